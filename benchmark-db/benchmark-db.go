@@ -3,11 +3,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/acronis/perfkit/benchmark"
 	embeddedpostgres "github.com/fergusstrange/embedded-postgres" // embedder postgres
@@ -16,8 +21,8 @@ import (
 // Version is a version of the benchmark-db
 var Version = "1-main-dev"
 
-func printVersion() {
-	fmt.Printf("Acronis Database Benchmark: version v%s\n", Version)
+func printVersion(w io.Writer) {
+	fmt.Fprintf(w, "Acronis Database Benchmark: version v%s\n", Version)
 }
 
 /*
@@ -38,23 +43,38 @@ type TestOpts struct {
 
 // BenchOpts is a structure to store all the benchmark options
 type BenchOpts struct {
-	Batch             int    `short:"b" long:"batch" description:"batch sets the amount of rows per transaction" required:"false" default:"0"`
-	Test              string `short:"t" long:"test" description:"select a test to execute, run --list to see available tests list" required:"false"`
-	List              bool   `short:"a" long:"list" description:"list available tests" required:"false"`
-	Cleanup           bool   `short:"C" long:"cleanup" description:"delete/truncate all test DB tables and exit"`
-	Init              bool   `short:"I" long:"init" description:"create all test DB tables and exit" `
-	RandSeed          int64  `short:"s" long:"randseed" description:"Seed used for random number generation" required:"false" default:"1"`
-	Chunk             int    `short:"u" long:"chunk" description:"chunk size for 'all' test" required:"false" default:"500000"`
-	Limit             int    `short:"U" long:"limit" description:"total rows limit for 'all' test" required:"false" default:"2000000"`
-	Info              bool   `short:"i" long:"info" description:"provide information about tables & indexes" required:"false"`
-	Events            bool   `short:"e" long:"events" description:"simulate event generation for every new object" required:"false"`
-	TenantsWorkingSet int    `long:"tenants-working-set" description:"set tenants working set" required:"false" default:"10000"`
-	CTIsWorkingSet    int    `long:"ctis-working-set" description:"set CTI working set" required:"false" default:"1000"`
-	ProfilerPort      int    `long:"profiler-port" description:"open profiler on given port (e.g. 6060)" required:"false" default:"0"`
-	Describe          bool   `long:"describe" description:"describe what test is going to do" required:"false"`
-	DescribeAll       bool   `long:"describe-all" description:"describe all the tests" required:"false"`
-	Explain           bool   `long:"explain" description:"prepend the test queries by EXPLAIN ANALYZE" required:"false"`
-	Query             string `short:"q" long:"query" description:"execute given query, one can use:\n{CTI} - for random CTI UUID\n{TENANT} - randon tenant UUID"`
+	Batch             int     `short:"b" long:"batch" description:"batch sets the amount of rows per transaction" required:"false" default:"0"`
+	Test              string  `short:"t" long:"test" description:"select a test to execute, run --list to see available tests list" required:"false"`
+	List              bool    `short:"a" long:"list" description:"list available tests" required:"false"`
+	Cleanup           bool    `short:"C" long:"cleanup" description:"delete/truncate all test DB tables and exit"`
+	Init              bool    `short:"I" long:"init" description:"create all test DB tables and exit" `
+	RandSeed          int64   `short:"s" long:"randseed" description:"Seed used for random number generation" required:"false" default:"1"`
+	Chunk             int     `short:"u" long:"chunk" description:"chunk size for 'all' test" required:"false" default:"500000"`
+	Limit             int     `short:"U" long:"limit" description:"total rows limit for 'all' test" required:"false" default:"2000000"`
+	Info              bool    `short:"i" long:"info" description:"provide information about tables & indexes" required:"false"`
+	Events            bool    `short:"e" long:"events" description:"simulate event generation for every new object" required:"false"`
+	TenantsWorkingSet int     `long:"tenants-working-set" description:"set tenants working set" required:"false" default:"10000"`
+	CTIsWorkingSet    int     `long:"ctis-working-set" description:"set CTI working set" required:"false" default:"1000"`
+	ProfilerPort      int     `long:"profiler-port" description:"open profiler on given port (e.g. 6060)" required:"false" default:"0"`
+	Describe          bool    `long:"describe" description:"describe what test is going to do" required:"false"`
+	DescribeAll       bool    `long:"describe-all" description:"describe all the tests" required:"false"`
+	Explain           bool    `long:"explain" description:"prepend the test queries by EXPLAIN ANALYZE" required:"false"`
+	ExplainOutput     string  `long:"explain-output" description:"directory to save the first query plan of every test to as <test>.json with --explain (postgres and mysql)" required:"false"`
+	Query             string  `short:"q" long:"query" description:"execute given query, one can use:\n{CTI} - for random CTI UUID\n{TENANT} - randon tenant UUID"`
+	TypeMap           string  `long:"type-map" description:"JSON file overriding physical column types per DB driver, e.g. {\"postgres\": {\"datetime\": \"TIMESTAMPTZ\"}}" required:"false"`
+	BatchSweep        string  `long:"batch-sweep" description:"comma-separated batch sizes to run the selected insert test with one by one, reporting the rate of every batch size and the best one (e.g. 100,500,1000,5000)" required:"false"`
+	RepeatTest        int     `long:"repeat-test" description:"run every selected test given amount of times and report the coefficient of variation of the rate and p99 across the runs" required:"false" default:"1"`
+	UnstableCV        float64 `long:"unstable-cv" description:"coefficient of variation (%) of the rate or p99 across --repeat-test runs above which the test is flagged as unstable" required:"false" default:"10"`
+	DataLocale        string  `long:"data-locale" description:"character set of the generated strings: ascii|ja|zh|ko|emoji|mixed, string sizes are in characters" required:"false" default:"ascii"`
+	ReportSizes       bool    `long:"report-sizes" description:"report table and every index size after every --chunk of the 'all' test, in text and JSON" required:"false"`
+	ReportWAL         bool    `long:"report-wal" description:"report write-ahead log (redo log on mysql, transaction log on mssql) bytes generated by the test in total and per loop" required:"false"`
+	GeomeansFile      string  `long:"geomeans-file" description:"JSON file accumulating the 'all' test category geomeans per DB driver across runs, e.g. {\"postgres\": {\"select\": 1200}}" required:"false"`
+	BaselineDialect   string  `long:"baseline-dialect" description:"normalize the 'all' test category geomeans of every DB driver found in --geomeans-file against given driver (e.g. postgres = 1.0)" required:"false"`
+	CapWorkers        bool    `long:"cap-workers" description:"cap --workers by the free connections of the DB server (max connections minus the ones in use) instead of just warning" required:"false"`
+	ReportBlobStorage bool    `long:"report-blob-storage" description:"report the raw and the stored (compressed) size of the 'blob' table data after the 'insert-blob' and 'copy-blob' tests, see --blob-compression" required:"false"`
+	OutputFormat      string  `long:"output-format" description:"format of the test results: text | json (per-test results, per-category geomeans and the run metadata in a single document printed at the end)" required:"false" default:"text"`
+	OutputFile        string  `long:"output-file" description:"write the --output-format=json document to given file instead of stdout, otherwise the human-readable output goes to stderr to keep stdout parseable" required:"false"`
+	Warmup            string  `long:"warmup" description:"unmeasured warmup of every worker before the measured window of every test: N - worker iterations, or a duration (e.g. 5s); it's done on top of --duration or --loops and is counted neither in the rate nor in the geomean" required:"false"`
 }
 
 // CTIOpts is a structure to store all the CTI options
@@ -64,8 +84,45 @@ type CTIOpts struct {
 
 // TestcaseOpts is a structure to store all the test case options
 type TestcaseOpts struct {
-	MinBlobSize int `long:"min-blob-size" description:"defines min blob size for the 'insert-blob' test (default 0)" required:"false" default:"0"`
-	MaxBlobSize int `long:"max-blob-size" description:"defines max blob size for the 'insert-blob' test (default 52428800)" required:"false" default:"52428800"`
+	MinBlobSize       int    `long:"min-blob-size" description:"defines min blob size for the 'insert-blob' test (default 0)" required:"false" default:"0"`
+	MaxBlobSize       int    `long:"max-blob-size" description:"defines max blob size for the 'insert-blob' test (default 52428800)" required:"false" default:"52428800"`
+	PayloadSizes      string `long:"payload-sizes" description:"comma-separated payload size bands (bytes) for the 'insert-growing-payload' test" required:"false" default:"128,512,1024,2048,4096,8192,16384,65536"`
+	PgParamTypes      string `long:"pg-param-types" description:"postgres parameter type hints for the prepared insert tests: 'default' or column-type:pg-type pairs overriding defaults (e.g. int:int4,uuid:uuid)" required:"false"`
+	HeavyExtraColumns int    `long:"heavy-extra-columns" description:"widen the 'heavy' table by given number of extra int and varchar columns" required:"false" default:"0"`
+	DecimalPrecision  int    `long:"decimal-precision" description:"precision (total digits) of the decimal column in the 'money' table" required:"false" default:"18"`
+	DecimalScale      int    `long:"decimal-scale" description:"scale (fraction digits) of the decimal column in the 'money' table" required:"false" default:"2"`
+	InsertKeyOrder    string `long:"insert-key-order" description:"primary key order of the insert tests: sequential (monotonic int, generated by the database if the key is autoincremented) | random (UUID v4 or random int) | uuid (time-ordered UUID v7 or nanoseconds int), the index sizes are reported with --report-wal" required:"false" default:"sequential"`
+	UUIDVersion       int    `long:"uuid-version" description:"UUID version of the primary key of the 'insert-uuid-pk' and 'select-by-uuid-range' tests: 4 (random) | 7 (time-ordered)" required:"false" default:"4"`
+	CopyCommitRows    int    `long:"copy-commit-rows" description:"commit the 'copy-*' tests every given amount of rows, splitting the --batch rows into several COPY statements and transactions (0 - single transaction per batch)" required:"false" default:"0"`
+	QueueJobs         int    `long:"queue-jobs" description:"amount of pending jobs to enqueue before the 'queue-consume' test" required:"false" default:"100000"`
+	TenantIsolation   string `long:"tenant-isolation" description:"tenant isolation for the 'tenant-isolation-insert-select' test: column (shared table with tenant_id) | schema (schema/database per tenant)" required:"false" default:"column"`
+	TenantSchemas     int    `long:"tenant-schemas" description:"amount of tenant schemas (postgres) or databases (mysql) in --tenant-isolation=schema mode" required:"false" default:"10"`
+	TSLatestDevices   int    `long:"ts-latest-devices" description:"amount of devices in the 'upsert-ts-latest' test keyspace, smaller keyspace means higher conflict rate" required:"false" default:"1000"`
+	TSLatestMetrics   int    `long:"ts-latest-metrics" description:"amount of metrics per device in the 'upsert-ts-latest' test keyspace" required:"false" default:"10"`
+	TSRecentShare     int    `long:"ts-recent-share" description:"percentage of the 'select-ts-sql-recency-skewed' test queries reading the last hour window, the rest read older windows" required:"false" default:"80"`
+	TSHistoryDays     int    `long:"ts-history-days" description:"max age (days) of the window read by the 'select-ts-sql-recency-skewed' test" required:"false" default:"30"`
+	TSRollingWindow   int    `long:"ts-rolling-window" description:"length (minutes) of the time window read by the 'select-ts-sql-rolling-window' test" required:"false" default:"60"`
+	TSBucketSeconds   int    `long:"ts-bucket-seconds" description:"length (seconds) of the time buckets the 'select-ts-sql-rolling-window' test aggregates the values by" required:"false" default:"60"`
+	TenantSpreads     string `long:"tenant-spreads" description:"comma-separated amounts of distinct tenants the rows of every batch are spread across in the 'insert-heavy-multivalue-many-tenants' test" required:"false" default:"1,10,100,500"`
+	PreparedStmts     int    `long:"prepared-statements" description:"amount of distinct statements prepared in a single session by the 'prepared-statements-footprint' test" required:"false" default:"5000"`
+	RemoteCatalog     string `long:"remote-catalog" description:"remote catalog of the 'select-cross-catalog-join' test: postgres_fdw database (default - loopback to the current one), MSSQL database or ClickHouse 'host:port'" required:"false"`
+	CounterKeyspaces  string `long:"counter-keyspaces" description:"comma-separated key space sizes of the 'upsert-counter' test, smaller key space means higher contention" required:"false" default:"10000,1000,100,10"`
+	UpsertHitRatio    int    `long:"upsert-hit-ratio" description:"percentage of the 'upsert-medium' test upserts hitting an existing uuid (the update path), the rest insert new uuids" required:"false" default:"50"`
+	PoolSizes         string `long:"pool-sizes" description:"comma-separated sizes of the connections pool shared by all the workers of the 'prepared-pool-contention' test" required:"false" default:"1,2,4,8"`
+	HotRowWorkers     string `long:"hot-row-workers" description:"comma-separated amounts of workers updating the same row in the 'update-single-hot-row' test" required:"false" default:"1,2,4,8,16,32"`
+	DecoupleGen       bool   `long:"decouple-gen" description:"generate the rows of the 'insert-*' tests in producer goroutines feeding a buffered channel drained by the INSERT workers, so the data generation is not on the INSERT critical path" required:"false"`
+	ConsistencyPairs  string `long:"consistency-pairs" description:"comma-separated write:read cassandra consistency level pairs for the 'read-after-write-consistency' test" required:"false" default:"ONE:ONE,ONE:QUORUM,QUORUM:QUORUM,ONE:ALL"`
+	CancelDelay       int    `long:"cancel-delay" description:"delay (msec) between the start of the expensive aggregate and its cancellation in the 'cancel-propagation' test" required:"false" default:"100"`
+	CheckViolations   int    `long:"check-violations" description:"percentage of the rows with the out-of-range 'progress' value inserted by the 'insert-heavy-check-constraint' test" required:"false" default:"1"`
+	LikePattern       string `long:"like-pattern" description:"pattern searched by the 'select-heavy-rand-in-tenant-like' test (default - the first letter of the --data-locale)" required:"false"`
+	LikePosition      string `long:"like-position" description:"position of the --like-pattern in the matched string: prefix (k%, can use an index) | suffix (%k) | infix (%k%)" required:"false" default:"infix"`
+	CountMode         string `long:"count-mode" description:"row count mode of the 'select-heavy-count' test: exact (COUNT(*) scan) | estimate (planner statistics on postgres and mssql, exact elsewhere)" required:"false" default:"exact"`
+
+	TablePersistence string   `long:"table-persistence" description:"defines the 'heavy' table persistence: logged|unlogged|temp (unlogged - postgres only, temp - postgres and mysql)" required:"false" default:"logged"`
+	ClickHouseCodec  []string `long:"clickhouse-codec" description:"compression codec of a column of the 'heavy' and 'timeseries' tables on clickhouse in column=codec form, can be repeated (e.g. --clickhouse-codec ts=Delta,ZSTD --clickhouse-codec value=Gorilla)" required:"false"`
+	BlobCompression  string   `long:"blob-compression" description:"compression of the 'blob' table data: postgres: pglz|lz4 (TOAST), mysql: none|zlib|lz4 (InnoDB page compression), mssql: none|row|page" required:"false"`
+	BlobCompressible int      `long:"blob-compressible" description:"compressible share (%) of every blob of the 'blob' table, filled by a repeated text, the rest is random" required:"false" default:"0"`
+	CheckConstraints bool     `long:"with-check-constraints" description:"create the 'heavy' table with the CHECK (progress BETWEEN 0 AND 100) constraint (mysql 8.0.16+ enforces it, older versions ignore it)" required:"false"`
 }
 
 // DBTestData is a structure to store all the test data
@@ -73,23 +130,464 @@ type DBTestData struct {
 	TestDesc         *TestDesc
 	EventBus         *EventBus
 	EmbeddedPostgres *embeddedpostgres.EmbeddedPostgres
-	EffectiveBatch   int // EffectiveBatch reflects the default value if the --batch option is not set, it can be different for different tests
-
-	scores map[string][]benchmark.Score
+	EffectiveBatch   int                       // EffectiveBatch reflects the default value if the --batch option is not set, it can be different for different tests
+	TypeMap          map[string]string         // TypeMap maps logical column types to physical types of the current DB driver, see --type-map
+	PgParamTypes     map[string]string         // PgParamTypes maps fake column types to postgres parameter type hints, see --pg-param-types
+	CopyPeakTxRows   uint64                    // CopyPeakTxRows is the max amount of rows copied in a single transaction, see --copy-commit-rows
+	ReadReplicas     []*benchmark.DatabaseOpts // ReadReplicas are the database options of every read replica, see --read-replicas
+	Emulation        string                    // Emulation describes the fallback the current test runs with, see TestDesc.fallbacks
+	WALStart         int64                     // WALStart is the WAL position the current test run started at, see --report-wal
+	ClickHouseCodecs map[string]string         // ClickHouseCodecs maps the column names to compression codecs, see --clickhouse-codec
+	Results          *resultsDocument          // Results collects the test results if --output-format=json is set
+	Out              io.Writer                 // Out is the human-readable output, stderr if the JSON results go to stdout
+
+	scores   map[string][]benchmark.Score
+	counters []testCounters // counters of the current test, see newTestCounters()
 }
 
 // DBWorkerData is a structure to store all the worker data
 type DBWorkerData struct {
-	conn *benchmark.DBConnector
+	conn    *benchmark.DBConnector
+	replica int // index of the read replica the worker is connected to, -1 for the primary
 }
 
 var header = strings.Repeat("=", 120) + "\n"
 
-func main() {
-	fmt.Printf(header) //nolint:staticcheck
+// printPoolAcquireWaits prints the time spent waiting for a free connection in the workers' pools separately from the query time
+func printPoolAcquireWaits(b *benchmark.Benchmark, score benchmark.Score) {
+	var waits []time.Duration
+	var total time.Duration
+
+	for _, wd := range b.WorkerData {
+		if wd == nil {
+			continue
+		}
+		for _, w := range wd.(*DBWorkerData).conn.TakeAcquireWaits() {
+			waits = append(waits, w)
+			total += w
+		}
+	}
+
+	if len(waits) == 0 || score.Loops == 0 {
+		return
+	}
+
+	loopTime := time.Duration(score.Seconds * float64(score.Workers) * float64(time.Second) / float64(score.Loops))
+	loopWait := total / time.Duration(score.Loops)
+
+	fmt.Fprintf(out(b), "pool acquire wait: acquisitions: %d; avg: %.3f ms; p99: %.3f ms; per loop: %.3f ms; query time per loop: %.3f ms\n",
+		len(waits), float64(total)/float64(len(waits))/float64(time.Millisecond), float64(benchmark.Percentile(waits, 99))/float64(time.Millisecond),
+		float64(loopWait)/float64(time.Millisecond), float64(loopTime-loopWait)/float64(time.Millisecond))
+}
+
+// printRePrepares prints the amount of prepared statements re-prepared after a schema change (e.g. by online DDL)
+func printRePrepares(b *benchmark.Benchmark) {
+	total := 0
+	for _, wd := range b.WorkerData {
+		if wd == nil {
+			continue
+		}
+		total += wd.(*DBWorkerData).conn.TakeRePrepares()
+	}
+
+	if total > 0 {
+		fmt.Fprintf(out(b), "prepared statements re-prepared after schema change: %d\n", total)
+	}
+}
+
+// takeTxRetries returns the amount of transactions retried after a transient error (see --tx-max-retries) since the last call
+func takeTxRetries(b *benchmark.Benchmark) int {
+	total := 0
+	for _, wd := range b.WorkerData {
+		if wd == nil {
+			continue
+		}
+		total += wd.(*DBWorkerData).conn.TakeTxRetries()
+	}
+
+	return total
+}
+
+// printTxRetries prints the amount of transactions retried after a transient error and the amount of retries per loop
+func printTxRetries(b *benchmark.Benchmark, retries int, score benchmark.Score) {
+	if retries == 0 {
+		return
+	}
+
+	var perLoop float64
+	if score.Loops > 0 {
+		perLoop = float64(retries) / float64(score.Loops)
+	}
+
+	fmt.Fprintf(out(b), "transactions retried after transient errors: %d (%.4f per loop)\n", retries, perLoop)
+}
+
+// printReconnects prints the amount of lost DB connections re-established during the test (see --reconnect-on-loss)
+func printReconnects(b *benchmark.Benchmark) {
+	if n := benchmark.TakeReconnects(); n > 0 {
+		fmt.Fprintf(out(b), "DB connections re-established after loss: %d\n", n)
+	}
+}
+
+// printInjectedFaults prints the amount of artificial errors and connection drops (see --inject-error-rate, --inject-drop-rate)
+func printInjectedFaults(b *benchmark.Benchmark) {
+	errs, drops := 0, 0
+	for _, wd := range b.WorkerData {
+		if wd == nil {
+			continue
+		}
+		e, d := wd.(*DBWorkerData).conn.TakeInjectedFaults()
+		errs += e
+		drops += d
+	}
+
+	if errs > 0 || drops > 0 {
+		fmt.Fprintf(out(b), "injected faults: %d errors, %d connection drops\n", errs, drops)
+	}
+}
+
+// markWALStart remembers the WAL position the test run starts at, see --report-wal
+func markWALStart(b *benchmark.Benchmark) {
+	switch getDBDriver(b) {
+	case benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL:
+	default:
+		b.Exit("--report-wal is supported for postgres, mysql and mssql only")
+	}
+
+	c := scratchConnector(b)
+	defer c.Close()
+
+	b.Vault.(*DBTestData).WALStart = c.GetWALBytes()
+}
+
+// testResult is the result of a single test run in the --output-format=json document
+type testResult struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Emulated bool    `json:"emulated,omitempty"`
+	Metric   string  `json:"metric"`
+	Workers  int     `json:"workers"`
+	Batch    int     `json:"batch"`
+	Loops    uint64  `json:"loops"`
+	Seconds  float64 `json:"duration_sec"`
+	Rate     float64 `json:"rate"`
+	Retries  int     `json:"tx_retries"` // transactions retried after a transient error, see --tx-max-retries
+}
+
+// resultsDocument is the --output-format=json document
+type resultsDocument struct {
+	Driver   string             `json:"dialect"`
+	Version  string             `json:"version"`
+	Workers  int                `json:"workers"`
+	Batch    int                `json:"batch"` // 0 - the test default batch
+	Tests    []testResult       `json:"tests"`
+	Geomeans map[string]float64 `json:"geomeans"` // per-category geomean of the test rates
+}
+
+// add appends the result of the current test run
+func (r *resultsDocument) add(b *benchmark.Benchmark, score benchmark.Score, retries int) {
+	testData := b.Vault.(*DBTestData)
+
+	r.Tests = append(r.Tests, testResult{
+		Name:     testData.TestDesc.name,
+		Category: testData.TestDesc.category,
+		Emulated: testData.Emulation != "",
+		Metric:   score.Metric,
+		Workers:  score.Workers,
+		Batch:    testData.EffectiveBatch,
+		Loops:    score.Loops,
+		Seconds:  score.Seconds,
+		Rate:     score.Rate,
+		Retries:  retries,
+	})
+}
+
+// writeResults writes the --output-format=json document to the --output-file or stdout
+func writeResults(b *benchmark.Benchmark) {
+	testData := b.Vault.(*DBTestData)
+
+	testData.Results.Geomeans = make(map[string]float64)
+	for category, scores := range testData.scores {
+		if len(scores) > 0 {
+			testData.Results.Geomeans[category] = b.Geomean(scores)
+		}
+	}
+
+	out, err := json.MarshalIndent(testData.Results, "", "  ")
+	if err != nil {
+		b.Exit("can't marshal the test results: %v", err)
+	}
+
+	path := b.TestOpts.(*TestOpts).BenchOpts.OutputFile
+	if path == "" {
+		fmt.Printf("%s\n", out)
+
+		return
+	}
+
+	if err = os.WriteFile(path, append(out, '\n'), 0o644); err != nil {
+		b.Exit("can't write the test results: %v", err)
+	}
+}
+
+// printWAL prints the amount of WAL bytes generated since the test run start in total and per loop, e.g. per inserted row
+func printWAL(b *benchmark.Benchmark, score benchmark.Score) {
+	testData := b.Vault.(*DBTestData)
+
+	c := scratchConnector(b)
+	defer c.Close()
+
+	end := c.GetWALBytes()
+	start := testData.WALStart
+	// the next --repeat run starts here
+	testData.WALStart = end
+
+	if start < 0 || end < 0 {
+		fmt.Fprintf(out(b), "WAL: n/a, can't read the WAL position of the '%s' database, insufficient privileges or a replica?\n", c.DbOpts.Driver)
+
+		return
+	}
+
+	perLoop := 0.0
+	if score.Loops > 0 {
+		perLoop = float64(end-start) / float64(score.Loops)
+	}
+
+	fmt.Fprintf(out(b), "WAL: %d bytes; %.1f bytes per loop\n", end-start, perLoop)
+}
+
+// printIndexSizes prints the size of the test table data and of every its index per row, so the index bloat caused by
+// the primary key order of the insert tests can be compared, see --insert-key-order
+func printIndexSizes(b *benchmark.Benchmark) {
+	table := b.Vault.(*DBTestData).TestDesc.table.TableName
+
+	c := scratchConnector(b)
+	defer c.Close()
+
+	dataBytes, indexBytes := c.GetTableAndIndexSizes(table)
+	if dataBytes < 0 {
+		fmt.Fprintf(out(b), "index sizes: n/a, not supported by the '%s' database\n", c.DbOpts.Driver)
+
+		return
+	}
+
+	rows := c.GetRowsCount(table, "")
+	perRow := func(size int64) float64 {
+		if rows == 0 {
+			return 0
+		}
+
+		return float64(size) / float64(rows)
+	}
+
+	names := make([]string, 0, len(indexBytes))
+	for name := range indexBytes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(out(b), "table size: %d bytes; %.1f bytes per row; rows: %d\n", dataBytes, perRow(dataBytes), rows)
+	for _, name := range names {
+		fmt.Fprintf(out(b), "index size: %s: %d bytes; %.1f bytes per row\n", name, indexBytes[name], perRow(indexBytes[name]))
+	}
+}
+
+// printClickHouseColumnSizes prints the codec and the compressed/uncompressed size of every column of the test table
+// and the table size on disk, so the --clickhouse-codec choices can be compared
+func printClickHouseColumnSizes(b *benchmark.Benchmark) {
+	table := b.Vault.(*DBTestData).TestDesc.table.TableName
+
+	c := scratchConnector(b)
+	defer c.Close()
+
+	rows, err := c.Query(fmt.Sprintf(`SELECT name, compression_codec, data_compressed_bytes, data_uncompressed_bytes
+		FROM system.columns WHERE database = currentDatabase() AND table = '%s' ORDER BY position`, table))
+	if err != nil {
+		b.Exit("can't read the '%s' table columns sizes: %v", table, err)
+	}
+	defer rows.Close()
+
+	fmt.Fprintf(out(b), "%-30s %-30s %15s %15s %7s\n", "COLUMN", "CODEC", "COMPRESSED", "UNCOMPRESSED", "RATIO")
+
+	for rows.Next() {
+		var name, codec string
+		var compressed, uncompressed uint64
+		if err = rows.Scan(&name, &codec, &compressed, &uncompressed); err != nil {
+			b.Exit("can't scan the '%s' table columns sizes: %v", table, err)
+		}
+
+		ratio := 0.0
+		if compressed > 0 {
+			ratio = float64(uncompressed) / float64(compressed)
+		}
+		if codec == "" {
+			codec = "(default)"
+		}
+
+		fmt.Fprintf(out(b), "%-30s %-30s %15d %15d %7.2f\n", name, codec, compressed, uncompressed, ratio)
+	}
+
+	var onDisk uint64
+	c.QueryRowAndScan(fmt.Sprintf("SELECT sum(bytes_on_disk) FROM system.parts WHERE database = currentDatabase() AND table = '%s' AND active", table), &onDisk)
+
+	fmt.Fprintf(out(b), "table '%s' size on disk: %d bytes\n", table, onDisk)
+}
+
+// printBlobStorage prints the raw size of the test table blobs and the size the server stores them in, see --report-blob-storage
+func printBlobStorage(b *benchmark.Benchmark) {
+	table := b.Vault.(*DBTestData).TestDesc.table.TableName
+
+	c := scratchConnector(b)
+	defer c.Close()
+
+	raw, stored := c.GetBlobStorage(table, "data")
 
-	printVersion()
+	compression := b.TestOpts.(*TestOpts).TestcaseOpts.BlobCompression
+	if compression == "" {
+		compression = "default"
+	}
+
+	scope := "table"
+	if c.DbOpts.Driver == benchmark.POSTGRES {
+		scope = "column"
+	}
+
+	if raw < 0 || stored < 0 {
+		fmt.Fprintf(out(b), "blob storage: n/a, can't read the '%s' table storage size of the '%s' database\n", table, c.DbOpts.Driver)
+
+		return
+	}
+
+	ratio := 0.0
+	if stored > 0 {
+		ratio = float64(raw) / float64(stored)
+	}
+
+	fmt.Fprintf(out(b), "blob storage: %s; compression: %s; compressible: %d%%; raw: %d bytes; stored (%s): %d bytes; ratio: %.2f\n", c.DbOpts.Driver,
+		compression, b.TestOpts.(*TestOpts).TestcaseOpts.BlobCompressible, raw, scope, stored, ratio)
+}
+
+// checkConnectionsLimit warns if the workers may open more connections than the DB server allows, or caps the workers, see --cap-workers
+func checkConnectionsLimit(b *benchmark.Benchmark, c *benchmark.DBConnector) {
+	maxConns, usedConns := c.GetConnectionsLimit()
+	if maxConns < 0 || usedConns < 0 {
+		return
+	}
+
+	dbOpts := b.TestOpts.(*TestOpts).DBOpts
+	perWorker := dbOpts.MaxOpenConns
+	if perWorker <= 0 {
+		perWorker = 1
+	}
+	if dbOpts.ConnPerWorker {
+		// the pooled connections of the first run stay open while the pinned run opens one more per worker
+		perWorker++
+	}
+
+	// the connection of this session is counted already, but the tests open one more for their setup
+	free := int(maxConns-usedConns) - 1
+	workers := b.CommonOpts.Workers
+	if workers*perWorker <= free {
+		return
+	}
+
+	if !b.TestOpts.(*TestOpts).BenchOpts.CapWorkers {
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("%d workers may open up to %d connections (%d per worker, see --maxopencons), "+
+			"but the server allows only %d more (max %d, in use %d), use --cap-workers to cap the workers", workers, workers*perWorker, perWorker,
+			free, maxConns, usedConns))
+
+		return
+	}
+
+	capped := free / perWorker
+	if capped < 1 {
+		b.Exit("no free connections on the server to run the workers: max %d, in use %d", maxConns, usedConns)
+	}
+
+	fmt.Fprintf(out(b), "Workers: capped from %d to %d by the server connections limit (max %d, in use %d, %d per worker)\n",
+		workers, capped, maxConns, usedConns, perWorker)
+	b.CommonOpts.Workers = capped
+}
+
+// parseWarmup parses the --warmup option, it's either the amount of worker iterations or a duration
+func parseWarmup(b *benchmark.Benchmark) {
+	warmup := b.TestOpts.(*TestOpts).BenchOpts.Warmup
+	if warmup == "" {
+		return
+	}
+
+	if loops, err := strconv.Atoi(warmup); err == nil && loops >= 0 {
+		b.Warmup = benchmark.Warmup{Loops: loops}
+
+		return
+	}
+
+	if duration, err := time.ParseDuration(warmup); err == nil && duration >= 0 {
+		b.Warmup = benchmark.Warmup{Duration: duration}
+
+		return
+	}
+
+	b.Exit("invalid --warmup=%s, the amount of iterations (e.g. 100) or a duration (e.g. 5s) is expected", warmup)
+}
+
+// checkPgBouncerMode resolves the --pgbouncer-mode hint and disables the features the PgBouncer pool mode breaks
+func checkPgBouncerMode(b *benchmark.Benchmark, c *benchmark.DBConnector) {
+	dbOpts := &b.TestOpts.(*TestOpts).DBOpts
+
+	mode := dbOpts.PgBouncerMode
+	switch mode {
+	case "":
+		return
+	case benchmark.PgBouncerSession, benchmark.PgBouncerTransaction, benchmark.PgBouncerStatement, benchmark.PgBouncerAuto:
+	default:
+		b.Exit("unsupported --pgbouncer-mode '%s', supported values are: %s", mode, benchmark.SupportedPgBouncerModes)
+	}
+
+	if dbOpts.Driver != benchmark.POSTGRES {
+		b.Exit("--pgbouncer-mode is supported for postgres only")
+	}
+
+	if mode == benchmark.PgBouncerAuto {
+		var err error
+		if mode, err = c.DetectPgBouncerMode(); err != nil {
+			b.Exit("can't detect the PgBouncer pool mode: %v", err)
+		}
+		dbOpts.PgBouncerMode = mode
+		fmt.Fprintf(out(b), "PgBouncer pool mode: %s (detected, a direct connection is reported as session)\n", mode)
+	} else {
+		fmt.Fprintf(out(b), "PgBouncer pool mode: %s\n", mode)
+	}
+
+	if mode == benchmark.PgBouncerSession {
+		return
+	}
+
+	// server-side prepared statements live in the server session, which is not pinned to the client one in these modes
+	if dbOpts.PgProtocol == benchmark.PgProtocolPrepared {
+		b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--pg-protocol=%s is not supported by PgBouncer in %s pool mode, falling back to %s",
+			benchmark.PgProtocolPrepared, mode, benchmark.PgProtocolExtended))
+		dbOpts.PgProtocol = benchmark.PgProtocolExtended
+	}
+
+	if b.TestOpts.(*TestOpts).TestcaseOpts.TablePersistence == TablePersistenceTemp {
+		b.Exit("--table-persistence=%s is not supported by PgBouncer in %s pool mode", TablePersistenceTemp, mode)
+	}
+}
+
+// checkIsolation warns that the write tests are skipped if the database doesn't support the --isolation level,
+// running them at the database default level would silently report the numbers of another level
+func checkIsolation(b *benchmark.Benchmark) {
+	dbOpts := &b.TestOpts.(*TestOpts).DBOpts
+	if dbOpts.SupportsIsolation() {
+		return
+	}
 
+	b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--isolation=%s is not supported by the '%s' database, the write tests are skipped",
+		dbOpts.Isolation, dbOpts.DialectName()))
+}
+
+func main() {
 	b := benchmark.New()
 
 	b.AddOpts = func() benchmark.TestOpts {
@@ -121,8 +619,53 @@ func main() {
 			format = "test: %s; rows-before-test: %d; time: %.1f sec; workers: %d; loops: %d; batch: %4d; rate: %s %s\n"
 		}
 
-		fmt.Printf(format, testData.TestDesc.name, testData.TestDesc.table.RowsCount, score.Seconds, score.Workers, score.Loops,
-			b.Vault.(*DBTestData).EffectiveBatch, score.FormatRate(4), score.Metric)
+		name := testData.TestDesc.name
+		if testData.Emulation != "" {
+			name += " (EMULATED)"
+		}
+
+		retries := takeTxRetries(b)
+
+		if testData.Results != nil {
+			testData.Results.add(b, score, retries)
+		} else {
+			fmt.Fprintf(out(b), format, name, testData.TestDesc.table.RowsCount, score.Seconds, score.Workers, score.Loops,
+				b.Vault.(*DBTestData).EffectiveBatch, score.FormatRate(4), score.Metric)
+		}
+
+		if persistence := testData.TestDesc.table.persistence(b); persistence != TablePersistenceLogged {
+			fmt.Fprintf(out(b), "WARNING: NON-DURABLE RESULT, the '%s' table is %s\n", testData.TestDesc.table.TableName, persistence)
+		}
+
+		if b.TestOpts.(*TestOpts).DBOpts.PoolAcquireTimeout > 0 {
+			printPoolAcquireWaits(b, score)
+		}
+
+		printReadReplicaRates(b, score)
+
+		if b.TestOpts.(*TestOpts).DBOpts.PgProtocol == benchmark.PgProtocolPrepared {
+			printRePrepares(b)
+		}
+
+		printTxRetries(b, retries, score)
+		printInjectedFaults(b)
+		printReconnects(b)
+
+		if b.TestOpts.(*TestOpts).BenchOpts.ReportWAL {
+			printWAL(b, score)
+
+			if testData.TestDesc.category == TestInsert && testData.TestDesc.table.TableName != "" {
+				printIndexSizes(b)
+			}
+		}
+
+		if len(testData.ClickHouseCodecs) > 0 && testData.TestDesc.table.CodecConfigurable {
+			printClickHouseColumnSizes(b)
+		}
+
+		if b.TestOpts.(*TestOpts).BenchOpts.ReportBlobStorage && testData.TestDesc.table.CompressionConfigurable {
+			printBlobStorage(b)
+		}
 	}
 
 	b.InitOpts()
@@ -131,8 +674,10 @@ func main() {
 	if !ok {
 		b.Exit("db type conversion error")
 	}
+	testOpts.DBOpts.ResolveDialect()
+	b.ReconnectOnLoss = testOpts.DBOpts.ReconnectOnLoss > 0
 
-	d := DBTestData{}
+	d := DBTestData{Out: os.Stdout}
 	b.Vault = &d
 
 	d.scores = make(map[string][]benchmark.Score)
@@ -141,6 +686,21 @@ func main() {
 		d.scores[s] = []benchmark.Score{}
 	}
 
+	switch testOpts.BenchOpts.OutputFormat {
+	case "text":
+	case "json":
+		d.Results = &resultsDocument{Workers: b.CommonOpts.Workers, Batch: testOpts.BenchOpts.Batch, Tests: []testResult{}}
+		if testOpts.BenchOpts.OutputFile == "" {
+			// the human-readable output goes to stderr, so stdout gets the JSON document
+			d.Out = os.Stderr
+		}
+	default:
+		b.Exit("unsupported --output-format '%s', supported values are: text|json", testOpts.BenchOpts.OutputFormat)
+	}
+
+	fmt.Fprintf(out(b), header) //nolint:staticcheck
+	printVersion(out(b))
+
 	if b.TestOpts.(*TestOpts).BenchOpts.Batch > 0 {
 		b.Vault.(*DBTestData).EffectiveBatch = b.TestOpts.(*TestOpts).BenchOpts.Batch
 	} else {
@@ -149,26 +709,26 @@ func main() {
 
 	if testOpts.BenchOpts.List {
 		groups, _ := GetTests()
-		fmt.Printf(header) //nolint:staticcheck
+		fmt.Fprintf(out(b), header) //nolint:staticcheck
 		for _, g := range groups {
 			str := fmt.Sprintf("  -- %s", g.name)
-			fmt.Printf("\n%s %s\n\n", str, strings.Repeat("-", 130-len(str)))
+			fmt.Fprintf(out(b), "\n%s %s\n\n", str, strings.Repeat("-", 130-len(str)))
 			var testsOutput []string
 			for _, t := range g.tests {
-				if testOpts.DBOpts.Driver != "" && !t.dbIsSupported(testOpts.DBOpts.Driver) {
+				if testOpts.DBOpts.Driver != "" && !t.dbIsSupported(testOpts.DBOpts.DialectName()) {
 					continue
 				}
 				testsOutput = append(testsOutput, fmt.Sprintf("  %-39s : %s : %s\n", t.name, t.getDBs(), t.description))
 			}
 			sort.Strings(testsOutput)
-			fmt.Print(strings.Join(testsOutput, ""))
+			fmt.Fprint(out(b), strings.Join(testsOutput, ""))
 		}
-		fmt.Printf("\n")
-		fmt.Printf("Databases symbol legend:\n\n ")
+		fmt.Fprintf(out(b), "\n")
+		fmt.Fprintf(out(b), "Databases symbol legend:\n\n ")
 		for _, db := range benchmark.GetDatabases() {
-			fmt.Printf(" %s - %s;", db.Symbol, db.Name)
+			fmt.Fprintf(out(b), " %s - %s;", db.Symbol, db.Name)
 		}
-		fmt.Printf("\n\n")
+		fmt.Fprintf(out(b), "\n\n")
 		b.Exit()
 	}
 
@@ -189,11 +749,45 @@ func main() {
 		b.Exit()
 	}
 
+	if err := benchmark.SetDataLocale(testOpts.BenchOpts.DataLocale); err != nil {
+		b.Exit(err.Error())
+	}
+
+	if dir := testOpts.BenchOpts.ExplainOutput; dir != "" {
+		if !testOpts.BenchOpts.Explain {
+			b.Exit("--explain-output requires --explain")
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil { //nolint:gosec
+			b.Exit("can't create --explain-output directory: %v", err)
+		}
+		if dialect := testOpts.DBOpts.DialectName(); dialect != benchmark.POSTGRES && dialect != benchmark.MYSQL {
+			b.Log(benchmark.LogWarn, 0, fmt.Sprintf("--explain-output is supported by postgres and mysql only, the '%s' plans are logged", dialect))
+		}
+	}
+
+	loadTypeMap(b)
+	loadPgParamTypes(b)
+	loadReadReplicas(b)
+	addHeavyExtraColumns(b)
+	loadClickHouseCodecs(b)
+
 	if testOpts.BenchOpts.Init {
 		createTables(b)
 		b.Exit()
 	}
 
+	checkTablePersistence(b)
+	checkBlobCompression(b)
+	checkCheckConstraints(b)
+	parseWarmup(b)
+
+	if testOpts.DBOpts.ConnPerWorker && (testOpts.BenchOpts.BatchSweep != "" || testOpts.BenchOpts.RepeatTest > 1) {
+		b.Exit("--conn-per-worker can't be combined with --batch-sweep or --repeat-test")
+	}
+	if testOpts.BenchOpts.BatchSweep != "" && testOpts.BenchOpts.RepeatTest > 1 {
+		b.Exit("--batch-sweep can't be combined with --repeat-test")
+	}
+
 	if testOpts.DBOpts.Reconnect {
 		b.PreWorker = func(workerId int) {
 			conn := b.WorkerData[workerId].(*DBWorkerData).conn
@@ -204,14 +798,38 @@ func main() {
 	c := dbConnector(b)
 
 	driver, version := c.GetVersion()
-	fmt.Printf("Connected to '%s' database: %s\n", driver, version)
-	fmt.Printf(header) //nolint:staticcheck
+	fmt.Fprintf(out(b), "Connected to '%s' database: %s\n", driver, version)
+	checkConnectionsLimit(b, c)
+	checkPgBouncerMode(b, c)
+	checkIsolation(b)
+	if d.Results != nil {
+		d.Results.Driver, d.Results.Version = testOpts.DBOpts.DialectName(), version
+	}
+	if driver == benchmark.POSTGRES {
+		fmt.Fprintf(out(b), "Postgres query protocol: %s\n", testOpts.DBOpts.PgProtocol)
+		if testOpts.TestcaseOpts.PgParamTypes != "" {
+			fmt.Fprintf(out(b), "Postgres parameter type hints: %s\n", testOpts.TestcaseOpts.PgParamTypes)
+		}
+	}
+	if testOpts.DBOpts.ConnPerWorker {
+		fmt.Fprintf(out(b), "Connections: every test runs with the connections pool and with single pinned connection per worker\n")
+	}
+	if replicas := len(d.ReadReplicas); replicas > 0 {
+		fmt.Fprintf(out(b), "Read replicas: %d (workers of read-only tests are spread across them in round-robin)\n", replicas)
+	}
+	if testOpts.DBOpts.SessionTimezone != "" {
+		fmt.Fprintf(out(b), "Session time zone: %s\n", testOpts.DBOpts.SessionTimezone)
+	}
+	if benchmark.DataLocale() != "ascii" {
+		fmt.Fprintf(out(b), "Data locale: %s\n", benchmark.DataLocale())
+	}
+	fmt.Fprintf(out(b), header) //nolint:staticcheck
 
 	content, dbInfo := c.GetInfo(version)
 
 	if testOpts.BenchOpts.Info || b.Logger.LogLevel > benchmark.LogInfo {
 		if testOpts.BenchOpts.Info {
-			fmt.Printf(getDBInfo(b, content)) //nolint:staticcheck
+			fmt.Fprintf(out(b), getDBInfo(b, content)) //nolint:staticcheck
 		}
 	}
 
@@ -226,8 +844,8 @@ func main() {
 				b.Exit("Failed to start profiler server: %v", err)
 			}
 		}()
-		fmt.Printf("running profiler endpoint @ http://localhost:%d/debug/pprof/\n", testOpts.BenchOpts.ProfilerPort)
-		fmt.Printf("to collect the profiler log run: go tool pprof 'http://localhost:%d/debug/pprof/profile?seconds=10'\n", testOpts.BenchOpts.ProfilerPort)
+		fmt.Fprintf(out(b), "running profiler endpoint @ http://localhost:%d/debug/pprof/\n", testOpts.BenchOpts.ProfilerPort)
+		fmt.Fprintf(out(b), "to collect the profiler log run: go tool pprof 'http://localhost:%d/debug/pprof/profile?seconds=10'\n", testOpts.BenchOpts.ProfilerPort)
 	}
 
 	b.Init = func() {
@@ -284,6 +902,10 @@ func main() {
 		b.Exit("either --test or --info options must be set\n")
 	}
 
+	if d.Results != nil {
+		writeResults(b)
+	}
+
 	b.Exit()
 }
 
@@ -294,10 +916,15 @@ func executeTests(b *benchmark.Benchmark, testOpts *TestOpts) {
 		b.Exit(fmt.Sprintf("Test: '%s' doesn't exist, see the list of available tests using --list option\n", testOpts.BenchOpts.Test))
 	}
 	test := tests[testOpts.BenchOpts.Test]
-	if !test.dbIsSupported(testOpts.DBOpts.Driver) {
+	if !test.dbIsSupported(testOpts.DBOpts.DialectName()) {
 		b.Exit(fmt.Sprintf("Test: '%s' doesn't support '%s' database\n", testOpts.BenchOpts.Test, testOpts.DBOpts.Driver))
 	}
-	test.launcherFunc(b, test)
+	if test == &TestBaseAll {
+		// every test of the group is repeated separately
+		test.launcherFunc(b, test)
+	} else {
+		executeOneTest(b, test)
+	}
 }
 
 func describeOne(b *benchmark.Benchmark, testDesc *TestDesc) {
@@ -307,19 +934,19 @@ func describeOne(b *benchmark.Benchmark, testDesc *TestDesc) {
 		b.Logger.LogLevel = benchmark.LogInfo
 	}
 
-	fmt.Printf("\n")
-	fmt.Printf(header) //nolint:staticcheck
-	fmt.Printf("Test:        %s\n", testDesc.name)
-	fmt.Printf("Metric:      %s\n", testDesc.metric)
-	fmt.Printf("Description: %s\n", testDesc.description)
-	fmt.Printf(header) //nolint:staticcheck
+	fmt.Fprintf(out(b), "\n")
+	fmt.Fprintf(out(b), header) //nolint:staticcheck
+	fmt.Fprintf(out(b), "Test:        %s\n", testDesc.name)
+	fmt.Fprintf(out(b), "Metric:      %s\n", testDesc.metric)
+	fmt.Fprintf(out(b), "Description: %s\n", testDesc.description)
+	fmt.Fprintf(out(b), header) //nolint:staticcheck
 
 	if testDesc.name == TestBaseAll.name {
-		fmt.Print("describe: run all the tests in a loop\n")
+		fmt.Fprint(out(b), "describe: run all the tests in a loop\n")
 	} else {
 		testDesc.launcherFunc(b, testDesc)
 	}
-	fmt.Printf("\n")
+	fmt.Fprintf(out(b), "\n")
 }
 
 func describeTest(b *benchmark.Benchmark, testOpts *TestOpts) {
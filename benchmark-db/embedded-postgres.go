@@ -67,6 +67,7 @@ func initEmbeddedPostgres(b *benchmark.Benchmark) {
 
 	if testOpts.DBOpts.EmbeddedPostgres {
 		testOpts.DBOpts.Driver = "postgres"
+		testOpts.DBOpts.Dialect = benchmark.POSTGRES
 		port := uint32(b.TestOpts.(*TestOpts).EmbeddedPostgresOpts.Port)
 		maxConnections := strconv.Itoa(b.TestOpts.(*TestOpts).EmbeddedPostgresOpts.MaxConnections)
 		testOpts.DBOpts.Dsn = fmt.Sprintf("host=localhost port=%d user=postgres password=postgres dbname=postgres sslmode=disable", port)
@@ -2,9 +2,14 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"regexp"
 	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
 
 	"github.com/acronis/perfkit/benchmark"
 )
@@ -19,15 +24,19 @@ func createTables(b *benchmark.Benchmark) {
 
 	_, tests := GetTests()
 	for _, t := range tests {
-		if t.table.TableName != "" && t.dbIsSupported(dbOpts.Driver) {
+		if t.table.TableName != "" && t.dbIsSupported(dbOpts.DialectName()) {
 			usedTables.Add(t.table.TableName)
 		}
 	}
 
-	fmt.Printf("creating the tables ... ")
+	fmt.Fprintf(out(b), "creating the tables ... ")
 
 	c := dbConnector(b)
 	for _, tableDesc := range TestTables {
+		if tableDesc.isTemporary(b) {
+			// temporary tables are created by every test worker in its own session
+			continue
+		}
 		if usedTables.Contains(tableDesc.TableName) {
 			tableDesc.Create(c, b)
 		}
@@ -39,23 +48,79 @@ func createTables(b *benchmark.Benchmark) {
 	eb := NewEventBus(&dbOpts, b.Logger)
 	eb.CreateTables()
 
-	fmt.Printf("done\n")
+	fmt.Fprintf(out(b), "done\n")
 }
 
 func dbConnector(b *benchmark.Benchmark) *benchmark.DBConnector {
 	return benchmark.NewDBConnector(&b.TestOpts.(*TestOpts).DBOpts, 0, b.Logger, 1)
 }
 
+// out returns the writer of the human-readable output, see DBTestData.Out
+func out(b *benchmark.Benchmark) io.Writer {
+	return b.Vault.(*DBTestData).Out
+}
+
+// scratchConnector returns a new connector of its own for the statements a test runs next to the workers (e.g. DDL
+// between the modes), it's never taken from the pool, as worker 0 shares the pool key with dbConnector() and puts its
+// own connector there after the run, so the caller must Close() it instead of Release()
+func scratchConnector(b *benchmark.Benchmark) *benchmark.DBConnector {
+	return benchmark.NewPinnedDBConnector(&b.TestOpts.(*TestOpts).DBOpts, 0, b.Logger, 1)
+}
+
+// withCassandraConsistency returns the cassandra dsn with the consistency parameter set to given level
+func withCassandraConsistency(dsn string, level string) string {
+	host, query, _ := strings.Cut(dsn, "?")
+
+	params := []string{"consistency=" + level}
+	for _, param := range strings.Split(query, "&") {
+		if param != "" && !strings.HasPrefix(param, "consistency=") {
+			params = append(params, param)
+		}
+	}
+
+	return host + "?" + strings.Join(params, "&")
+}
+
+// pgConnParams returns the connection parameters of the postgres key=value or URL dsn
+func pgConnParams(dsn string) map[string]string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if converted, err := pq.ParseURL(dsn); err == nil {
+			dsn = converted
+		}
+	}
+
+	params := make(map[string]string)
+	for _, param := range strings.Fields(dsn) {
+		if k, v, ok := strings.Cut(param, "="); ok {
+			params[k] = strings.Trim(v, "'")
+		}
+	}
+
+	return params
+}
+
+// mysqlMultiStatementsDSN returns the mysql dsn allowing several statements separated by semicolons in a single query
+func mysqlMultiStatementsDSN(dsn string) string {
+	if strings.Contains(dsn, "multiStatements=") {
+		return dsn
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&multiStatements=true"
+	}
+
+	return dsn + "?multiStatements=true"
+}
+
 func cleanupTables(b *benchmark.Benchmark) {
 	dbOpts := b.TestOpts.(*TestOpts).DBOpts
 
 	if dbOpts.DontCleanup {
-		fmt.Printf("skip acronis_db_bench_* tables cleanup\n")
+		fmt.Fprintf(out(b), "skip acronis_db_bench_* tables cleanup\n")
 
 		return
 	}
 
-	fmt.Printf("cleaning up the test tables ... ")
+	fmt.Fprintf(out(b), "cleaning up the test tables ... ")
 
 	c := dbConnector(b)
 
@@ -65,12 +130,14 @@ func cleanupTables(b *benchmark.Benchmark) {
 
 	b.TenantsCache.DropTables(c)
 	c.DropSequence(benchmark.SequenceName)
+	c.DropSequence(idManualSequence)
+	dropTenantSchemas(c)
 	c.Release()
 
 	eb := NewEventBus(&b.TestOpts.(*TestOpts).DBOpts, b.Logger)
 	eb.DropTables()
 
-	fmt.Printf("done\n")
+	fmt.Fprintf(out(b), "done\n")
 }
 
 func getDBInfo(b *benchmark.Benchmark, content []string) (ret string) {
@@ -104,3 +171,268 @@ func formatSQL(sqlTemlate, driver string) string {
 
 	return re.ReplaceAllString(sqlTemlate, "?")
 }
+
+// pgDefaultParamTypes maps fake column types to postgres parameter types used as explicit type hints (see --pg-param-types)
+var pgDefaultParamTypes = map[string]string{
+	"autoinc":     "int8",
+	"int":         "int8",
+	"bigint":      "int8",
+	"time_ns":     "int8",
+	"now_sec":     "int8",
+	"now_ms":      "int8",
+	"now_mcs":     "int8",
+	"now_ns":      "int8",
+	"string":      "text",
+	"rstring":     "text",
+	"uuid":        "uuid",
+	"uuid_v7":     "uuid",
+	"seq_key":     "text",
+	"tenant_uuid": "uuid",
+	"cti_uuid":    "text",
+	"time":        "timestamp",
+	"timestamp":   "text",
+	"bool":        "bool",
+	"byte":        "bytea",
+	"rbyte":       "bytea",
+	"blob":        "bytea",
+}
+
+// loadPgParamTypes parses the --pg-param-types option, the 'default' value enables built-in mapping, 'type:pgtype' pairs override it
+func loadPgParamTypes(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	opt := strings.TrimSpace(testOpts.TestcaseOpts.PgParamTypes)
+	if opt == "" || testOpts.DBOpts.Driver != benchmark.POSTGRES {
+		return
+	}
+
+	paramTypes := make(map[string]string, len(pgDefaultParamTypes))
+	for k, v := range pgDefaultParamTypes {
+		paramTypes[k] = v
+	}
+
+	if opt != "default" {
+		for _, pair := range strings.Split(opt, ",") {
+			kv := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+			if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+				b.Exit("invalid --pg-param-types value: '%s', expected 'default' or comma-separated column-type:pg-type pairs", pair)
+			}
+			paramTypes[kv[0]] = kv[1]
+		}
+	}
+
+	b.Vault.(*DBTestData).PgParamTypes = paramTypes
+}
+
+// loadReadReplicas parses the --read-replicas option into per-replica database options
+func loadReadReplicas(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	if strings.TrimSpace(testOpts.DBOpts.ReadReplicas) == "" {
+		return
+	}
+
+	var replicas []*benchmark.DatabaseOpts
+	for _, dsn := range strings.Split(testOpts.DBOpts.ReadReplicas, ",") {
+		dsn = strings.TrimSpace(dsn)
+		if dsn == "" {
+			b.Exit("invalid --read-replicas value: '%s', expected comma-separated DSNs", testOpts.DBOpts.ReadReplicas)
+		}
+		opts := testOpts.DBOpts
+		opts.Dsn = dsn
+		opts.ReadReplicas = ""
+		replicas = append(replicas, &opts)
+	}
+
+	b.Vault.(*DBTestData).ReadReplicas = replicas
+}
+
+// printReadReplicaRates prints the read-only test throughput of every read replica to detect unbalanced or lagging ones
+func printReadReplicaRates(b *benchmark.Benchmark, score benchmark.Score) {
+	replicas := len(b.Vault.(*DBTestData).ReadReplicas)
+	if replicas == 0 || score.Seconds == 0 {
+		return
+	}
+
+	loops := make([]int, replicas)
+	workers := make([]int, replicas)
+	for workerID, l := range score.WorkerLoops {
+		wd, ok := b.WorkerData[workerID].(*DBWorkerData)
+		if !ok || wd.replica < 0 {
+			return
+		}
+		loops[wd.replica] += l
+		workers[wd.replica]++
+	}
+
+	for r := 0; r < replicas; r++ {
+		fmt.Fprintf(out(b), "read replica #%d: workers: %d; loops: %d; rate: %.1f %s\n", r+1, workers[r], loops[r], float64(loops[r])/score.Seconds, score.Metric)
+	}
+}
+
+// genParameterPlaceholders generates $N placeholders, with explicit ::type hints on postgres if --pg-param-types is set
+func genParameterPlaceholders(b *benchmark.Benchmark, colConfs *[]benchmark.DBFakeColumnConf) string {
+	paramTypes := b.Vault.(*DBTestData).PgParamTypes
+	if len(paramTypes) == 0 {
+		return benchmark.GenDBParameterPlaceholders(0, len(*colConfs))
+	}
+
+	placeholders := make([]string, len(*colConfs))
+	for i, col := range *colConfs {
+		if pgType, ok := paramTypes[col.ColumnType]; ok {
+			placeholders[i] = fmt.Sprintf("$%d::%s", i+1, pgType)
+		} else {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+		}
+	}
+
+	return strings.Join(placeholders, ",")
+}
+
+// fakeTimeLayout is the layout of the 'time' fake column values, see benchmark.Benchmark.GenFakeValue()
+const fakeTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// pgParamValues converts the 'time' column values to time.Time if --pg-param-types is set, postgres can't cast
+// their text form with both the offset and the zone name to the timestamp type hint
+func pgParamValues(b *benchmark.Benchmark, colConfs *[]benchmark.DBFakeColumnConf, values []interface{}) {
+	if len(b.Vault.(*DBTestData).PgParamTypes) == 0 {
+		return
+	}
+
+	for i, col := range *colConfs {
+		if col.ColumnType != "time" {
+			continue
+		}
+		if s, ok := values[i].(string); ok {
+			if t, err := time.Parse(fakeTimeLayout, s); err == nil {
+				values[i] = t
+			}
+		}
+	}
+}
+
+// setBatch sets the batch of the test, the returned function restores the previous one
+func setBatch(b *benchmark.Benchmark, batch int) (restore func()) {
+	d := b.Vault.(*DBTestData)
+	origBatch := d.EffectiveBatch
+	d.EffectiveBatch = batch
+
+	return func() {
+		d.EffectiveBatch = origBatch
+	}
+}
+
+// setDefaultBatch sets the batch of the test unless --batch is set, the returned function restores the previous one
+func setDefaultBatch(b *benchmark.Benchmark, batch int) (restore func()) {
+	if b.TestOpts.(*TestOpts).BenchOpts.Batch != 0 {
+		batch = b.Vault.(*DBTestData).EffectiveBatch
+	}
+
+	return setBatch(b, batch)
+}
+
+// cleanupOnExit makes given cleanup run whatever way the test exits (see Benchmark.PreExit), the returned function
+// runs the cleanup and restores the previous PreExit
+func cleanupOnExit(b *benchmark.Benchmark, cleanup func()) (done func()) {
+	origPreExit := b.PreExit
+	b.PreExit = func() {
+		cleanup()
+		origPreExit()
+	}
+
+	return func() {
+		b.PreExit = origPreExit
+		cleanup()
+	}
+}
+
+// testModes runs the test once per mode and prints the rate of every mode as a table, run runs the test in the i-th
+// mode (usually by testGeneric()) and returns the values of the extra columns of the mode row, the rates are returned
+func testModes(b *benchmark.Benchmark, title string, modes []string, extra []string, run func(i int) []string) []float64 {
+	width := len(title)
+	for _, mode := range modes {
+		width = benchmark.Max(width, len(mode))
+	}
+
+	rows := make([]string, 0, len(modes))
+	rates := make([]float64, 0, len(modes))
+
+	for i, mode := range modes {
+		if b.NeedToExit {
+			break
+		}
+
+		values := run(i)
+		rates = append(rates, b.Score.Rate)
+		rows = append(rows, fmt.Sprintf("%-*s %24s%s", width, mode, b.Score.FormatRate(4)+" "+b.Score.Metric, modeColumns(values)))
+	}
+
+	fmt.Fprintf(out(b), "%-*s %24s%s\n", width, title, "RATE", modeColumns(extra))
+	fmt.Fprintf(out(b), "%s\n", strings.Join(rows, "\n"))
+
+	return rates
+}
+
+// modeColumns formats the extra columns of the testModes() table
+func modeColumns(values []string) string {
+	var s strings.Builder
+	for _, v := range values {
+		fmt.Fprintf(&s, " %14s", v)
+	}
+
+	return s.String()
+}
+
+// testCounters are the atomic counters of the loop outcomes of a test run (e.g. conflicts, retries, time spent),
+// indexed by the test own constants
+type testCounters []uint64
+
+// newTestCounters returns n zeroed counters, they are zeroed again after the warmup of every test run, see initCommon()
+func newTestCounters(b *benchmark.Benchmark, n int) testCounters {
+	c := make(testCounters, n)
+	b.Vault.(*DBTestData).counters = append(b.Vault.(*DBTestData).counters, c)
+
+	return c
+}
+
+// add adds delta to the i-th counter
+func (c testCounters) add(i int, delta uint64) {
+	atomic.AddUint64(&c[i], delta)
+}
+
+// addTime adds the time passed since start (nsec) to the i-th counter
+func (c testCounters) addTime(i int, start time.Time) {
+	atomic.AddUint64(&c[i], uint64(time.Since(start)))
+}
+
+// get returns the i-th counter
+func (c testCounters) get(i int) uint64 {
+	return atomic.LoadUint64(&c[i])
+}
+
+// reset zeroes all the counters
+func (c testCounters) reset() {
+	for i := range c {
+		atomic.StoreUint64(&c[i], 0)
+	}
+}
+
+// percent returns the share of the i-th counter in the sum of given counters in percents
+func (c testCounters) percent(i int, total ...int) float64 {
+	var sum uint64
+	for _, t := range total {
+		sum += c.get(t)
+	}
+	if sum == 0 {
+		return 0
+	}
+
+	return float64(c.get(i)) * 100 / float64(sum)
+}
+
+// avgMsec returns the i-th time counter (nsec) per the n-th counter in milliseconds
+func (c testCounters) avgMsec(i int, n int) float64 {
+	if c.get(n) == 0 {
+		return 0
+	}
+
+	return float64(c.get(i)) / float64(c.get(n)) / float64(time.Millisecond)
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/acronis/perfkit/benchmark"
@@ -21,6 +23,11 @@ type TestTable struct {
 	CreateQuery           string
 	CreateQueryPatchFuncs []CreateQueryPatchFunc
 	Indexes               []string
+	Checks                []string // CHECK constraints the table is created with if --with-check-constraints is set
+
+	DurabilityConfigurable  bool // the table honors the --table-persistence option
+	CodecConfigurable       bool // the table honors the --clickhouse-codec option
+	CompressionConfigurable bool // the table honors the --blob-compression option
 
 	// runtime information
 	RowsCount uint64
@@ -133,6 +140,348 @@ func (t *TestTable) GetColumnsForUpdate(withAutoInc bool) *[]benchmark.DBFakeCol
 	return t.GetColumnsConf(t.UpdateColumns, withAutoInc)
 }
 
+const (
+	TablePersistenceLogged   = "logged"   // TablePersistenceLogged is a regular durable table
+	TablePersistenceUnlogged = "unlogged" // TablePersistenceUnlogged is a table which skips WAL (postgres only)
+	TablePersistenceTemp     = "temp"     // TablePersistenceTemp is a session-local temporary table
+)
+
+// checkTablePersistence validates the --table-persistence option against the DB driver
+func checkTablePersistence(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	persistence := testOpts.TestcaseOpts.TablePersistence
+	driver := testOpts.DBOpts.Driver
+
+	switch persistence {
+	case TablePersistenceLogged:
+		return
+	case TablePersistenceUnlogged:
+		if driver != benchmark.POSTGRES {
+			// MEMORY engine is the closest MySQL equivalent, but it doesn't support BLOB/TEXT columns of the 'heavy' table
+			b.Exit("--table-persistence=%s is supported for postgres only, use --mysql-engine= for mysql", persistence)
+		}
+	case TablePersistenceTemp:
+		if driver != benchmark.POSTGRES && driver != benchmark.MYSQL {
+			b.Exit("--table-persistence=%s is supported for postgres and mysql only", persistence)
+		}
+		// temporary table is visible only to the session which has created it, so every worker must stick to one connection
+		testOpts.DBOpts.MaxOpenConns = 1
+	default:
+		b.Exit("unsupported table persistence: '%s', supported values are: %s|%s|%s",
+			persistence, TablePersistenceLogged, TablePersistenceUnlogged, TablePersistenceTemp)
+	}
+}
+
+// checkCheckConstraints validates the --with-check-constraints option against the DB driver
+func checkCheckConstraints(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	if !testOpts.TestcaseOpts.CheckConstraints {
+		return
+	}
+
+	switch testOpts.DBOpts.Driver {
+	case benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE:
+	default:
+		b.Exit("--with-check-constraints is supported for postgres, mysql, mssql and sqlite only")
+	}
+}
+
+// applyChecks appends the table CHECK constraints to its create query if --with-check-constraints is set
+func (t *TestTable) applyChecks(b *benchmark.Benchmark, query string) string {
+	if !b.TestOpts.(*TestOpts).TestcaseOpts.CheckConstraints || len(t.Checks) == 0 {
+		return query
+	}
+
+	end := strings.LastIndex(query, ") {$engine}")
+	if end < 0 {
+		b.Exit("internal error: can't add CHECK constraints to the '%s' table create query", t.TableName)
+	}
+
+	var checks strings.Builder
+	for n, check := range t.Checks {
+		// the constraint names share the namespace with the tables on MSSQL, so they must not look like the table copies
+		// 'insert-heavy-check-constraint' creates
+		checks.WriteString(fmt.Sprintf(",\n\tCONSTRAINT %s_ck_%d CHECK (%s)", t.TableName, n, check))
+	}
+
+	return query[:end] + checks.String() + "\n" + query[end:]
+}
+
+// hasColumn returns true if the table has given column, the 'id' column is implied
+func (t *TestTable) hasColumn(name string) bool {
+	if name == "id" {
+		return true
+	}
+
+	for _, column := range t.columns {
+		if column[0].(string) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadClickHouseCodecs validates the --clickhouse-codec options and keeps the column to codec mapping
+func loadClickHouseCodecs(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	if len(testOpts.TestcaseOpts.ClickHouseCodec) == 0 {
+		return
+	}
+
+	if testOpts.DBOpts.Driver != benchmark.CLICKHOUSE {
+		b.Exit("--clickhouse-codec is supported for clickhouse only")
+	}
+
+	var tables []TestTable
+	for _, t := range TestTables {
+		if t.CodecConfigurable {
+			tables = append(tables, t)
+		}
+	}
+
+	codecs := make(map[string]string)
+
+	for _, opt := range testOpts.TestcaseOpts.ClickHouseCodec {
+		column, codec, ok := strings.Cut(opt, "=")
+		column, codec = strings.TrimSpace(column), strings.TrimSpace(codec)
+		if !ok || column == "" || codec == "" {
+			b.Exit("invalid --clickhouse-codec value: '%s', expected column=codec, e.g. ts=Delta,ZSTD", opt)
+		}
+
+		known := false
+		for i := range tables {
+			if tables[i].hasColumn(column) {
+				known = true
+
+				break
+			}
+		}
+		if !known {
+			b.Exit("--clickhouse-codec: unknown column '%s', only the 'heavy' and 'timeseries' tables columns are supported", column)
+		}
+
+		codecs[column] = codec
+	}
+
+	b.Vault.(*DBTestData).ClickHouseCodecs = codecs
+}
+
+// applyClickHouseCodecs sets the --clickhouse-codec compression codecs of the table columns,
+// on existing table the new codec is applied to newly written parts only
+func (t *TestTable) applyClickHouseCodecs(c *benchmark.DBConnector, b *benchmark.Benchmark) {
+	if !t.CodecConfigurable || c.DbOpts.Driver != benchmark.CLICKHOUSE {
+		return
+	}
+
+	codecs := b.Vault.(*DBTestData).ClickHouseCodecs
+	columns := make([]string, 0, len(codecs))
+	for column := range codecs {
+		if t.hasColumn(column) {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+
+	for _, column := range columns {
+		c.ExecOrExit(fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s CODEC(%s)", t.TableName, column, codecs[column]))
+	}
+}
+
+// blobCompressions lists the --blob-compression values supported by every DB driver
+var blobCompressions = map[string][]string{
+	benchmark.POSTGRES: {"pglz", "lz4"},         // TOAST compression method, postgres 14+
+	benchmark.MYSQL:    {"none", "zlib", "lz4"}, // InnoDB page compression, file-per-table tablespace and hole punching are required
+	benchmark.MSSQL:    {"none", "row", "page"}, // data compression, it doesn't compress the off-row VARBINARY(MAX) values
+}
+
+// checkBlobCompression validates the --blob-compression option against the DB driver
+func checkBlobCompression(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	compression := testOpts.TestcaseOpts.BlobCompression
+	if compression == "" {
+		return
+	}
+
+	supported, ok := blobCompressions[testOpts.DBOpts.Driver]
+	if !ok {
+		b.Exit("--blob-compression is supported for postgres, mysql and mssql only")
+	}
+
+	for _, s := range supported {
+		if compression == s {
+			return
+		}
+	}
+
+	b.Exit("unsupported --blob-compression '%s' for %s, supported values are: %s", compression, testOpts.DBOpts.Driver, strings.Join(supported, "|"))
+}
+
+// applyBlobCompression sets the --blob-compression of the table, on existing table the compression applies to newly written data only
+func (t *TestTable) applyBlobCompression(c *benchmark.DBConnector, b *benchmark.Benchmark) {
+	compression := b.TestOpts.(*TestOpts).TestcaseOpts.BlobCompression
+	if !t.CompressionConfigurable || compression == "" {
+		return
+	}
+
+	switch c.DbOpts.Driver {
+	case benchmark.POSTGRES:
+		c.ExecOrExit(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN data SET COMPRESSION %s", t.TableName, compression))
+	case benchmark.MYSQL:
+		c.ExecOrExit(fmt.Sprintf("ALTER TABLE %s COMPRESSION = '%s'", t.TableName, compression))
+	case benchmark.MSSQL:
+		c.ExecOrExit(fmt.Sprintf("ALTER TABLE %s REBUILD WITH (DATA_COMPRESSION = %s)", t.TableName, strings.ToUpper(compression)))
+	}
+}
+
+// loadTypeMap loads the --type-map file and keeps the logical to physical column types mapping for the current DB driver
+func loadTypeMap(b *benchmark.Benchmark) {
+	testOpts := b.TestOpts.(*TestOpts)
+	if testOpts.BenchOpts.TypeMap == "" {
+		return
+	}
+
+	data, err := os.ReadFile(testOpts.BenchOpts.TypeMap)
+	if err != nil {
+		b.Exit("can't read type map: %v", err)
+	}
+
+	// driver -> logical type -> physical type
+	var typeMap map[string]map[string]string
+	if err = json.Unmarshal(data, &typeMap); err != nil {
+		b.Exit("can't parse type map '%s': %v", testOpts.BenchOpts.TypeMap, err)
+	}
+
+	driverTypes := make(map[string]string)
+	for logicalType, physicalType := range typeMap[testOpts.DBOpts.Driver] {
+		logicalType = strings.TrimSuffix(strings.TrimPrefix(logicalType, "{$"), "}")
+		if logicalType == "" || physicalType == "" {
+			b.Exit("invalid type map '%s': empty type for driver '%s'", testOpts.BenchOpts.TypeMap, testOpts.DBOpts.Driver)
+		}
+		driverTypes[logicalType] = physicalType
+	}
+
+	b.Vault.(*DBTestData).TypeMap = driverTypes
+}
+
+// applyTypeMap replaces the logical column types placeholders (e.g. {$datetime}) overridden by --type-map, the rest placeholders keep default types
+func applyTypeMap(b *benchmark.Benchmark, query string) string {
+	for logicalType, physicalType := range b.Vault.(*DBTestData).TypeMap {
+		query = strings.ReplaceAll(query, "{$"+logicalType+"}", physicalType)
+	}
+
+	return query
+}
+
+// addHeavyExtraColumns widens the 'heavy' table (and all the tests using it) by --heavy-extra-columns columns
+func addHeavyExtraColumns(b *benchmark.Benchmark) {
+	n := b.TestOpts.(*TestOpts).TestcaseOpts.HeavyExtraColumns
+	if n < 0 {
+		b.Exit("--heavy-extra-columns must be >= 0")
+	}
+	if n == 0 {
+		return
+	}
+
+	var columns [][]interface{}
+	var schema strings.Builder
+
+	// mix of int and varchar columns, so both numbers and strings binding is stressed
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("extra_%04d", i)
+		if i%2 == 1 {
+			columns = append(columns, []interface{}{name, "int", 1000000})
+			schema.WriteString(fmt.Sprintf(",\n\t%-25s integer", name))
+		} else {
+			columns = append(columns, []interface{}{name, "string", 0, 32})
+			schema.WriteString(fmt.Sprintf(",\n\t%-25s varchar(32)", name))
+		}
+	}
+
+	widen := func(t *TestTable) {
+		t.columns = append(append([][]interface{}{}, t.columns...), columns...)
+		t.ColumnsConf = nil
+		t.CreateQuery = `create table {table} (` + tableHeavySchema + schema.String() + `) {$engine};`
+	}
+
+	for _, tableName := range []string{TestTableHeavy.TableName, TestTableHeavyPartitioned.TableName} {
+		t := TestTables[tableName]
+		widen(&t)
+		TestTables[tableName] = t
+	}
+
+	// every test keeps its own copy of the table description
+	_, tests := GetTests()
+	for _, test := range tests {
+		if test.table.TableName == TestTableHeavy.TableName || test.table.TableName == TestTableHeavyPartitioned.TableName {
+			widen(&test.table)
+		}
+	}
+}
+
+// decimalType returns the decimal column type with --decimal-precision and --decimal-scale
+func decimalType(b *benchmark.Benchmark) string {
+	precision, scale := decimalPrecisionScale(b)
+
+	return fmt.Sprintf("DECIMAL(%d,%d)", precision, scale)
+}
+
+// decimalPrecisionScale returns validated --decimal-precision and --decimal-scale values
+func decimalPrecisionScale(b *benchmark.Benchmark) (precision int, scale int) {
+	testOpts := b.TestOpts.(*TestOpts)
+	precision, scale = testOpts.TestcaseOpts.DecimalPrecision, testOpts.TestcaseOpts.DecimalScale
+
+	// 38 is the max precision supported by MSSQL, MySQL allows 65
+	if precision < 1 || precision > 38 || scale < 0 || scale > precision {
+		b.Exit("invalid decimal precision/scale: %d/%d, expected 1 <= precision <= 38 and 0 <= scale <= precision", precision, scale)
+	}
+
+	return precision, scale
+}
+
+// persistence returns the effective table persistence
+func (t *TestTable) persistence(b *benchmark.Benchmark) string {
+	if !t.DurabilityConfigurable {
+		return TablePersistenceLogged
+	}
+
+	return b.TestOpts.(*TestOpts).TestcaseOpts.TablePersistence
+}
+
+// isTemporary returns true if the table is created as a session-local temporary table
+func (t *TestTable) isTemporary(b *benchmark.Benchmark) bool {
+	return t.persistence(b) == TablePersistenceTemp
+}
+
+// applyPersistence patches the table creation query according to the table persistence
+func (t *TestTable) applyPersistence(b *benchmark.Benchmark, query string) string {
+	switch t.persistence(b) {
+	case TablePersistenceUnlogged:
+		return strings.Replace(query, "create table", "create unlogged table", 1)
+	case TablePersistenceTemp:
+		return strings.Replace(query, "create table", "create temporary table", 1)
+	}
+
+	return query
+}
+
+// createTemporary (re)creates the temporary table in the session of the given DBConnector
+func (t *TestTable) createTemporary(c *benchmark.DBConnector, query string) {
+	switch c.DbOpts.Driver {
+	case benchmark.MYSQL:
+		c.ExecOrExit("DROP TEMPORARY TABLE IF EXISTS " + t.TableName)
+	default:
+		c.ExecOrExit("DROP TABLE IF EXISTS pg_temp." + t.TableName)
+	}
+
+	c.ApplyMigrations(t.TableName, query)
+
+	// existence of the indexes can't be checked reliably for temporary tables, so create them unconditionally
+	for n, columns := range t.Indexes {
+		c.ExecOrExit(fmt.Sprintf("CREATE INDEX %s_tmp_idx_%d ON %s(%s)", t.TableName, n, t.TableName, columns))
+	}
+}
+
 // Create creates table in DB using provided DBConnector
 func (t *TestTable) Create(c *benchmark.DBConnector, b *benchmark.Benchmark) {
 	if t.TableName == "" {
@@ -144,7 +493,10 @@ func (t *TestTable) Create(c *benchmark.DBConnector, b *benchmark.Benchmark) {
 		// b.Exit("internal error: no migration provided for table %s creation", t.TableName)
 		return
 	}
-	tableCreationQuery := t.CreateQuery
+	tableCreationQuery := applyTypeMap(b, t.CreateQuery)
+	if strings.Contains(tableCreationQuery, "{$decimal}") {
+		tableCreationQuery = strings.ReplaceAll(tableCreationQuery, "{$decimal}", decimalType(b))
+	}
 
 	var err error
 
@@ -155,11 +507,29 @@ func (t *TestTable) Create(c *benchmark.DBConnector, b *benchmark.Benchmark) {
 		}
 	}
 
+	tableCreationQuery = t.applyPersistence(b, tableCreationQuery)
+	tableCreationQuery = t.applyChecks(b, tableCreationQuery)
+
+	if t.isTemporary(b) {
+		t.createTemporary(c, tableCreationQuery)
+
+		return
+	}
+
 	c.CreateTable(t.TableName, tableCreationQuery)
 
 	for n, columns := range t.Indexes {
 		c.CreateIndex(t.TableName, columns, n)
 	}
+
+	t.applyClickHouseCodecs(c, b)
+	t.applyBlobCompression(c, b)
+}
+
+// scratchIndexID returns the id of the n-th index a test creates for its run only, the ids follow the ones of the table
+// own indexes (see Create()), so the names of the scratch indexes never clash with them
+func scratchIndexID(t *TestTable, n int) int {
+	return len(t.Indexes) + n
 }
 
 /*
@@ -310,9 +680,12 @@ var TestTableHeavy = TestTable{
 		{"blocker_count", "int", 3},
 		{"const_val", "int", 1},
 	},
-	InsertColumns: []string{}, // all
-	UpdateColumns: []string{"progress", "result_payload", "update_time_str", "update_time_ns", "completion_time_str", "completion_time_ns"},
-	CreateQuery:   `create table {table} (` + tableHeavySchema + `) {$engine};`,
+	InsertColumns:          []string{}, // all
+	UpdateColumns:          []string{"progress", "result_payload", "update_time_str", "update_time_ns", "completion_time_str", "completion_time_ns"},
+	CreateQuery:            `create table {table} (` + tableHeavySchema + `) {$engine};`,
+	Checks:                 []string{"progress BETWEEN 0 AND 100"},
+	DurabilityConfigurable: true,
+	CodecConfigurable:      true,
 	Indexes: []string{
 		"uuid",
 		"completion_time_ns",
@@ -337,6 +710,48 @@ var TestTableHeavy = TestTable{
 	},
 }
 
+// heavyPartitions is the amount of range partitions of the 'heavy_part' table, every partition holds heavyPartitionStep
+// values of the 'progress' column (which is in [0, 100) range)
+const (
+	heavyPartitions    = 10
+	heavyPartitionStep = 10
+)
+
+// heavyPartitionName returns the name of the i-th partition of the 'heavy_part' table
+func heavyPartitionName(table string, i int) string {
+	return fmt.Sprintf("%s_p%d", table, i)
+}
+
+// TestTableHeavyPartitioned is the 'heavy' table range-partitioned by the 'progress' column (postgres only)
+var TestTableHeavyPartitioned = TestTable{
+	TableName:     "acronis_db_bench_heavy_part",
+	columns:       TestTableHeavy.columns,
+	InsertColumns: []string{}, // all
+	UpdateColumns: TestTableHeavy.UpdateColumns,
+	CreateQuery:   TestTableHeavy.CreateQuery,
+	CreateQueryPatchFuncs: []CreateQueryPatchFunc{
+		func(table string, query string, sql_driver string, sql_engine string) (string, error) {
+			if sql_driver != benchmark.POSTGRES {
+				return "", fmt.Errorf("unsupported driver: '%v', supported drivers are: %s", sql_driver, benchmark.POSTGRES)
+			}
+
+			// unique constraints of a partitioned table must include the partition key
+			query = strings.ReplaceAll(query, "{$bigint_autoinc_pk}", "{$bigint_autoinc}")
+			query = strings.ReplaceAll(query, "{$unique}", "")
+			query = strings.ReplaceAll(query, "{$engine}", "PARTITION BY RANGE (progress)")
+
+			for i := 0; i < heavyPartitions; i++ {
+				query += fmt.Sprintf("\nCREATE TABLE %s PARTITION OF %s FOR VALUES FROM (%d) TO (%d);",
+					heavyPartitionName(table, i), table, i*heavyPartitionStep, (i+1)*heavyPartitionStep)
+			}
+			query += fmt.Sprintf("\nCREATE TABLE %s_default PARTITION OF %s DEFAULT;", table, table)
+
+			return query, nil
+		},
+	},
+	Indexes: TestTableHeavy.Indexes,
+}
+
 // TestTableBlob is table to store blobs
 var TestTableBlob = TestTable{
 	TableName: "acronis_db_bench_blob",
@@ -355,7 +770,93 @@ var TestTableBlob = TestTable{
 		timestamp bigint {$notnull},
 		data {$hugeblob} {$notnull}
 		) {$engine};`,
-	Indexes: []string{"tenant_id", "uuid"},
+	Indexes:                 []string{"tenant_id", "uuid"},
+	CompressionConfigurable: true,
+}
+
+// TestTableKeyOrder is table to store light objects with externally generated primary key (see --insert-key-order)
+var TestTableKeyOrder = TestTable{
+	TableName: "acronis_db_bench_key_order",
+	columns: [][]interface{}{
+		{"id", "seq_key"},
+		{"uuid", "uuid"},
+		{"data", "string", 0, 64},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id {$varchar_uuid} primary key,
+		uuid {$varchar_uuid} {$notnull},
+		data varchar(64) {$notnull}
+		) {$engine};`,
+	Indexes: []string{"uuid"},
+}
+
+// TestTableMoney is table to store financial transactions with exact decimal and approximate float amounts
+var TestTableMoney = TestTable{
+	TableName: "acronis_db_bench_money",
+	columns: [][]interface{}{
+		{"id", "autoinc"},
+		{"tenant_id", "tenant_uuid"},
+		{"amount", "decimal", 0, 18, 2}, // precision and scale are set by --decimal-precision and --decimal-scale
+		{"amount_float", "float", 1000000},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id {$bigint_autoinc_pk},
+		tenant_id {$varchar_uuid} {$notnull},
+		amount {$decimal} {$notnull},
+		amount_float {$double} {$notnull}
+		) {$engine};`,
+	Indexes: []string{"tenant_id"},
+}
+
+// TestTableUniqueKeys is table to store objects with unique business key from a small key space to provoke concurrent duplicates
+var TestTableUniqueKeys = TestTable{
+	TableName: "acronis_db_bench_unique_keys",
+	columns: [][]interface{}{
+		{"key_id", "uuid", 10000},
+		{"value", "int", 100},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id {$bigint_autoinc_pk},
+		key_id {$varchar_uuid} {$notnull} {$unique},
+		value int {$notnull}
+		) {$engine};`,
+}
+
+// TestTableMediumUpsert is the 'medium' table with unique uuid, see the 'upsert-medium' test
+var TestTableMediumUpsert = TestTable{
+	TableName: "acronis_db_bench_medium_upsert",
+	columns: [][]interface{}{
+		{"uuid", "uuid"},
+		{"tenant_id", "tenant_uuid"},
+		{"euc_id", "int", 2147483647},
+		{"progress", "int", 100},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id {$bigint_autoinc_pk},
+		uuid {$varchar_uuid} {$notnull} {$unique},
+		tenant_id {$varchar_uuid} {$notnull},
+		euc_id int {$notnull},
+		progress int {$null}
+		) {$engine};`,
+}
+
+// TestTableQueue is table to store jobs of the job queue, state 0 - pending, 1 - processed
+var TestTableQueue = TestTable{
+	TableName: "acronis_db_bench_queue",
+	columns: [][]interface{}{
+		{"payload", "string", 0, 64},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id {$bigint_autoinc_pk},
+		state int {$notnull},
+		payload varchar(64) {$notnull}
+		) {$engine};`,
+	Indexes: []string{"state"},
 }
 
 // TestTableLargeObj is table to store large objects
@@ -430,6 +931,21 @@ var TestTableJSON = TestTable{
 	Indexes:               []string{"sequence", "created_at"},
 }
 
+// TestTableJSONTags is table to store JSON documents with the tags array
+var TestTableJSONTags = TestTable{
+	TableName: "acronis_db_bench_json_tags",
+	columns: [][]interface{}{
+		{"doc", "json_tags", 1000, 5}, // up to 5 tags out of 1000 per document
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+			id {$bigint_autoinc_pk},
+			doc {$json_type} {$notnull}
+			) {$engine};
+			{$json_tags_index}`,
+	CreateQueryPatchFuncs: []CreateQueryPatchFunc{JSONTagsTableCreateQueryPatchFunc},
+}
+
 // TestTableTimeSeriesSQL is table to store time series data
 var TestTableTimeSeriesSQL = TestTable{
 	TableName: "acronis_db_bench_ts_sql",
@@ -462,7 +978,71 @@ var TestTableTimeSeriesSQL = TestTable{
 			return query, nil
 		},
 	},
-	Indexes: []string{"tenant_id", "device_id", "metric_id"},
+	Indexes:           []string{"tenant_id", "device_id", "metric_id"},
+	CodecConfigurable: true,
+}
+
+// TestTableTimeSeriesLatest is table to store the last value of every (device, metric) pair
+var TestTableTimeSeriesLatest = TestTable{
+	TableName: "acronis_db_bench_ts_latest",
+	columns: [][]interface{}{
+		{"device_id", "int", 0},
+		{"metric_id", "int", 0},
+		{"ts", "now", 0},
+		{"value", "int", 100},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		device_id int {$notnull},
+		metric_id int {$notnull},
+		ts {$datetime6} {$notnull},
+		value int {$notnull},
+		PRIMARY KEY (device_id, metric_id)
+		) {$engine};`,
+}
+
+// TestTableCounters is table to store aggregate counters incremented by atomic upserts
+var TestTableCounters = TestTable{
+	TableName: "acronis_db_bench_counters",
+	columns: [][]interface{}{
+		{"counter_key", "int", 0},
+		{"hits", "int", 1},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		counter_key int {$notnull},
+		hits bigint {$notnull},
+		PRIMARY KEY (counter_key)
+		) {$engine};`,
+}
+
+// TestTableIDManual is table to store light objects with the primary key generated by the application, see 'insert-max-id-plus-one'
+var TestTableIDManual = TestTable{
+	TableName: "acronis_db_bench_id_manual",
+	columns: [][]interface{}{
+		{"id", "int", 0},
+		{"uuid", "uuid"},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id bigint {$notnull},
+		uuid {$uuid} {$notnull},
+		PRIMARY KEY (id)
+		) {$engine};`,
+}
+
+// TestTableIDIdentity is table to store light objects with the primary key generated by an identity column, see 'insert-max-id-plus-one'
+var TestTableIDIdentity = TestTable{
+	TableName: "acronis_db_bench_id_identity",
+	columns: [][]interface{}{
+		{"id", "autoinc"},
+		{"uuid", "uuid"},
+	},
+	InsertColumns: []string{}, // all
+	CreateQuery: `create table {table} (
+		id {$bigint_autoinc_pk},
+		uuid {$uuid} {$notnull}
+		) {$engine};`,
 }
 
 // TestTableAdvmTasks is table to store tasks
@@ -842,9 +1422,20 @@ var TestTables = map[string]TestTable{
 	"acronis_db_bench_light":                     TestTableLight,
 	"acronis_db_bench_medium":                    TestTableMedium,
 	"acronis_db_bench_heavy":                     TestTableHeavy,
+	"acronis_db_bench_heavy_part":                TestTableHeavyPartitioned,
 	"acronis_db_bench_blob":                      TestTableBlob,
+	"acronis_db_bench_key_order":                 TestTableKeyOrder,
+	"acronis_db_bench_money":                     TestTableMoney,
+	"acronis_db_bench_unique_keys":               TestTableUniqueKeys,
+	"acronis_db_bench_medium_upsert":             TestTableMediumUpsert,
+	"acronis_db_bench_ts_latest":                 TestTableTimeSeriesLatest,
+	"acronis_db_bench_counters":                  TestTableCounters,
+	"acronis_db_bench_id_manual":                 TestTableIDManual,
+	"acronis_db_bench_id_identity":               TestTableIDIdentity,
+	"acronis_db_bench_queue":                     TestTableQueue,
 	"acronis_db_bench_largeobj":                  TestTableLargeObj,
 	"acronis_db_bench_json":                      TestTableJSON,
+	"acronis_db_bench_json_tags":                 TestTableJSONTags,
 	"acronis_db_bench_ts_sql":                    TestTableTimeSeriesSQL,
 	"acronis_db_bench_cybercache_tenants":        TestTableTenants,
 	"acronis_db_bench_cybercache_tenant_closure": TestTableTenantsClosure,
@@ -879,3 +1470,30 @@ func JSONTableCreateQueryPatchFunc(table string, query string, sql_driver string
 
 	return query, nil
 }
+
+// jsonTagsIndexName is a name of the index on the tags array of the 'json_tags' table
+const jsonTagsIndexName = "acronis_db_bench_json_tags_idx_tags"
+
+// jsonTagsIndexQuery returns driver specific query creating an array containment index on the tags of the 'json_tags' table
+func jsonTagsIndexQuery(sqlDriver string) string {
+	switch sqlDriver {
+	case benchmark.MYSQL:
+		// multi-valued index, MySQL 8.0.17+
+		return "CREATE INDEX " + jsonTagsIndexName + " ON acronis_db_bench_json_tags ((CAST(doc->'$.tags' AS CHAR(32) ARRAY)))"
+	default:
+		return "CREATE INDEX " + jsonTagsIndexName + " ON acronis_db_bench_json_tags USING GIN ((doc -> 'tags') jsonb_path_ops)"
+	}
+}
+
+func JSONTagsTableCreateQueryPatchFunc(table string, query string, sql_driver string, sql_engine string) (string, error) { //nolint:revive
+	switch sql_driver {
+	case benchmark.MYSQL:
+		query = strings.ReplaceAll(query, "{$json_type}", "json")
+	case benchmark.POSTGRES:
+		query = strings.ReplaceAll(query, "{$json_type}", "jsonb")
+	default:
+		return "", fmt.Errorf("unsupported driver: '%v', supported drivers are: postgres|mysql", sql_driver)
+	}
+
+	return strings.ReplaceAll(query, "{$json_tags_index}", jsonTagsIndexQuery(sql_driver)), nil
+}
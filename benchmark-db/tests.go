@@ -1,9 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	mssql "github.com/denisenkom/go-mssqldb"
 	"github.com/lib/pq"
@@ -61,10 +70,61 @@ type TestDesc struct {
 	isReadonly  bool // indicates the test doesn't run DDL and doesn't modidy data
 	isDBRTest   bool
 	databases   []string
+	requires    []benchmark.DBFeature // requires lists the database features the test relies on, see fallbacks
 
 	table TestTable // SQL table name
 
 	launcherFunc launcherFunc
+	fallbacks    []TestFallback // fallbacks are chosen automatically if the database lacks a required feature
+}
+
+// TestFallback is an emulation of a test for the databases lacking a feature the test relies on
+type TestFallback struct {
+	emulates     benchmark.DBFeature   // emulates is the missing feature the fallback works around
+	requires     []benchmark.DBFeature // requires lists the database features the fallback relies on
+	emulation    string                // emulation describes the way the feature is emulated
+	launcherFunc launcherFunc
+}
+
+// testLauncher returns the test launcher, or the one of the fallback emulating the features the database lacks. On
+// CockroachDB a test lacking features w/o emulation is skipped rather than failed, then the launcher is nil and the
+// second value is the reason
+func testLauncher(b *benchmark.Benchmark, testDesc *TestDesc) (launcher launcherFunc, emulation string) {
+	if len(testDesc.requires) == 0 {
+		return testDesc.launcherFunc, ""
+	}
+
+	c := dbConnector(b)
+	defer c.Release()
+
+	var missing []benchmark.DBFeature
+	for _, f := range testDesc.requires {
+		if !c.SupportsFeature(f) {
+			missing = append(missing, f)
+		}
+	}
+
+	if len(missing) == 0 {
+		return testDesc.launcherFunc, ""
+	}
+
+	for _, fb := range testDesc.fallbacks {
+		usable := len(missing) == 1 && missing[0] == fb.emulates
+		for _, f := range fb.requires {
+			usable = usable && c.SupportsFeature(f)
+		}
+		if usable {
+			return fb.launcherFunc, fmt.Sprintf("'%s' is not supported by the database, %s", fb.emulates, fb.emulation)
+		}
+	}
+
+	if c.DbOpts.Dialect == benchmark.COCKROACH {
+		return nil, fmt.Sprintf("requires the %v features not supported by CockroachDB", missing)
+	}
+
+	b.Exit("Test: '%s' requires the %v features not supported by the '%s' database and has no suitable emulation", testDesc.name, missing, c.DbOpts.Driver)
+
+	return nil, ""
 }
 
 // dbIsSupported returns true if the database is supported by the test
@@ -103,11 +163,16 @@ var (
 	PMWSA = []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE, benchmark.CASSANDRA}
 )
 
+// withCockroach returns the list of databases plus CockroachDB, a test opts in with it once verified on CockroachDB
+func withCockroach(databases []string) []string {
+	return append(databases[:len(databases):len(databases)], benchmark.COCKROACH)
+}
+
 // TestBaseAll tests all tests in the 'base' group
 var TestBaseAll = TestDesc{
 	name:        "all",
 	description: "execute all tests in the 'base' group",
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	//	launcherFunc: ...  # causes 'initialization cycle' go-lang compiler error
 }
 
@@ -119,7 +184,7 @@ var TestPing = TestDesc{
 	category:    TestOther,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
 			err := c.Ping()
@@ -166,7 +231,7 @@ var TestRawQuery = TestDesc{
 					}
 					q = strings.Replace(q, "{TENANT}", "'"+string(tenantUUID)+"'", -1)
 				}
-				fmt.Printf("query %s\n", q)
+				fmt.Fprintf(out(b), "query %s\n", q)
 				c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, q)
 
 				return 1
@@ -190,7 +255,7 @@ var TestSelectOne = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
 			c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, "SELECT 1")
@@ -201,6 +266,114 @@ var TestSelectOne = TestDesc{
 	},
 }
 
+// TestSelectGenerateSeries generates rows on the server side w/o any table access and reads them all
+var TestSelectGenerateSeries = TestDesc{
+	name:        "select-generate-series",
+	metric:      "rows/sec",
+	description: "generate rows on the server side w/o table access (generate_series / recursive CTE / GENERATE_SERIES) and read them all, rows per query is set by --batch (default 10000)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		defer setDefaultBatch(b, 10000)()
+
+		var query string
+
+		switch getDBDriver(b) {
+		case benchmark.POSTGRES:
+			query = "SELECT n FROM generate_series(1, %d) AS n"
+		case benchmark.MYSQL:
+			// the recursion depth is limited by 1000 by default
+			query = "WITH RECURSIVE series (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM series WHERE n < %[1]d) SELECT /*+ SET_VAR(cte_max_recursion_depth = %[1]d) */ n FROM series"
+		case benchmark.MSSQL:
+			// SQL Server 2022+
+			query = "SELECT value FROM GENERATE_SERIES(1, %d)"
+		default:
+			query = "WITH RECURSIVE series (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM series WHERE n < %d) SELECT n FROM series"
+		}
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, fmt.Sprintf(query, batch))
+
+			return batch
+		}
+		testGeneric(b, testDesc, worker, 0)
+	},
+}
+
+// cancelTimeout limits the time the 'cancel-propagation' test waits for the cancelled query to stop
+const cancelTimeout = 10 * time.Second
+
+// expensiveAggregateQuery returns an aggregate query which runs for minutes w/o any table access
+func expensiveAggregateQuery(driver string) string {
+	switch driver {
+	case benchmark.POSTGRES:
+		return "SELECT SUM(a.n * b.n) FROM generate_series(1, 100000) AS a(n), generate_series(1, 100000) AS b(n)"
+	case benchmark.MYSQL:
+		return "SELECT COUNT(*) FROM information_schema.COLUMNS a, information_schema.COLUMNS b, information_schema.COLUMNS c"
+	case benchmark.MSSQL:
+		return "SELECT COUNT_BIG(*) FROM sys.all_objects a CROSS JOIN sys.all_objects b CROSS JOIN sys.all_objects c"
+	default:
+		return "WITH RECURSIVE series (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM series WHERE n < 10000000000) SELECT SUM(n) FROM series"
+	}
+}
+
+// TestCancelPropagation starts an expensive aggregate, cancels it by the client context and checks how fast the server
+// really stops executing it
+var TestCancelPropagation = TestDesc{
+	name:        "cancel-propagation",
+	metric:      "cancels/sec",
+	description: "start an expensive aggregate, cancel it after --cancel-delay msec and report the cancel-to-return and the cancel-to-stop (pg_stat_activity / processlist / dm_exec_requests) latency percentiles",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		query := expensiveAggregateQuery(getDBDriver(b))
+		delay := time.Duration(b.TestOpts.(*TestOpts).TestcaseOpts.CancelDelay) * time.Millisecond
+
+		var lock sync.Mutex
+		var returned, stopped []time.Duration
+		leaked := 0
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			stats, err := c.CancelQuery(query, delay, cancelTimeout)
+			if err != nil {
+				c.Exit("query cancellation failed: %v", err)
+			}
+
+			lock.Lock()
+			returned = append(returned, stats.Returned)
+			if stats.Stopped >= 0 {
+				stopped = append(stopped, stats.Stopped)
+			}
+			if stats.Leaked {
+				leaked++
+			}
+			lock.Unlock()
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 0)
+
+		ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+		fmt.Fprintf(out(b), "%-24s %8s %10s %10s %10s\n", "LATENCY (msec)", "SAMPLES", "P50", "P90", "P99")
+		fmt.Fprintf(out(b), "%-24s %8d %10.1f %10.1f %10.1f\n", "cancel-to-return", len(returned),
+			ms(benchmark.Percentile(returned, 50)), ms(benchmark.Percentile(returned, 90)), ms(benchmark.Percentile(returned, 99)))
+		if len(stopped) > 0 {
+			fmt.Fprintf(out(b), "%-24s %8d %10.1f %10.1f %10.1f\n", "cancel-to-stop (server)", len(stopped),
+				ms(benchmark.Percentile(stopped, 50)), ms(benchmark.Percentile(stopped, 90)), ms(benchmark.Percentile(stopped, 99)))
+		} else {
+			fmt.Fprintf(out(b), "%-24s %8s\n", "cancel-to-stop (server)", "n/a")
+		}
+		if leaked > 0 {
+			fmt.Fprintf(out(b), "WARNING: %d cancelled queries were still running on the server in %v\n", leaked, cancelTimeout)
+		}
+	},
+}
+
 // TestSelectOneDBR tests do 'SELECT 1' using golang DBR query builder
 var TestSelectOneDBR = TestDesc{
 	name:        "dbr-select-1",
@@ -228,11 +401,11 @@ var TestSelectOneDBR = TestDesc{
 var TestSelectNextVal = TestDesc{
 	name:        "select-nextval",
 	metric:      "ops/sec",
-	description: "increment a DB sequence in a loop (or use SELECT FOR UPDATE, UPDATE)",
+	description: "increment a DB sequence in a loop (or use SELECT FOR UPDATE, UPDATE; unique_rowid() on CockroachDB)",
 	category:    TestOther,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   withCockroach(RELATIONAL),
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		c := dbConnector(b)
 		c.CreateSequence(benchmark.SequenceName)
@@ -256,7 +429,7 @@ var TestSelectMediumLast = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		orderby := func(b *benchmark.Benchmark) string { return "id DESC" }
@@ -288,18 +461,74 @@ var TestSelectMediumRand = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+		selectMediumRand(b, testDesc)
+	},
+}
 
-			return fmt.Sprintf("id > %d", id)
+// selectMediumRand selects random row from the 'medium' table using literal SQL or bind parameters depending on --pg-protocol
+func selectMediumRand(b *benchmark.Benchmark, testDesc *TestDesc) {
+	if getDBDriver(b) == benchmark.POSTGRES && b.TestOpts.(*TestOpts).DBOpts.PgProtocol != benchmark.PgProtocolSimple {
+		// extended and prepared protocols require bind parameters instead of literal SQL
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			id := b.Randomizer.GetWorker(c.WorkerID).Uintn64(testDesc.table.RowsCount - 1)
+			c.Select(testDesc.table.TableName, "id", "id > $1", "id ASC", batch, b.TestOpts.(*TestOpts).BenchOpts.Explain, int64(id))
+
+			return batch
 		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id ASC"
+		testGeneric(b, testDesc, worker, 1)
+
+		return
+	}
+
+	where := func(b *benchmark.Benchmark, workerId int) string {
+		id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+		return fmt.Sprintf("id > %d", id)
+	}
+	orderby := func(b *benchmark.Benchmark) string {
+		return "id ASC"
+	}
+	testSelect(b, testDesc, nil, "id", where, orderby, 1)
+}
+
+// TestSelectMediumRandPgProtocols runs the 'select-medium-rand' test under every postgres query protocol
+var TestSelectMediumRandPgProtocols = TestDesc{
+	name:        "select-medium-rand-pg-protocols",
+	metric:      "rows/sec",
+	description: "select random row from the 'medium' table under the simple, extended and prepared postgres query protocols and compare the rates, see --pg-protocol",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		// the connectors read the protocol on every query, so switching it between the runs is enough
+		dbOpts := &b.TestOpts.(*TestOpts).DBOpts
+		defer func(protocol string) { dbOpts.PgProtocol = protocol }(dbOpts.PgProtocol)
+
+		protocols := []string{benchmark.PgProtocolSimple, benchmark.PgProtocolExtended, benchmark.PgProtocolPrepared}
+		if mode := dbOpts.PgBouncerMode; mode == benchmark.PgBouncerTransaction || mode == benchmark.PgBouncerStatement {
+			// server-side prepared statements are not pinned to the client session in these modes, see --pgbouncer-mode
+			protocols = protocols[:2]
 		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+
+		var simpleRate float64
+		testModes(b, "PROTOCOL", protocols, []string{"VS SIMPLE"}, func(i int) []string {
+			dbOpts.PgProtocol = protocols[i]
+			selectMediumRand(b, testDesc)
+
+			if i == 0 {
+				simpleRate = b.Score.Rate
+			}
+			if simpleRate <= 0 {
+				return []string{"n/a"}
+			}
+
+			return []string{fmt.Sprintf("%+.1f%%", (b.Score.Rate-simpleRate)*100/simpleRate)}
+		})
 	},
 }
 
@@ -326,6 +555,102 @@ var TestSelectMediumRandDBR = TestDesc{
 	},
 }
 
+// namedPreparedStatement is a name of the server-side prepared statement used by the 'select-medium-named-prepared' test
+const namedPreparedStatement = "acronis_db_bench_select_medium"
+
+// namedPreparedQueries returns driver specific queries managing the server-side named prepared statement lifecycle
+func namedPreparedQueries(driver string, tableName string) (prepare string, execute func(id int64) []string, deallocate string) {
+	query := fmt.Sprintf("SELECT id, uuid FROM %s WHERE id = ", tableName)
+
+	switch driver {
+	case benchmark.MYSQL:
+		// MySQL EXECUTE accepts only user variables as parameters, so every execution costs an extra SET
+		return fmt.Sprintf("PREPARE %s FROM '%s?'", namedPreparedStatement, query),
+			func(id int64) []string {
+				return []string{fmt.Sprintf("SET @id = %d", id), fmt.Sprintf("EXECUTE %s USING @id", namedPreparedStatement)}
+			},
+			fmt.Sprintf("DEALLOCATE PREPARE %s", namedPreparedStatement)
+	default:
+		return fmt.Sprintf("PREPARE %s(bigint) AS %s$1", namedPreparedStatement, query),
+			func(id int64) []string {
+				return []string{fmt.Sprintf("EXECUTE %s(%d)", namedPreparedStatement, id)}
+			},
+			fmt.Sprintf("DEALLOCATE %s", namedPreparedStatement)
+	}
+}
+
+// TestSelectMediumNamedPrepared selects random row from the 'medium' table using server-side named prepared statement
+var TestSelectMediumNamedPrepared = TestDesc{
+	name:        "select-medium-named-prepared",
+	metric:      "rows/sec",
+	description: "select random row from the 'medium' table by PREPARE once and EXECUTE per loop, compare with per-call parameters binding",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		driver := getDBDriver(b)
+		explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+		prepareSQL, executeSQL, deallocateSQL := namedPreparedQueries(driver, testDesc.table.TableName)
+		bindSQL := formatSQL(fmt.Sprintf("SELECT id, uuid FROM %s WHERE id = $1", testDesc.table.TableName), driver)
+
+		testModes(b, "STATEMENT", []string{"per-call bind", "named prepared"}, nil, func(i int) []string {
+			prepared := i == 1
+			started := make([]bool, b.CommonOpts.Workers)
+
+			initCommon(b, testDesc, 1)
+
+			// the prepared statement lives in the DB session, so every worker keeps its session pinned by a transaction
+			finishPerWorker := b.FinishPerWorker
+			b.FinishPerWorker = func(workerId int) {
+				if started[workerId] {
+					c := b.WorkerData[workerId].(*DBWorkerData).conn
+					if prepared {
+						c.ExecOrExit(deallocateSQL)
+					}
+					c.Commit()
+				}
+				finishPerWorker(workerId)
+			}
+
+			b.Worker = func(workerId int) (loops int) {
+				c := b.WorkerData[workerId].(*DBWorkerData).conn
+
+				if !started[workerId] {
+					c.Begin()
+					if prepared {
+						c.ExecOrExit(prepareSQL)
+					}
+					started[workerId] = true
+				}
+
+				id := int64(b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount) + 1)
+
+				if !prepared {
+					c.SelectRaw(explain, bindSQL, id)
+
+					return 1
+				}
+
+				queries := executeSQL(id)
+				for _, q := range queries[:len(queries)-1] {
+					c.ExecOrExit(q)
+				}
+				c.SelectRaw(explain, queries[len(queries)-1])
+
+				return 1
+			}
+
+			b.Run()
+
+			b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+
+			return nil
+		})
+	},
+}
+
 // TestSelectHeavyLast selects last row from the 'heavy' table
 var TestSelectHeavyLast = TestDesc{
 	name:        "select-heavy-last",
@@ -334,7 +659,7 @@ var TestSelectHeavyLast = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   withCockroach(RELATIONAL),
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		orderby := func(b *benchmark.Benchmark) string { return "id DESC" }
@@ -366,7 +691,7 @@ var TestSelectHeavyRand = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   withCockroach(RELATIONAL),
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		where := func(b *benchmark.Benchmark, workerId int) string {
@@ -381,908 +706,5202 @@ var TestSelectHeavyRand = TestDesc{
 	},
 }
 
-// TestSelectHeavyRandDBR selects random row from the 'heavy' table using golang DBR query builder
-var TestSelectHeavyRandDBR = TestDesc{
-	name:        "dbr-select-heavy-rand",
+// TestSelectHeavyFetchFirst selects a page of rows from the 'heavy' table using standard SQL FETCH NEXT and compares it with LIMIT / TOP
+var TestSelectHeavyFetchFirst = TestDesc{
+	name:        "select-heavy-fetch-first",
 	metric:      "rows/sec",
-	description: "select random row from the 'heavy' table using golang DBR query builder",
+	description: "select a page of 10 rows from the 'heavy' table starting from random id using OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY and compare with LIMIT / TOP",
 	category:    TestSelect,
 	isReadonly:  true,
-	isDBRTest:   true,
+	isDBRTest:   false,
 	databases:   RELATIONAL,
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+		const pageSize = 10
 
-			return fmt.Sprintf("id > %d", id)
-		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id ASC"
-		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+		testModes(b, "PAGINATION", []string{"LIMIT / TOP", "FETCH NEXT"}, nil, func(i int) []string {
+			fetchFirst := i == 1
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				id := b.Randomizer.GetWorker(c.WorkerID).Uintn64(testDesc.table.RowsCount - 1)
+				where := fmt.Sprintf("id > %d", id)
+				explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+
+				if fetchFirst {
+					c.SelectFetchFirst(testDesc.table.TableName, "id", where, "id ASC", 0, pageSize, explain)
+				} else {
+					c.Select(testDesc.table.TableName, "id", where, "id ASC", pageSize, explain)
+				}
+
+				return 1
+			}, 1)
+
+			return nil
+		})
 	},
 }
 
-// TestSelectHeavyRandTenantLike selects random row from the 'heavy' table WHERE tenant_id = {} AND resource_name LIKE {}
-var TestSelectHeavyRandTenantLike = TestDesc{
-	name:        "select-heavy-rand-in-tenant-like",
+// TestSelectUnionAllFeed selects the latest page of the "activity feed" merged from the 'light', 'medium' and 'heavy' tables
+var TestSelectUnionAllFeed = TestDesc{
+	name:        "select-union-all-feed",
 	metric:      "rows/sec",
-	description: "select random row from the 'heavy' table WHERE tenant_id = {} AND resource_name LIKE {}",
+	description: "select the latest page of the activity feed merged from the 'light', 'medium' and 'heavy' tables using UNION ALL ... ORDER BY id DESC",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-
-		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
-
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			w := b.GenFakeDataAsMap(workerId, colConfs, false)
-
-			return fmt.Sprintf("tenant_id = '%s' AND resource_name LIKE '%s'", (*w)["tenant_id"], "%a%")
+		defer setDefaultBatch(b, 20)()
+
+		// the 'light' table has no tenant or time columns, so the monotonic bigint id all the three tables share is the
+		// feed order, it keeps the branches type compatible in every dialect
+		from := func(b *benchmark.Benchmark, workerId int) string {
+			return fmt.Sprintf("(SELECT 'light' AS src, id FROM %s UNION ALL SELECT 'medium' AS src, id FROM %s "+
+				"UNION ALL SELECT 'heavy' AS src, id FROM %s) feed",
+				TestTableLight.TableName, TestTableMedium.TableName, TestTableHeavy.TableName)
 		}
 		orderby := func(b *benchmark.Benchmark) string {
 			return "id DESC"
 		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+
+		testSelect(b, testDesc, from, "src, id", nil, orderby, 1)
 	},
 }
 
-// TestSelectHeavyMinMaxTenant selects min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {}
-var TestSelectHeavyMinMaxTenant = TestDesc{
-	name:        "select-heavy-minmax-in-tenant",
-	metric:      "rows/sec",
-	description: "select min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {}",
-	category:    TestSelect,
-	isReadonly:  true,
-	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+// the counters of a single mode of the 'select-heavy-cursor-reuse' test
+const (
+	cursorPages    = iota // pages read
+	cursorPageTime        // time spent reading the pages, nsec
+	cursorOpens           // cursors opened
+	cursorOpenTime        // time spent opening the cursors, nsec
+	cursorCounters
+)
 
-		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+// cursorName returns the name of the server-side cursor held by given worker
+func cursorName(workerID int) string {
+	return fmt.Sprintf("acronis_db_bench_cursor_%d", workerID)
+}
 
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+// keysetPageQuery returns the query reading the next page of the 'heavy' table after given id
+func keysetPageQuery(driver string, tableName string, afterID int64, pageSize int) string {
+	if driver == benchmark.MSSQL {
+		return fmt.Sprintf("SELECT TOP %d id, tenant_id FROM %s WHERE id > %d ORDER BY id", pageSize, tableName, afterID)
+	}
 
-			return fmt.Sprintf("tenant_id = '%s'", (*w)["tenant_id"])
-		}
-		testSelect(b, testDesc, nil, "min(completion_time_ns), max(completion_time_ns)", where, nil, 1)
-	},
+	return fmt.Sprintf("SELECT id, tenant_id FROM %s WHERE id > %d ORDER BY id LIMIT %d", tableName, afterID, pageSize)
 }
 
-// TestSelectHeavyMinMaxTenantAndState selects min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {} AND state = {}
-var TestSelectHeavyMinMaxTenantAndState = TestDesc{
-	name:        "select-heavy-minmax-in-tenant-and-state",
+// TestCursorReuse reads the 'heavy' table page by page through a cursor held open across the loops
+var TestCursorReuse = TestDesc{
+	name:        "select-heavy-cursor-reuse",
 	metric:      "rows/sec",
-	description: "select min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {} AND state = {}",
+	description: "read the 'heavy' table page by page through a WITH HOLD cursor held open across the loops (postgres) and compare with the stateless keyset pagination",
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		tableName := testDesc.table.TableName
+		driver := b.TestOpts.(*TestOpts).DBOpts.Driver
 
-		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "state"}, false)
-
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+		defer setDefaultBatch(b, 100)()
+		origCollectLatencies := b.CollectLatencies
+		b.CollectLatencies = true
+		defer func() { b.CollectLatencies = origCollectLatencies }()
 
-			return fmt.Sprintf("tenant_id = '%s' AND state = '%d'", (*w)["tenant_id"], (*w)["state"])
+		modes := []string{"keyset"}
+		if driver == benchmark.POSTGRES {
+			modes = []string{"WITH HOLD cursor", "keyset"}
 		}
-		testSelect(b, testDesc, nil, "min(completion_time_ns), max(completion_time_ns)", where, nil, 1)
-	},
-}
 
-// TestSelectHeavyForUpdateSkipLocked selects a row from the 'heavy' table and then updates it
-var TestSelectHeavyForUpdateSkipLocked = TestDesc{
-	name:        "select-heavy-for-update-skip-locked",
-	metric:      "updates/sec",
-	description: "do SELECT FOR UPDATE SKIP LOCKED and then UPDATE",
-	category:    TestOther,
-	isReadonly:  false,
-	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		var query string
-		max := b.CommonOpts.Workers*2 + 1
+		// cursors live in the DB session, so every worker needs its own pinned session
+		sessions := make([]*benchmark.DBConnector, b.CommonOpts.Workers)
+		cursorOpen := make([]bool, b.CommonOpts.Workers)
+		lastID := make([]int64, b.CommonOpts.Workers)
 
-		switch b.TestOpts.(*TestOpts).DBOpts.Driver {
-		case benchmark.POSTGRES, benchmark.MYSQL:
-			query = fmt.Sprintf("SELECT id, progress FROM acronis_db_bench_heavy WHERE id < %d LIMIT 1 FOR UPDATE SKIP LOCKED", max)
-		case benchmark.MSSQL:
-			query = fmt.Sprintf("SELECT TOP(1) id, progress FROM acronis_db_bench_heavy WITH (UPDLOCK, READPAST, ROWLOCK) WHERE id < %d", max)
-		default:
-			b.Exit("unsupported driver: '%v', supported drivers are: %s|%s|%s", b.TestOpts.(*TestOpts).DBOpts.Driver, benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL)
+		for w := range sessions {
+			sessions[w] = benchmark.NewPinnedDBConnector(&b.TestOpts.(*TestOpts).DBOpts, w, b.Logger, 10)
+			sessions[w].Connect()
 		}
 
-		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
-			var id int64
-			var progress int
+		// closing the sessions drops the cursors whatever way the test exits
+		closeSessions := func() {
+			for w, s := range sessions {
+				if cursorOpen[w] {
+					if _, err := s.Exec("CLOSE " + cursorName(w)); err != nil {
+						s.Log(benchmark.LogError, "can't close cursor: %v", err)
+					}
+					cursorOpen[w] = false
+				}
+				s.Close()
+			}
+		}
+		defer cleanupOnExit(b, closeSessions)()
 
-			c.QueryRowAndScan(query, &id, &progress)
-			c.ExecOrExit(fmt.Sprintf("UPDATE acronis_db_bench_heavy SET progress = %d WHERE id = %d", progress+1, id))
+		readPage := func(s *benchmark.DBConnector, query string) (n int, last int64) {
+			rows, err := s.Query(query)
+			if err != nil {
+				b.Exit(err.Error())
+			}
+			defer rows.Close()
 
-			return 1
+			var tenantID string
+			for rows.Next() {
+				if err = rows.Scan(&last, &tenantID); err != nil {
+					b.Exit(err.Error())
+				}
+				n++
+			}
+			if err = rows.Err(); err != nil {
+				b.Exit(err.Error())
+			}
+
+			return n, last
 		}
-		testGeneric(b, testDesc, worker, 10000)
-	},
-}
 
-// TestInsertLight inserts a row into the 'light' table
-var TestInsertLight = TestDesc{
-	name:        "insert-light",
-	metric:      "rows/sec",
-	description: "insert a row into the 'light' table",
-	category:    TestInsert,
+		// fetchPage reads the next page of the worker's cursor or keyset and returns the amount of rows read
+		fetchPage := func(w int, useCursor bool, batch int, counters testCounters) (n int) {
+			s := sessions[w]
+
+			if useCursor {
+				if !cursorOpen[w] {
+					start := time.Now()
+					s.ExecOrExit(fmt.Sprintf("DECLARE %s CURSOR WITH HOLD FOR SELECT id, tenant_id FROM %s ORDER BY id", cursorName(w), tableName))
+					counters.addTime(cursorOpenTime, start)
+					counters.add(cursorOpens, 1)
+					cursorOpen[w] = true
+				}
+
+				start := time.Now()
+				n, _ = readPage(s, fmt.Sprintf("FETCH FORWARD %d FROM %s", batch, cursorName(w)))
+				counters.addTime(cursorPageTime, start)
+
+				// the cursor is exhausted, start over with a new one
+				if n < batch {
+					s.ExecOrExit("CLOSE " + cursorName(w))
+					cursorOpen[w] = false
+				}
+			} else {
+				start := time.Now()
+				var last int64
+				n, last = readPage(s, keysetPageQuery(driver, tableName, lastID[w], batch))
+				counters.addTime(cursorPageTime, start)
+
+				lastID[w] = last
+				if n < batch {
+					lastID[w] = 0
+				}
+			}
+			counters.add(cursorPages, 1)
+
+			return n
+		}
+
+		testModes(b, "MODE", modes, []string{"PAGE, ms", "PAGE P99, ms", "OPENS", "OPEN, ms"}, func(i int) []string {
+			counters := newTestCounters(b, cursorCounters)
+			useCursor := modes[i] != "keyset"
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				// an empty page means the previous one was the last, so start over, zero loops would stop the worker
+				for loops == 0 {
+					loops = fetchPage(c.WorkerID, useCursor, batch, counters)
+				}
+
+				return loops
+			}, 1)
+
+			return []string{
+				fmt.Sprintf("%.3f", counters.avgMsec(cursorPageTime, cursorPages)),
+				fmt.Sprintf("%.3f", float64(b.Score.P99)/float64(time.Millisecond)),
+				strconv.FormatUint(counters.get(cursorOpens), 10),
+				fmt.Sprintf("%.3f", counters.avgMsec(cursorOpenTime, cursorOpens)),
+			}
+		})
+	},
+}
+
+// preparedStatementName returns the name of the i-th statement prepared by the 'prepared-statements-footprint' test
+func preparedStatementName(i int) string {
+	return fmt.Sprintf("acronis_db_bench_ps_%d", i)
+}
+
+// prepareStatementQuery returns the SQL-level PREPARE of the i-th distinct statement, the constant makes every statement text unique
+func prepareStatementQuery(driver string, tableName string, i int) string {
+	if driver == benchmark.MYSQL {
+		return fmt.Sprintf("PREPARE %s FROM 'SELECT id, uuid, tenant_id FROM %s WHERE id = ? AND progress <> %d'", preparedStatementName(i), tableName, i)
+	}
+
+	return fmt.Sprintf("PREPARE %s AS SELECT id, uuid, tenant_id FROM %s WHERE id = $1 AND progress <> %d", preparedStatementName(i), tableName, i)
+}
+
+// TestPreparedStatementFootprint prepares many distinct statements in a single session and reports the server-side memory growth
+var TestPreparedStatementFootprint = TestDesc{
+	name:        "prepared-statements-footprint",
+	metric:      "bytes/statement",
+	description: "prepare --prepared-statements distinct statements in a single session and report the server-side session memory growth",
+	category:    TestOther,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		tableName := testDesc.table.TableName
+		driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+		total := b.TestOpts.(*TestOpts).TestcaseOpts.PreparedStmts
+		if total <= 0 {
+			b.Exit("--prepared-statements must be > 0")
+		}
+
+		// prepared statements live in the DB session, so all of them must go through the same pinned session
+		c := benchmark.NewPinnedDBConnector(&b.TestOpts.(*TestOpts).DBOpts, 0, b.Logger, 10)
+		c.Connect()
+
+		if !c.TableExists(tableName) {
+			b.Exit("The '%s' table doesn't exist, please create tables using -I option, or use individual insert test using the -t `insert-***`", tableName)
+		}
+
+		// closing the session deallocates all its prepared statements whatever way the test exits
+		closeSession := cleanupOnExit(b, c.Close)
+
+		_, baseline := c.GetPreparedStatementsFootprint()
+
+		formatBytes := func(v int64) string {
+			if v < 0 {
+				return "n/a"
+			}
+
+			return strconv.FormatInt(v, 10)
+		}
+
+		fmt.Fprintf(out(b), "%12s %15s %20s %20s %20s\n", "STATEMENTS", "SERVER COUNT", "SESSION MEMORY", "GROWTH", "PER STATEMENT")
+
+		start := time.Now()
+		for i, milestone := 1, 1; i <= total; i++ {
+			c.ExecOrExit(prepareStatementQuery(driver, tableName, i))
+
+			if i != milestone && i != total {
+				continue
+			}
+			milestone *= 10
+
+			count, memory := c.GetPreparedStatementsFootprint()
+
+			growth, perStatement := int64(-1), int64(-1)
+			if memory >= 0 && baseline >= 0 {
+				growth = memory - baseline
+				perStatement = growth / int64(i)
+			}
+
+			fmt.Fprintf(out(b), "%12d %15s %20s %20s %20s\n", i, formatBytes(count), formatBytes(memory), formatBytes(growth), formatBytes(perStatement))
+		}
+		fmt.Fprintf(out(b), "prepared %d statements in %.3f sec\n", total, time.Since(start).Seconds())
+
+		if driver == benchmark.POSTGRES {
+			c.ExecOrExit("DEALLOCATE ALL")
+		} else {
+			for i := 1; i <= total; i++ {
+				c.ExecOrExit("DEALLOCATE PREPARE " + preparedStatementName(i))
+			}
+		}
+
+		closeSession()
+	},
+}
+
+// preparedPoolStatements is the amount of distinct statements of the 'prepared-pool-contention' test
+const preparedPoolStatements = 10
+
+// TestPreparedPoolContention selects rows by prepared statements through a small connections pool shared by all the workers
+var TestPreparedPoolContention = TestDesc{
+	name:        "prepared-pool-contention",
+	metric:      "queries/sec",
+	description: "select a row from the 'light' table by one of several prepared statements using a connections pool shared by all the workers, every connection prepares a statement on its own, see --pool-sizes",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var sizes []int
+		for _, s := range strings.Split(b.TestOpts.(*TestOpts).TestcaseOpts.PoolSizes, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || size <= 0 {
+				b.Exit("invalid pool size '%s' in --pool-sizes, positive integers are expected", s)
+			}
+			sizes = append(sizes, size)
+		}
+
+		tableName := testDesc.table.TableName
+
+		c := dbConnector(b)
+		maxID := c.QueryMaxVal(tableName, "id", "")
+		c.Release()
+
+		if maxID == 0 {
+			b.Exit("The '%s' table is empty, please fill it using the -t 'insert-light' test", tableName)
+		}
+
+		queries := make([]string, preparedPoolStatements)
+		for i := range queries {
+			// the constant makes every statement text unique, so each of them is prepared separately
+			queries[i] = fmt.Sprintf("SELECT id, uuid FROM %s WHERE id = $1 AND id > -%d", tableName, i+1)
+		}
+
+		names := make([]string, len(sizes))
+		for i, size := range sizes {
+			names[i] = strconv.Itoa(size)
+		}
+
+		testModes(b, "POOL SIZE", names, []string{"PREPARES", "CACHE HITS", "WAIT USEC/QRY"}, func(i int) []string {
+			size := sizes[i]
+			c := dbConnector(b)
+			pool, err := c.NewPreparedPool(size)
+			c.Release()
+			if err != nil {
+				b.Exit("can't open the pool of %d connections: %v", size, err)
+			}
+
+			closePool := cleanupOnExit(b, pool.Close)
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				rw := b.Randomizer.GetWorker(c.WorkerID)
+
+				for i := 0; i < batch; i++ {
+					if _, err := pool.Query(queries[rw.Intn(len(queries))], rw.Intn(maxID)+1); err != nil {
+						c.Exit(err.Error())
+					}
+				}
+
+				return batch
+			}, 0)
+
+			stats := pool.TakeStats()
+			closePool()
+
+			hitRate, avgWait := 0.0, 0.0
+			if stats.Executes > 0 {
+				hitRate = float64(stats.Executes-stats.Prepares) * 100 / float64(stats.Executes)
+				avgWait = float64(stats.Wait.Microseconds()) / float64(stats.Executes)
+			}
+
+			return []string{strconv.Itoa(stats.Prepares), fmt.Sprintf("%.2f%%", hitRate), fmt.Sprintf("%.1f", avgWait)}
+		})
+	},
+}
+
+// the counters of a single feature of the 'select-pooler-features' test
+const (
+	poolerProbes = iota // feature probes
+	poolerErrors        // failed probes
+	poolerCounters
+)
+
+// TestSelectPoolerFeatures probes the postgres client features PgBouncer transaction and statement pool modes break,
+// so the throughput and the error rate of every feature show what works through the pooler the dsn points to
+var TestSelectPoolerFeatures = TestDesc{
+	name:        "select-pooler-features",
+	metric:      "queries/sec",
+	description: "probe simple and extended queries, transactions, SQL PREPARE and session SET through the connection pooler (PgBouncer), report the rate and errors per feature, see --pgbouncer-mode",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		mode := b.TestOpts.(*TestOpts).DBOpts.PgBouncerMode
+		if mode == "" {
+			mode = "n/a"
+		}
+
+		var seq int64
+
+		fmt.Fprintf(out(b), "PgBouncer pool mode: %s\n", mode)
+
+		testModes(b, "FEATURE", benchmark.PgPoolerFeatures, []string{"ERRORS"}, func(i int) []string {
+			feature := benchmark.PgPoolerFeatures[i]
+			counters := newTestCounters(b, poolerCounters)
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				if err := c.ProbePgPoolerFeature(feature, int(atomic.AddInt64(&seq, 1))); err != nil {
+					counters.add(poolerErrors, 1)
+				}
+				counters.add(poolerProbes, 1)
+
+				return 1
+			}, 0)
+
+			return []string{fmt.Sprintf("%.1f%%", counters.percent(poolerErrors, poolerProbes))}
+		})
+	},
+}
+
+// crossCatalogSchema is the local schema (postgres) or foreign server the remote 'medium' table is visible through
+const crossCatalogSchema = "acronis_db_bench_remote"
+
+// setupCrossCatalog makes the 'medium' table of the remote catalog visible in the current database and returns its name
+// and the cleanup function, or an empty name and the reason if the dialect or the server lacks the federation feature
+func setupCrossCatalog(b *benchmark.Benchmark, c *benchmark.DBConnector) (remoteTable string, cleanup func(), skipReason string) {
+	remoteCatalog := b.TestOpts.(*TestOpts).TestcaseOpts.RemoteCatalog
+	tableName := TestTableMedium.TableName
+
+	switch c.DbOpts.Driver {
+	case benchmark.POSTGRES:
+		var available int
+		c.QueryRowAndScan("SELECT COUNT(*) FROM pg_available_extensions WHERE name = 'postgres_fdw'", &available)
+		if available == 0 {
+			return "", nil, "postgres_fdw extension is not available"
+		}
+		if _, err := c.Exec("CREATE EXTENSION IF NOT EXISTS postgres_fdw"); err != nil {
+			return "", nil, fmt.Sprintf("can't create postgres_fdw extension: %v", err)
+		}
+
+		if remoteCatalog == "" {
+			c.QueryRowAndScan("SELECT current_database()", &remoteCatalog)
+		}
+
+		params := pgConnParams(c.DbOpts.Dsn)
+		host, port := params["host"], params["port"]
+		if host == "" {
+			host = "localhost"
+		}
+		if port == "" {
+			port = "5432"
+		}
+
+		cleanup = func() {
+			c.ExecOrExit(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", crossCatalogSchema))
+			c.ExecOrExit(fmt.Sprintf("DROP SERVER IF EXISTS %s CASCADE", crossCatalogSchema))
+		}
+		cleanup()
+
+		c.ExecOrExit(fmt.Sprintf("CREATE SERVER %s FOREIGN DATA WRAPPER postgres_fdw OPTIONS (host '%s', port '%s', dbname '%s')",
+			crossCatalogSchema, host, port, remoteCatalog))
+		// DDL can't have bind parameters, so the password goes to the statement, but never to the log
+		password := strings.ReplaceAll(params["password"], "'", "''")
+		c.ExecSecretOrExit(fmt.Sprintf("CREATE USER MAPPING FOR CURRENT_USER SERVER %s OPTIONS (user '%s', password '%s')",
+			crossCatalogSchema, params["user"], password), password)
+		c.ExecOrExit(fmt.Sprintf("CREATE SCHEMA %s", crossCatalogSchema))
+		c.ExecOrExit(fmt.Sprintf("IMPORT FOREIGN SCHEMA public LIMIT TO (%s) FROM SERVER %s INTO %s", tableName, crossCatalogSchema, crossCatalogSchema))
+
+		return crossCatalogSchema + "." + tableName, cleanup, ""
+	case benchmark.MSSQL:
+		if remoteCatalog == "" {
+			return "", nil, "--remote-catalog database is not set"
+		}
+
+		remoteTable = fmt.Sprintf("%s.dbo.%s", remoteCatalog, tableName)
+		cleanup = func() {
+			c.ExecOrExit(fmt.Sprintf("DROP TABLE IF EXISTS %s", remoteTable))
+		}
+		cleanup()
+
+		c.ExecOrExit(fmt.Sprintf("SELECT * INTO %s FROM %s", remoteTable, tableName))
+		c.ExecOrExit(fmt.Sprintf("CREATE INDEX %s_tenant_id ON %s (tenant_id)", tableName, remoteTable))
+
+		return remoteTable, cleanup, ""
+	case benchmark.CLICKHOUSE:
+		if remoteCatalog == "" {
+			return "", nil, "--remote-catalog 'host:port' is not set"
+		}
+
+		return fmt.Sprintf("remote('%s', currentDatabase(), '%s')", remoteCatalog, tableName), func() {}, ""
+	default:
+		return "", nil, fmt.Sprintf("cross-catalog queries are not supported by '%s'", c.DbOpts.Driver)
+	}
+}
+
+// TestSelectCrossCatalog joins the local 'heavy' table with the 'medium' table of a remote catalog and compares with the local join
+var TestSelectCrossCatalog = TestDesc{
+	name:        "select-cross-catalog-join",
+	metric:      "rows/sec",
+	description: "join the 'heavy' table with the 'medium' table of a remote catalog (postgres_fdw, MSSQL cross-database, ClickHouse remote()) and compare with the local join, see --remote-catalog",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL, benchmark.CLICKHOUSE},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		c := scratchConnector(b)
+		defer c.Close()
+
+		if !c.TableExists(TestTableMedium.TableName) {
+			b.Exit("The '%s' table doesn't exist, please create it using the 'insert-medium' test first", TestTableMedium.TableName)
+		}
+
+		remoteTable, cleanup, skipReason := setupCrossCatalog(b, c)
+		if remoteTable == "" {
+			fmt.Fprintf(out(b), "skipping the '%s' test: %s\n", testDesc.name, skipReason)
+
+			return
+		}
+
+		defer cleanupOnExit(b, cleanup)()
+		defer setDefaultBatch(b, 10)()
+
+		tables := []string{TestTableMedium.TableName, remoteTable}
+
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			tenant, err := b.TenantsCache.GetRandomTenantUUID(b.Randomizer.GetWorker(workerId), 0)
+			if err != nil {
+				b.Exit(err.Error())
+			}
+
+			return fmt.Sprintf("h.tenant_id = '%s'", tenant)
+		}
+
+		rates := testModes(b, "JOIN", []string{"local", "remote"}, nil, func(i int) []string {
+			joined := fmt.Sprintf("%s h JOIN %s m ON m.tenant_id = h.tenant_id", testDesc.table.TableName, tables[i])
+			from := func(b *benchmark.Benchmark, workerId int) string {
+				return joined
+			}
+
+			testSelect(b, testDesc, from, "h.id, m.id", where, nil, 1)
+
+			return nil
+		})
+
+		if len(rates) == 2 && rates[1] > 0 {
+			fmt.Fprintf(out(b), "federation overhead: %.1fx slower than the local join\n", rates[0]/rates[1])
+		}
+	},
+}
+
+// TestSelectHeavyRandAllColumns selects random row with all the columns from the 'heavy' table
+var TestSelectHeavyRandAllColumns = TestDesc{
+	name:        "select-heavy-rand-all-columns",
+	metric:      "rows/sec",
+	description: "select random row with all the columns (SELECT *) from the 'heavy' table, see also --heavy-extra-columns",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+			return fmt.Sprintf("id > %d", id)
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id ASC"
+		}
+		testSelect(b, testDesc, nil, "*", where, orderby, 1)
+	},
+}
+
+// randIDsPage returns up to size distinct random ids of the table rows
+func randIDsPage(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, size int) []int64 {
+	rw := b.Randomizer.GetWorker(c.WorkerID)
+
+	if uint64(size) > testDesc.table.RowsCount {
+		size = int(testDesc.table.RowsCount)
+	}
+
+	seen := make(map[int64]bool, size)
+	ids := make([]int64, 0, size)
+	for len(ids) < size {
+		id := int64(rw.Uintn64(testDesc.table.RowsCount) + 1)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// selectInListWorker selects a page of random rows from the table using WHERE id IN (...) list of literals
+func selectInListWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	ids := randIDsPage(b, c, testDesc, batch)
+	if len(ids) == 0 {
+		return 1
+	}
+
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = strconv.FormatInt(id, 10)
+	}
+
+	c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, fmt.Sprintf("SELECT id, uuid FROM %s WHERE id IN (%s)", testDesc.table.TableName, strings.Join(list, ", ")))
+
+	return len(ids)
+}
+
+// selectAnyArrayWorker selects a page of random rows from the table using WHERE id = ANY($1) with a single array parameter
+func selectAnyArrayWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	ids := randIDsPage(b, c, testDesc, batch)
+	if len(ids) == 0 {
+		return 1
+	}
+
+	c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, fmt.Sprintf("SELECT id, uuid FROM %s WHERE id = ANY($1::bigint[])", testDesc.table.TableName), pq.Array(ids))
+
+	return len(ids)
+}
+
+// TestSelectHeavyRandInList selects a page of random rows from the 'heavy' table using IN-list
+var TestSelectHeavyRandInList = TestDesc{
+	name:        "select-heavy-rand-in-list",
+	metric:      "rows/sec",
+	description: "select a page of random rows from the 'heavy' table WHERE id IN ({}, {}, ...), page size is set by --batch (default 100)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		defer setDefaultBatch(b, 100)()
+
+		testGeneric(b, testDesc, selectInListWorker, 1)
+	},
+}
+
+// inListSweepSizes are the IN-list sizes of the 'select-heavy-rand-in-list-sweep' test
+var inListSweepSizes = []int{10, 100, 1000, 10000}
+
+// the counters of a single list size of the 'select-heavy-rand-in-list-sweep' test
+const (
+	inListLists = iota // IN-lists selected
+	inListCounters
+)
+
+// mssqlInListChunk is a max amount of bind parameters in a single MSSQL IN-list, keeps the statement below the MSSQL
+// limit of 2100 parameters
+const mssqlInListChunk = 2000
+
+// selectUUIDInList selects the rows of given uuids using WHERE uuid IN (...) list of bind parameters, the list is split
+// into several statements on MSSQL
+func selectUUIDInList(b *benchmark.Benchmark, c *benchmark.DBConnector, tableName string, uuids []interface{}) {
+	chunk := len(uuids)
+	if c.DbOpts.Driver == benchmark.MSSQL && chunk > mssqlInListChunk {
+		chunk = mssqlInListChunk
+	}
+
+	for start := 0; start < len(uuids); start += chunk {
+		end := benchmark.Min(start+chunk, len(uuids))
+		query := formatSQL(fmt.Sprintf("SELECT id, uuid FROM %s WHERE uuid IN (%s)", tableName, benchmark.GenDBParameterPlaceholders(0, end-start)), c.DbOpts.Driver)
+		c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, query, uuids[start:end]...)
+	}
+}
+
+// TestSelectHeavyInListSweep selects pages of random rows from the 'heavy' table by UUID IN-lists of growing size
+var TestSelectHeavyInListSweep = TestDesc{
+	name:        "select-heavy-rand-in-list-sweep",
+	metric:      "rows/sec",
+	description: "select a page of random rows from the 'heavy' table WHERE uuid IN ($1, $2, ...) with 10, 100, 1k and 10k uuids in the list and report the throughput and latency curve",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		origCollectLatencies := b.CollectLatencies
+		b.CollectLatencies = true
+		defer func() { b.CollectLatencies = origCollectLatencies }()
+
+		sizes := make([]string, len(inListSweepSizes))
+		for i, size := range inListSweepSizes {
+			sizes[i] = strconv.Itoa(size)
+		}
+
+		// the sample is twice the largest list, so the lists of every size pick their uuids from it at random
+		_, uuids := sampleKeys(b, testDesc.table.TableName, 2*inListSweepSizes[len(inListSweepSizes)-1])
+
+		testModes(b, "LIST SIZE", sizes, []string{"AVG, ms", "P99, ms"}, func(i int) []string {
+			defer setBatch(b, inListSweepSizes[i])()
+
+			counters := newTestCounters(b, inListCounters)
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				counters.add(inListLists, 1)
+
+				rw := b.Randomizer.GetWorker(c.WorkerID)
+				page := make([]interface{}, batch)
+				for n := range page {
+					page[n] = uuids[rw.Intn(len(uuids))]
+				}
+				selectUUIDInList(b, c, testDesc.table.TableName, page)
+
+				return batch
+			}, 1)
+
+			var avg float64
+			if lists := counters.get(inListLists); lists > 0 {
+				avg = b.Score.Seconds * float64(b.Score.Workers) * 1000 / float64(lists)
+			}
+
+			return []string{fmt.Sprintf("%.3f", avg), fmt.Sprintf("%.3f", float64(b.Score.P99)/float64(time.Millisecond))}
+		})
+	},
+}
+
+// TestSelectHeavyAnyArray selects a page of random rows from the 'heavy' table using = ANY(array) and compares it with IN-list
+// Only Postgres is supported as other engines have no array parameters binding
+var TestSelectHeavyAnyArray = TestDesc{
+	name:        "select-heavy-rand-any-array",
+	metric:      "rows/sec",
+	description: "select a page of random rows from the 'heavy' table WHERE id = ANY($1::bigint[]) and compare with the IN-list form, page size is set by --batch (default 100)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		defer setDefaultBatch(b, 100)()
+
+		workers := []testWorkerFunc{selectInListWorker, selectAnyArrayWorker}
+
+		testModes(b, "LOOKUP", []string{"IN (...)", "= ANY(array)"}, nil, func(i int) []string {
+			testGeneric(b, testDesc, workers[i], 1)
+
+			return nil
+		})
+	},
+}
+
+// naturalKeySampleSize is a max amount of (id, uuid) pairs loaded for the 'select-heavy-by-natural-key' test lookups
+const naturalKeySampleSize = 10000
+
+// keySampleChunk is the amount of random ids probed by a single query while sampling the keys, see sampleKeys()
+const keySampleChunk = 1000
+
+// sampleKeys returns up to size (id, uuid) pairs of the table rows sampled at random ids across the whole table, so the
+// lookups don't hit the same few hot pages; the ids deleted since the insert are just missing in the sample
+func sampleKeys(b *benchmark.Benchmark, tableName string, size int) (ids []int64, uuids []string) {
+	c := dbConnector(b)
+	defer c.Release()
+
+	var minID, maxID int64
+	c.QueryRowAndScanAllowEmpty(fmt.Sprintf("SELECT COALESCE(MIN(id), 0), COALESCE(MAX(id), 0) FROM %s", tableName), &minID, &maxID)
+
+	rw := benchmark.NewRandomizerWorker(b.CommonOpts.RandSeed, -1)
+	probes := make([]string, 0, keySampleChunk)
+
+	for n := 0; maxID > 0 && n < size; n += keySampleChunk {
+		probes = probes[:0]
+		for i := 0; i < keySampleChunk; i++ {
+			probes = append(probes, strconv.FormatInt(minID+int64(rw.Uintn64(uint64(maxID-minID+1))), 10))
+		}
+
+		sample := c.Select(tableName, "id, uuid", "id IN ("+strings.Join(probes, ",")+")", "", 0, false)
+		for sample.Next() {
+			var id int64
+			var uuid string
+			if err := sample.Scan(&id, &uuid); err != nil {
+				b.Exit("can't fetch the keys sample: %v", err)
+			}
+			ids = append(ids, id)
+			uuids = append(uuids, uuid)
+		}
+		sample.Close()
+	}
+
+	if len(ids) == 0 {
+		b.Exit("table '%s' has no rows, please insert it first and then re-run the test", tableName)
+	}
+
+	return ids, uuids
+}
+
+// TestSelectByNaturalKey looks up random rows of the 'heavy' table by the uuid natural key and by the int surrogate primary key
+var TestSelectByNaturalKey = TestDesc{
+	name:        "select-heavy-by-natural-key",
+	metric:      "lookups/sec",
+	description: "look up random row from the 'heavy' table WHERE uuid = {} (natural key) and WHERE id = {} (surrogate int primary key) and compare",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		ids, uuids := sampleKeys(b, testDesc.table.TableName, naturalKeySampleSize)
+
+		wheres := []func(i int) string{
+			func(i int) string { return fmt.Sprintf("id = %d", ids[i]) },
+			func(i int) string { return fmt.Sprintf("uuid = '%s'", uuids[i]) },
+		}
+
+		testModes(b, "LOOKUP KEY", []string{"int PK (id)", "natural (uuid)"}, nil, func(mode int) []string {
+			where := wheres[mode]
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				i := b.Randomizer.GetWorker(c.WorkerID).Intn(len(ids))
+				c.Select(testDesc.table.TableName, "id, uuid", where(i), "", 1, b.TestOpts.(*TestOpts).BenchOpts.Explain)
+
+				return 1
+			}, 1)
+
+			return nil
+		})
+	},
+}
+
+// TestSelectHeavyRandDBR selects random row from the 'heavy' table using golang DBR query builder
+var TestSelectHeavyRandDBR = TestDesc{
+	name:        "dbr-select-heavy-rand",
+	metric:      "rows/sec",
+	description: "select random row from the 'heavy' table using golang DBR query builder",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+			return fmt.Sprintf("id > %d", id)
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id ASC"
+		}
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+	},
+}
+
+// likePatternFormats are the LIKE pattern formats of every --like-position
+var likePatternFormats = map[string]string{
+	"prefix": "%s%%",
+	"suffix": "%%%s",
+	"infix":  "%%%s%%",
+}
+
+// likePattern returns the LIKE pattern built from the --like-pattern and --like-position options
+func likePattern(b *benchmark.Benchmark) string {
+	opts := b.TestOpts.(*TestOpts).TestcaseOpts
+
+	format, ok := likePatternFormats[opts.LikePosition]
+	if !ok {
+		b.Exit("unsupported LIKE position: '%s', supported values are: prefix|suffix|infix", opts.LikePosition)
+	}
+
+	pattern := opts.LikePattern
+	if pattern == "" {
+		pattern = benchmark.DataLocaleLetter()
+	}
+
+	return fmt.Sprintf(format, strings.ReplaceAll(pattern, "'", "''"))
+}
+
+// likePrefixIndex returns the columns of the 'heavy' table index serving the prefix LIKE search in a tenant, postgres
+// can use a btree index for LIKE only with the pattern operator class unless the database uses the C collation
+func likePrefixIndex(driver string) string {
+	if driver == benchmark.POSTGRES {
+		return "tenant_id, resource_name varchar_pattern_ops"
+	}
+
+	return "tenant_id, resource_name"
+}
+
+// TestSelectHeavyRandTenantLike selects random row from the 'heavy' table WHERE tenant_id = {} AND resource_name LIKE {}
+var TestSelectHeavyRandTenantLike = TestDesc{
+	name:        "select-heavy-rand-in-tenant-like",
+	metric:      "rows/sec",
+	description: "select random row from the 'heavy' table WHERE tenant_id = {} AND resource_name LIKE {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+		pattern := likePattern(b)
+
+		// only the prefix search can use an index, it's created for the test run and dropped afterwards
+		if b.TestOpts.(*TestOpts).TestcaseOpts.LikePosition == "prefix" {
+			t := TestTables[testDesc.table.TableName]
+			c := scratchConnector(b)
+			defer c.Close()
+
+			index := likePrefixIndex(c.DbOpts.Driver)
+			dropIndex := func() {
+				c.DropTableIndex(t.TableName, index, scratchIndexID(&t, 0))
+			}
+
+			defer cleanupOnExit(b, dropIndex)()
+
+			c.CreateIndex(t.TableName, index, scratchIndexID(&t, 0))
+		}
+
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+
+			return fmt.Sprintf("tenant_id = '%s' AND resource_name LIKE '%s'", (*w)["tenant_id"], pattern)
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id DESC"
+		}
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+
+		// multibyte strings change the LIKE matching cost and the index size, so report them to compare with the ascii run
+		if benchmark.DataLocale() != "ascii" {
+			c := dbConnector(b)
+			fmt.Fprintf(out(b), "data locale: %s; LIKE pattern: '%s'; table size: %d MB; indexes size: %d MB\n", benchmark.DataLocale(), pattern,
+				c.GetTableSizeMB(testDesc.table.TableName), c.GetIndexesSizeMB(testDesc.table.TableName))
+			c.Release()
+		}
+	},
+}
+
+// countQuery returns the query counting the table rows in given --count-mode and whether it's an estimate, the estimate
+// comes from the planner statistics, so it's only as fresh as the last ANALYZE / auto-update; the dialects w/o a cheap
+// estimate fall back to the exact count
+func countQuery(dialect string, table string, mode string) (string, bool) {
+	if mode == "estimate" {
+		switch dialect {
+		case benchmark.POSTGRES:
+			return fmt.Sprintf("SELECT CAST(reltuples AS BIGINT) FROM pg_class WHERE oid = '%s'::regclass", table), true
+		case benchmark.MSSQL:
+			return fmt.Sprintf("SELECT SUM(row_count) FROM sys.dm_db_partition_stats WHERE object_id = OBJECT_ID('%s') AND index_id IN (0, 1)", table), true
+		}
+	}
+
+	return fmt.Sprintf("SELECT COUNT(*) FROM %s", table), false
+}
+
+// TestSelectHeavyCount counts the rows of the 'heavy' table either exactly or by the statistics estimate (see --count-mode)
+var TestSelectHeavyCount = TestDesc{
+	name:        "select-heavy-count",
+	metric:      "queries/sec",
+	description: "count the rows of the 'heavy' table by COUNT(*) or by the planner statistics estimate (see --count-mode)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testOpts := b.TestOpts.(*TestOpts)
+		mode := testOpts.TestcaseOpts.CountMode
+		if mode != "exact" && mode != "estimate" {
+			b.Exit("unsupported count mode: '%s', supported values are: exact|estimate", mode)
+		}
+
+		dialect := testOpts.DBOpts.DialectName()
+		query, estimate := countQuery(dialect, testDesc.table.TableName, mode)
+		if mode == "estimate" && !estimate {
+			fmt.Fprintf(out(b), "count mode: the '%s' database has no cheap row count estimate, falling back to the exact count\n", dialect)
+		}
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			c.SelectRaw(testOpts.BenchOpts.Explain, query)
+
+			return 1
+		}, 1)
+
+		// the estimate accuracy matters as much as its cost
+		if estimate {
+			c := dbConnector(b)
+			var rows int64
+			c.QueryRowAndScan(query, &rows)
+
+			// postgres 14+ reports -1 reltuples until the table is vacuumed or analyzed for the first time
+			estimated := strconv.FormatInt(rows, 10)
+			if rows < 0 {
+				estimated = "unknown (the table has never been analyzed, run ANALYZE)"
+			}
+			fmt.Fprintf(out(b), "count mode: estimate; estimated rows: %s; exact rows: %d\n", estimated, c.GetRowsCount(testDesc.table.TableName, ""))
+			c.Release()
+		}
+	},
+}
+
+// TestSelectHeavyMinMaxTenant selects min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {}
+var TestSelectHeavyMinMaxTenant = TestDesc{
+	name:        "select-heavy-minmax-in-tenant",
+	metric:      "rows/sec",
+	description: "select min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+
+			return fmt.Sprintf("tenant_id = '%s'", (*w)["tenant_id"])
+		}
+		testSelect(b, testDesc, nil, "min(completion_time_ns), max(completion_time_ns)", where, nil, 1)
+	},
+}
+
+// TestSelectHeavyMinMaxTenantAndState selects min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {} AND state = {}
+var TestSelectHeavyMinMaxTenantAndState = TestDesc{
+	name:        "select-heavy-minmax-in-tenant-and-state",
+	metric:      "rows/sec",
+	description: "select min(completion_time_ns) and max(completion_time_ns) value from the 'heavy' table WHERE tenant_id = {} AND state = {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "state"}, false)
+
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+
+			return fmt.Sprintf("tenant_id = '%s' AND state = '%d'", (*w)["tenant_id"], (*w)["state"])
+		}
+		testSelect(b, testDesc, nil, "min(completion_time_ns), max(completion_time_ns)", where, nil, 1)
+	},
+}
+
+// rowsOrOne returns the number of fetched rows, but at least one to keep the worker loop running on empty results
+func rowsOrOne(rows *benchmark.DBRows) int {
+	if n := rows.Len(); n > 0 {
+		return n
+	}
+
+	return 1
+}
+
+// TestSelectHeavyRollup selects multi-level aggregates GROUP BY ROLLUP(tenant_id, state) from the 'heavy' table WHERE tenant_id = {}
+var TestSelectHeavyRollup = TestDesc{
+	name:        "select-heavy-rollup-in-tenant",
+	metric:      "rows/sec",
+	description: "select multi-level aggregates GROUP BY ROLLUP(tenant_id, state) from the 'heavy' table WHERE tenant_id = {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var groupBy string
+
+		switch getDBDriver(b) {
+		case benchmark.MYSQL:
+			groupBy = "GROUP BY tenant_id, state WITH ROLLUP"
+		default:
+			groupBy = "GROUP BY ROLLUP(tenant_id, state)"
+		}
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+			query := fmt.Sprintf("SELECT tenant_id, state, COUNT(*), MAX(completion_time_ns) FROM %s WHERE tenant_id = '%s' %s",
+				testDesc.table.TableName, (*w)["tenant_id"], groupBy)
+
+			return rowsOrOne(c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, query))
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// filteredCount returns the conditional COUNT(*) aggregate: FILTER clause on postgres and sqlite, COUNT(CASE WHEN ...) elsewhere
+func filteredCount(driver string, cond string) string {
+	switch driver {
+	case benchmark.POSTGRES, benchmark.SQLITE:
+		return fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", cond)
+	default:
+		return fmt.Sprintf("COUNT(CASE WHEN %s THEN 1 END)", cond)
+	}
+}
+
+// TestSelectHeavyFilteredAgg selects several conditional counts in a single scan from the 'heavy' table WHERE tenant_id = {}
+var TestSelectHeavyFilteredAgg = TestDesc{
+	name:        "select-heavy-filtered-agg-in-tenant",
+	metric:      "rows/sec",
+	description: "select several conditional counts COUNT(*) FILTER (WHERE ...) / COUNT(CASE WHEN ...) in a single scan from the 'heavy' table WHERE tenant_id = {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		driver := getDBDriver(b)
+
+		aggregates := []string{"COUNT(*)"}
+		for _, cond := range []string{"state = 1", "state = 2", "priority = 0", "progress >= 50", "progress = progress_total"} {
+			aggregates = append(aggregates, filteredCount(driver, cond))
+		}
+		what := strings.Join(aggregates, ", ")
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+			query := fmt.Sprintf("SELECT %s FROM %s WHERE tenant_id = '%s'", what, testDesc.table.TableName, (*w)["tenant_id"])
+
+			return rowsOrOne(c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, query))
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// pgAccessMethod returns the table access method postgres plans for given query, BitmapAnd takes precedence over the scans
+func pgAccessMethod(c *benchmark.DBConnector, query string) (string, error) {
+	return pgPlanNode(c, query, []string{"BitmapAnd", "Index Only Scan", "Index Scan", "Bitmap Heap Scan", "Seq Scan"})
+}
+
+// pgPlanNode returns the first of given plan nodes found in the postgres plan of the query, the nodes are in the order of precedence
+func pgPlanNode(c *benchmark.DBConnector, query string, nodes []string) (string, error) {
+	rows, err := c.Query("EXPLAIN " + query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	found := make(map[string]string)
+
+	for rows.Next() {
+		var line string
+		if err = rows.Scan(&line); err != nil {
+			return "", err
+		}
+		for _, m := range nodes {
+			if _, ok := found[m]; !ok && strings.Contains(line, m) {
+				found[m] = strings.TrimSpace(strings.TrimLeft(strings.SplitN(line, "  (cost=", 2)[0], " ->"))
+			}
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	for _, m := range nodes {
+		if plan, ok := found[m]; ok {
+			return plan, nil
+		}
+	}
+
+	return "unknown", nil
+}
+
+// TestSelectHeavyTwoPredicates selects rows matching two independent columns from the 'heavy' table using two single-column
+// indexes combined by BitmapAnd and then using a composite index
+var TestSelectHeavyTwoPredicates = TestDesc{
+	name:        "select-heavy-two-predicates",
+	metric:      "rows/sec",
+	description: "select rows from the 'heavy' table WHERE resource_type = {} AND progress = {} using two single-column indexes (BitmapAnd) and then a composite index",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		t := TestTables[testDesc.table.TableName]
+		explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+
+		singleIndexes := []string{"resource_type", "progress"}
+		compositeIndex := "resource_type, progress"
+		singleID := scratchIndexID(&t, 0)
+		compositeID := scratchIndexID(&t, len(singleIndexes))
+
+		c := scratchConnector(b)
+		defer c.Close()
+
+		dropIndexes := func() {
+			for n, columns := range singleIndexes {
+				c.DropTableIndex(t.TableName, columns, singleID+n)
+			}
+			c.DropTableIndex(t.TableName, compositeIndex, compositeID)
+		}
+
+		defer cleanupOnExit(b, dropIndexes)()
+
+		where := func(b *benchmark.Benchmark, workerID int) string {
+			rw := b.Randomizer.GetWorker(workerID)
+
+			return fmt.Sprintf("resource_type = %d AND progress = %d", rw.Intn(256), rw.Intn(100))
+		}
+
+		setups := []func(){
+			func() {
+				for n, columns := range singleIndexes {
+					c.CreateIndex(t.TableName, columns, singleID+n)
+				}
+			},
+			func() {
+				c.CreateIndex(t.TableName, compositeIndex, compositeID)
+			},
+		}
+
+		testModes(b, "INDEXES", []string{"two indexes", "composite index"}, []string{"PLAN"}, func(i int) []string {
+			dropIndexes()
+			setups[i]()
+			c.ExecOrExit("ANALYZE " + t.TableName)
+
+			access, err := pgAccessMethod(c, fmt.Sprintf("SELECT id FROM %s WHERE resource_type = 1 AND progress = 1", t.TableName))
+			if err != nil {
+				b.Exit("can't explain the query: %v", err)
+			}
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				return rowsOrOne(c.Select(testDesc.table.TableName, "id", where(b, c.WorkerID), "", 0, explain))
+			}, 1)
+
+			return []string{access}
+		})
+	},
+}
+
+// TestSelectHeavyExists selects a page of the 'heavy' table rows of the live tenants by EXISTS (semi-join) and then
+// the rest of the rows by NOT EXISTS (anti-join)
+var TestSelectHeavyExists = TestDesc{
+	name:        "select-heavy-exists",
+	metric:      "rows/sec",
+	description: "select a page of rows from the 'heavy' table WHERE EXISTS (SELECT 1 FROM tenants ...) (semi-join) and then WHERE NOT EXISTS (...) (anti-join)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		driver := getDBDriver(b)
+		explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+		from := testDesc.table.TableName + " h"
+		liveTenant := fmt.Sprintf("SELECT 1 FROM %s t WHERE t.uuid = h.tenant_id AND t.is_deleted = %s",
+			benchmark.TableNameTenants, benchmark.RenderBool(driver, false))
+
+		defer setDefaultBatch(b, 100)()
+
+		predicates := []string{"EXISTS (" + liveTenant + ")", "NOT EXISTS (" + liveTenant + ")"}
+
+		testModes(b, "PREDICATE", []string{"EXISTS", "NOT EXISTS"}, []string{"PLAN"}, func(i int) []string {
+			predicate := predicates[i]
+			plan := "n/a"
+			if driver == benchmark.POSTGRES {
+				c := dbConnector(b)
+				var err error
+				plan, err = pgPlanNode(c, fmt.Sprintf("SELECT h.id FROM %s WHERE %s", from, predicate), []string{"Semi Join", "Anti Join", "SubPlan", "Join"})
+				c.Release()
+				if err != nil {
+					b.Exit("can't explain the query: %v", err)
+				}
+			}
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				start := 0
+				if rowsCount := int(testDesc.table.RowsCount) - batch; rowsCount > 0 {
+					start = b.Randomizer.GetWorker(c.WorkerID).Intn(rowsCount)
+				}
+
+				return rowsOrOne(c.Select(from, "h.id, h.tenant_id", fmt.Sprintf("h.id > %d AND %s", start, predicate), "h.id ASC", batch, explain))
+			}, 1)
+
+			return []string{plan}
+		})
+	},
+}
+
+// the counters of a single mode of the 'select-heavy-wide-row' test
+const (
+	wideRowBytes = iota // bytes received
+	wideRowCounters
+)
+
+// TestSelectHeavyWideRow selects consecutive rows of the 'heavy' table fetching only the id and then all the columns,
+// so the network bound wide rows can be told from the narrow ones
+var TestSelectHeavyWideRow = TestDesc{
+	name:        "select-heavy-wide-row",
+	metric:      "rows/sec",
+	description: "select a page of consecutive rows from the 'heavy' table fetching the id only and then all the columns, report rows/sec and MB/sec received",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+
+		defer setDefaultBatch(b, 100)()
+
+		columns := []string{"id", "*"}
+
+		testModes(b, "COLUMNS", []string{"narrow (id)", "wide (*)"}, []string{"MB/SEC", "BYTES/ROW"}, func(i int) []string {
+			what := columns[i]
+			counters := newTestCounters(b, wideRowCounters)
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				from := 0
+				if rowsCount := int(testDesc.table.RowsCount) - batch; rowsCount > 0 {
+					from = b.Randomizer.GetWorker(c.WorkerID).Intn(rowsCount)
+				}
+
+				rows := c.Select(testDesc.table.TableName, what, fmt.Sprintf("id > %d", from), "id ASC", batch, explain)
+				counters.add(wideRowBytes, uint64(rows.Bytes()))
+
+				return rowsOrOne(rows)
+			}, 1)
+
+			mbPerSec := 0.0
+			if b.Score.Seconds > 0 {
+				mbPerSec = float64(counters.get(wideRowBytes)) / b.Score.Seconds / (1024 * 1024)
+			}
+			bytesPerRow := 0.0
+			if b.Score.Loops > 0 {
+				bytesPerRow = float64(counters.get(wideRowBytes)) / float64(b.Score.Loops)
+			}
+
+			return []string{fmt.Sprintf("%.2f", mbPerSec), fmt.Sprintf("%.0f", bytesPerRow)}
+		})
+	},
+}
+
+const lateralTenantsCount = 10 // lateralTenantsCount is a number of random tenants in the 'select-heavy-lateral' test
+
+// TestSelectHeavyLateral selects 3 latest rows per tenant for a random set of tenants from the 'heavy' table using LATERAL join (CROSS APPLY on MSSQL)
+var TestSelectHeavyLateral = TestDesc{
+	name:        "select-heavy-lateral",
+	metric:      "rows/sec",
+	description: "select 3 latest rows per tenant for a random set of tenants from the 'heavy' table using LATERAL join (CROSS APPLY on MSSQL)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		tableName := testDesc.table.TableName
+		tenantsSQL := "SELECT DISTINCT tenant_id FROM " + tableName + " WHERE tenant_id IN (%s)"
+
+		var queryTpl string
+
+		switch getDBDriver(b) {
+		case benchmark.MSSQL:
+			queryTpl = "SELECT t.tenant_id, l.id, l.update_time_ns FROM (" + tenantsSQL + ") t CROSS APPLY (" +
+				"SELECT TOP 3 h.id, h.update_time_ns FROM " + tableName + " h WHERE h.tenant_id = t.tenant_id ORDER BY h.update_time_ns DESC) l"
+		default:
+			queryTpl = "SELECT t.tenant_id, l.id, l.update_time_ns FROM (" + tenantsSQL + ") t, LATERAL (" +
+				"SELECT h.id, h.update_time_ns FROM " + tableName + " h WHERE h.tenant_id = t.tenant_id ORDER BY h.update_time_ns DESC LIMIT 3) l"
+		}
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			tenants := make([]string, 0, lateralTenantsCount)
+			for i := 0; i < lateralTenantsCount; i++ {
+				w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+				tenants = append(tenants, fmt.Sprintf("'%s'", (*w)["tenant_id"]))
+			}
+
+			query := fmt.Sprintf(queryTpl, strings.Join(tenants, ", "))
+
+			return rowsOrOne(c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, query))
+		}
+		testGeneric(b, testDesc, worker, 1)
+	},
+}
+
+// TestInsertMoney inserts a row with decimal and float amounts into the 'money' table
+var TestInsertMoney = TestDesc{
+	name:        "insert-money",
+	metric:      "rows/sec",
+	description: "insert a row with decimal (see --decimal-precision, --decimal-scale) and float amounts into the 'money' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableMoney,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		precision, scale := decimalPrecisionScale(b)
+
+		testDesc.table.InitColumnsConf()
+
+		for i := range testDesc.table.ColumnsConf {
+			if testDesc.table.ColumnsConf[i].ColumnType == "decimal" {
+				testDesc.table.ColumnsConf[i].MaxSize = precision
+				testDesc.table.ColumnsConf[i].MinSize = scale
+			}
+		}
+
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// moneySumWorker returns a worker summing given column of the 'money' table rows of a random tenant
+func moneySumWorker(column string) testWorkerFunc {
+	return func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		w := b.GenFakeDataAsMap(c.WorkerID, testDesc.table.GetColumnsConf([]string{"tenant_id"}, false), false)
+		query := fmt.Sprintf("SELECT SUM(%s) FROM %s WHERE tenant_id = '%s'", column, testDesc.table.TableName, (*w)["tenant_id"])
+
+		c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, query)
+
+		return 1
+	}
+}
+
+// TestSelectMoneySumByTenant sums decimal amounts of a random tenant in the 'money' table
+var TestSelectMoneySumByTenant = TestDesc{
+	name:        "select-money-sum-in-tenant",
+	metric:      "rows/sec",
+	description: "select SUM(amount) of decimal amounts from the 'money' table WHERE tenant_id = {}, compare with 'select-money-float-sum-in-tenant'",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableMoney,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, moneySumWorker("amount"), 1)
+	},
+}
+
+// TestSelectMoneyFloatSumByTenant sums float amounts of a random tenant in the 'money' table
+var TestSelectMoneyFloatSumByTenant = TestDesc{
+	name:        "select-money-float-sum-in-tenant",
+	metric:      "rows/sec",
+	description: "select SUM(amount_float) of float amounts from the 'money' table WHERE tenant_id = {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableMoney,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, moneySumWorker("amount_float"), 1)
+	},
+}
+
+// resultCacheQueries returns the same query with the engine result cache turned off and on, or nil if the engine has no result cache
+func resultCacheQueries(b *benchmark.Benchmark, query string) (cacheOff string, cacheOn string, ok bool) {
+	switch getDBDriver(b) {
+	case benchmark.CLICKHOUSE:
+		return query + " SETTINGS use_query_cache = 0", query + " SETTINGS use_query_cache = 1", true
+	case benchmark.MYSQL:
+		c := dbConnector(b)
+		_, version := c.GetVersion()
+		c.Release()
+
+		// the query cache has been removed in MySQL 8.0, but it is still available in MySQL 5.x and MariaDB
+		if strings.HasPrefix(version, "5.") || strings.Contains(strings.ToLower(version), "mariadb") {
+			return strings.Replace(query, "SELECT ", "SELECT SQL_NO_CACHE ", 1), strings.Replace(query, "SELECT ", "SELECT SQL_CACHE ", 1), true
+		}
+	}
+
+	return query, query, false
+}
+
+// TestSelectCacheHitRate repeats the same aggregate query over the 'medium' table with the engine result cache turned off and on
+var TestSelectCacheHitRate = TestDesc{
+	name:        "select-medium-cache-hit-rate",
+	metric:      "queries/sec",
+	description: "repeat the same aggregate query over the 'medium' table with the engine result cache off and on (MySQL 5.x/MariaDB query cache, ClickHouse query cache)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE, benchmark.CLICKHOUSE},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		query := fmt.Sprintf("SELECT COUNT(*), MAX(progress) FROM %s", testDesc.table.TableName)
+		cacheOff, cacheOn, ok := resultCacheQueries(b, query)
+
+		modes := []string{"off", "on"}
+		queries := []string{cacheOff, cacheOn}
+
+		if !ok {
+			fmt.Fprintf(out(b), "result cache: N/A, the '%s' engine has no native result cache, reporting the uncached rate only\n", getDBDriver(b))
+			modes = []string{"N/A"}
+		}
+
+		testModes(b, "RESULT CACHE", modes, nil, func(i int) []string {
+			q := queries[i]
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				c.SelectRaw(b.TestOpts.(*TestOpts).BenchOpts.Explain, q)
+
+				return 1
+			}, 1)
+
+			return nil
+		})
+	},
+}
+
+// TestSelectHeavyForUpdateSkipLocked selects a row from the 'heavy' table and then updates it
+var TestSelectHeavyForUpdateSkipLocked = TestDesc{
+	name:        "select-heavy-for-update-skip-locked",
+	metric:      "updates/sec",
+	description: "do SELECT FOR UPDATE SKIP LOCKED and then UPDATE",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   withCockroach(RELATIONAL),
+	requires:    []benchmark.DBFeature{benchmark.FeatureSkipLocked},
+	table:       TestTableHeavy,
+	fallbacks: []TestFallback{{
+		emulates:     benchmark.FeatureSkipLocked,
+		requires:     []benchmark.DBFeature{benchmark.FeatureAdvisoryLocks},
+		emulation:    "the rows are claimed by non-blocking advisory locks",
+		launcherFunc: testForUpdateAdvisoryLocks,
+	}},
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var query string
+		max := b.CommonOpts.Workers*2 + 1
+
+		switch b.TestOpts.(*TestOpts).DBOpts.Driver {
+		case benchmark.POSTGRES, benchmark.MYSQL:
+			// CockroachDB accepts the postgres syntax since 23.1, the older versions are skipped, see FeatureSkipLocked
+			query = fmt.Sprintf("SELECT id, progress FROM acronis_db_bench_heavy WHERE id < %d LIMIT 1 FOR UPDATE SKIP LOCKED", max)
+		case benchmark.MSSQL:
+			query = fmt.Sprintf("SELECT TOP(1) id, progress FROM acronis_db_bench_heavy WITH (UPDLOCK, READPAST, ROWLOCK) WHERE id < %d", max)
+		default:
+			b.Exit("unsupported driver: '%v', supported drivers are: %s|%s|%s", b.TestOpts.(*TestOpts).DBOpts.Driver, benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL)
+		}
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			// the row stays locked till the commit, a serialization failure or a deadlock retries the whole transaction
+			err := c.Transact(func() error {
+				rows, err := c.Query(query)
+				if err != nil {
+					return err
+				}
+
+				var id int64
+				var progress int
+				found := rows.Next()
+				if found {
+					err = rows.Scan(&id, &progress)
+				}
+				if err == nil {
+					err = rows.Err()
+				}
+				rows.Close()
+				if err != nil || !found {
+					return err
+				}
+
+				_, err = c.Exec(fmt.Sprintf("UPDATE acronis_db_bench_heavy SET progress = %d WHERE id = %d", progress+1, id))
+
+				return err
+			})
+			if err != nil {
+				c.Exit(err.Error())
+			}
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 10000)
+	},
+}
+
+// testForUpdateAdvisoryLocks emulates 'select-heavy-for-update-skip-locked' claiming the first row not claimed by other
+// workers with a non-blocking advisory lock instead of skipping the locked rows
+func testForUpdateAdvisoryLocks(b *benchmark.Benchmark, testDesc *TestDesc) {
+	var tryLock, unlock string
+	max := b.CommonOpts.Workers*2 + 1
+
+	switch b.TestOpts.(*TestOpts).DBOpts.Driver {
+	case benchmark.POSTGRES:
+		// released on commit
+		tryLock = "SELECT CASE WHEN pg_try_advisory_xact_lock(%d) THEN 1 ELSE 0 END"
+	case benchmark.MYSQL:
+		// GET_LOCK() is owned by the session, so it's released right before the commit, the worker claiming the row next
+		// waits for the commit on the row lock then
+		tryLock = "SELECT GET_LOCK('acronis_db_bench_heavy_%d', 0)"
+		unlock = "SELECT RELEASE_LOCK('acronis_db_bench_heavy_%d')"
+	case benchmark.MSSQL:
+		// released on commit
+		tryLock = "DECLARE @r int; EXEC @r = sp_getapplock @Resource = 'acronis_db_bench_heavy_%d', @LockMode = 'Exclusive', " +
+			"@LockOwner = 'Transaction', @LockTimeout = 0; SELECT CASE WHEN @r >= 0 THEN 1 ELSE 0 END"
+	default:
+		b.Exit("unsupported driver: '%v', supported drivers are: %s|%s|%s", b.TestOpts.(*TestOpts).DBOpts.Driver, benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL)
+	}
+
+	worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		c.Begin()
+
+		for id := 1; id < max; id++ {
+			var acquired int
+			c.QueryRowAndScan(fmt.Sprintf(tryLock, id), &acquired)
+			if acquired != 1 {
+				continue
+			}
+
+			var progress int
+			c.QueryRowAndScan(fmt.Sprintf("SELECT progress FROM acronis_db_bench_heavy WHERE id = %d", id), &progress)
+			c.ExecOrExit(fmt.Sprintf("UPDATE acronis_db_bench_heavy SET progress = %d WHERE id = %d", progress+1, id))
+
+			if unlock != "" {
+				c.QueryRowAndScan(fmt.Sprintf(unlock, id), &acquired)
+			}
+
+			break
+		}
+
+		c.Commit()
+
+		return 1
+	}
+	testGeneric(b, testDesc, worker, 10000)
+}
+
+// TestTransactionChattyVsBatched updates rows of the 'heavy' table in a transaction sending every statement separately
+// and then sending the whole transaction as a single multi-statement query
+var TestTransactionChattyVsBatched = TestDesc{
+	name:        "transaction-chatty-vs-batched",
+	metric:      "transactions/sec",
+	description: "update --batch random rows of the 'heavy' table in a transaction using a round-trip per statement and then a single multi-statement round-trip",
+	category:    TestTransaction,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var begin, commit string
+
+		switch getDBDriver(b) {
+		case benchmark.MSSQL:
+			begin, commit = "BEGIN TRANSACTION", "COMMIT TRANSACTION"
+		case benchmark.MYSQL:
+			begin, commit = "START TRANSACTION", "COMMIT"
+		default:
+			begin, commit = "BEGIN", "COMMIT"
+		}
+
+		defer setDefaultBatch(b, 10)()
+		statements := b.Vault.(*DBTestData).EffectiveBatch
+
+		// the rows are updated in the id order, so the concurrent transactions don't deadlock
+		updates := func(b *benchmark.Benchmark, workerID int, rowsCount uint64) []string {
+			rw := b.Randomizer.GetWorker(workerID)
+			ids := make([]int, statements)
+			for i := range ids {
+				ids[i] = 1 + rw.Intn(int(rowsCount))
+			}
+			sort.Ints(ids)
+
+			ret := make([]string, 0, statements)
+			for _, id := range ids {
+				ret = append(ret, fmt.Sprintf("UPDATE %s SET progress = progress + 1 WHERE id = %d", testDesc.table.TableName, id))
+			}
+
+			return ret
+		}
+
+		// mysql runs multi-statement queries on the connections opened with multiStatements=true only
+		var batchConns []*benchmark.DBConnector
+		if getDBDriver(b) == benchmark.MYSQL {
+			batchConns = make([]*benchmark.DBConnector, b.CommonOpts.Workers)
+		}
+		batchConn := func(c *benchmark.DBConnector) *benchmark.DBConnector {
+			if batchConns == nil {
+				return c
+			}
+			if batchConns[c.WorkerID] == nil {
+				opts := *c.DbOpts
+				opts.Dsn = mysqlMultiStatementsDSN(opts.Dsn)
+				batchConns[c.WorkerID] = benchmark.NewDBConnector(&opts, c.WorkerID, b.Logger, 10)
+			}
+
+			return batchConns[c.WorkerID]
+		}
+
+		roundTrips := []int{statements + 2, 1}
+		workers := []testWorkerFunc{
+			func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				c.Begin()
+				for _, update := range updates(b, c.WorkerID, testDesc.table.RowsCount) {
+					c.ExecOrExit(update)
+				}
+				c.Commit()
+
+				return 1
+			},
+			func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				query := append([]string{begin}, updates(b, c.WorkerID, testDesc.table.RowsCount)...)
+				query = append(query, commit)
+				batchConn(c).ExecOrExit(strings.Join(query, "; "))
+
+				return 1
+			},
+		}
+
+		latencies := make([]float64, 0, len(workers))
+
+		testModes(b, "MODE", []string{"chatty", "batched"}, []string{"ROUND-TRIPS", "TX LATENCY, ms"}, func(i int) []string {
+			testGeneric(b, testDesc, workers[i], 1)
+
+			// average transaction latency as seen by a single worker
+			latency := 0.0
+			if b.Score.Loops > 0 {
+				latency = b.Score.Seconds * float64(b.Score.Workers) / float64(b.Score.Loops) * 1000
+			}
+			latencies = append(latencies, latency)
+
+			return []string{strconv.Itoa(roundTrips[i]), fmt.Sprintf("%.3f", latency)}
+		})
+
+		for _, c := range batchConns {
+			if c != nil {
+				c.Close()
+			}
+		}
+
+		if len(latencies) == 2 {
+			saved := latencies[0] - latencies[1]
+			fmt.Fprintf(out(b), "saved per transaction: %.3f ms, per round-trip: %.3f ms\n", saved, saved/float64(statements+1))
+		}
+	},
+}
+
+// the counters of the 'select-heavy-for-update-nowait' test
+const (
+	nowaitAttempts  = iota // SELECT FOR UPDATE NOWAIT attempts
+	nowaitAcquired         // attempts which acquired the row lock immediately
+	nowaitConflicts        // attempts failed on a lock conflict
+	nowaitCounters
+)
+
+// TestSelectHeavyForUpdateNowait selects random row from the 'heavy' table FOR UPDATE NOWAIT and then updates it
+var TestSelectHeavyForUpdateNowait = TestDesc{
+	name:        "select-heavy-for-update-nowait",
+	metric:      "ops/sec",
+	description: "do SELECT FOR UPDATE NOWAIT and then UPDATE, lock conflicts fail fast and are counted",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var query string
+		hot := b.CommonOpts.Workers * 2
+
+		switch b.TestOpts.(*TestOpts).DBOpts.Driver {
+		case benchmark.POSTGRES, benchmark.MYSQL:
+			query = "SELECT progress FROM acronis_db_bench_heavy WHERE id = %d FOR UPDATE NOWAIT"
+		case benchmark.MSSQL:
+			query = "SELECT progress FROM acronis_db_bench_heavy WITH (UPDLOCK, ROWLOCK, NOWAIT) WHERE id = %d"
+		default:
+			b.Exit("unsupported driver: '%v', supported drivers are: %s|%s|%s", b.TestOpts.(*TestOpts).DBOpts.Driver, benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL)
+		}
+
+		counters := newTestCounters(b, nowaitCounters)
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			id := 1 + b.Randomizer.GetWorker(c.WorkerID).Intn(hot)
+			progress := 0
+
+			counters.add(nowaitAttempts, 1)
+			c.Begin()
+
+			rows, err := c.Query(fmt.Sprintf(query, id))
+			if err == nil {
+				for rows.Next() {
+					err = rows.Scan(&progress)
+				}
+				if err == nil {
+					err = rows.Err()
+				}
+				rows.Close()
+			}
+
+			if benchmark.IsLockNotAvailable(err) {
+				c.Rollback()
+				counters.add(nowaitConflicts, 1)
+
+				return 1
+			} else if err != nil {
+				c.Exit(err.Error())
+			}
+
+			c.ExecOrExit(fmt.Sprintf("UPDATE acronis_db_bench_heavy SET progress = %d WHERE id = %d", progress+1, id))
+			c.Commit()
+			counters.add(nowaitAcquired, 1)
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, uint64(hot))
+
+		fmt.Fprintf(out(b), "attempts: %d; acquired immediately: %d (%.2f%%); lock conflicts: %d (%.2f%%)\n",
+			counters.get(nowaitAttempts), counters.get(nowaitAcquired), counters.percent(nowaitAcquired, nowaitAttempts),
+			counters.get(nowaitConflicts), counters.percent(nowaitConflicts, nowaitAttempts))
+	},
+}
+
+// queueInsertRows is a max amount of jobs enqueued by a single INSERT statement
+const queueInsertRows = 500
+
+// the counters of the 'queue-consume' test, partial and empty claims are the scans wasted on the rows locked by other consumers
+const (
+	queueClaims        = iota // claim transactions
+	queueJobs                 // claimed jobs
+	queuePartialClaims        // claims which got less than --batch jobs
+	queueEmptyClaims          // claims which got no jobs
+	queueCounters
+)
+
+// enqueueJobs replaces the content of the 'queue' table by given amount of pending jobs
+func enqueueJobs(b *benchmark.Benchmark, testDesc *TestDesc, jobs int) {
+	c := dbConnector(b)
+	defer c.Release()
+
+	driver := getDBDriver(b)
+	t := TestTables[testDesc.table.TableName]
+	t.Create(c, b)
+	c.ExecOrExit(fmt.Sprintf("DELETE FROM %s", testDesc.table.TableName))
+
+	colConfs := testDesc.table.GetColumnsConf([]string{"payload"}, false)
+
+	for enqueued := 0; enqueued < jobs; {
+		rows := jobs - enqueued
+		if rows > queueInsertRows {
+			rows = queueInsertRows
+		}
+
+		placeholders := make([]string, rows)
+		values := make([]interface{}, rows)
+		for i := 0; i < rows; i++ {
+			w := b.GenFakeDataAsMap(0, colConfs, false)
+			placeholders[i] = fmt.Sprintf("(0, $%d)", i+1)
+			values[i] = (*w)["payload"]
+		}
+
+		c.ExecOrExit(formatSQL(fmt.Sprintf("INSERT INTO %s (state, payload) VALUES %s", testDesc.table.TableName, strings.Join(placeholders, ", ")), driver), values...)
+		enqueued += rows
+	}
+}
+
+// TestQueueConsume claims a batch of pending jobs from the 'queue' table by SKIP LOCKED and marks them processed
+var TestQueueConsume = TestDesc{
+	name:        "queue-consume",
+	metric:      "jobs/sec",
+	description: "claim --batch (default 10) pending jobs from the 'queue' table by SELECT FOR UPDATE SKIP LOCKED, mark them processed and commit, see also --queue-jobs",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableQueue,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		defer setDefaultBatch(b, 10)()
+
+		tableName := testDesc.table.TableName
+		enqueueJobs(b, testDesc, b.TestOpts.(*TestOpts).TestcaseOpts.QueueJobs)
+
+		var claimQuery string
+		switch getDBDriver(b) {
+		case benchmark.MSSQL:
+			claimQuery = "SELECT TOP(%[2]d) id FROM %[1]s WITH (UPDLOCK, READPAST, ROWLOCK) WHERE state = 0 ORDER BY id"
+		default:
+			claimQuery = "SELECT id FROM %[1]s WHERE state = 0 ORDER BY id LIMIT %[2]d FOR UPDATE SKIP LOCKED"
+		}
+
+		counters := newTestCounters(b, queueCounters)
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			c.Begin()
+
+			rows := c.SelectRaw(false, fmt.Sprintf(claimQuery, tableName, batch))
+
+			var ids []string
+			for rows.Next() {
+				var id int64
+				if err := rows.Scan(&id); err != nil {
+					c.Exit("can't fetch job id: %v", err)
+				}
+				ids = append(ids, strconv.FormatInt(id, 10))
+			}
+
+			counters.add(queueClaims, 1)
+
+			if len(ids) == 0 {
+				c.Commit()
+				counters.add(queueEmptyClaims, 1)
+
+				// all the pending jobs are either processed or locked by other consumers
+				if c.GetRowsCount(tableName, "state = 0") == 0 {
+					return 0
+				}
+
+				return 1
+			}
+
+			c.ExecOrExit(fmt.Sprintf("UPDATE %s SET state = 1 WHERE id IN (%s)", tableName, strings.Join(ids, ", ")))
+			c.Commit()
+
+			counters.add(queueJobs, uint64(len(ids)))
+			if len(ids) < batch {
+				counters.add(queuePartialClaims, 1)
+			}
+
+			return len(ids)
+		}, 0)
+
+		jobsPerClaim := 0.0
+		if claims := counters.get(queueClaims); claims > 0 {
+			jobsPerClaim = float64(counters.get(queueJobs)) / float64(claims)
+		}
+		fmt.Fprintf(out(b), "claims: %d; jobs: %d; jobs per claim: %.1f; partial claims: %d (%.2f%%); empty claims: %d (%.2f%%)\n",
+			counters.get(queueClaims), counters.get(queueJobs), jobsPerClaim,
+			counters.get(queuePartialClaims), counters.percent(queuePartialClaims, queueClaims),
+			counters.get(queueEmptyClaims), counters.percent(queueEmptyClaims, queueClaims))
+	},
+}
+
+// TestInsertLight inserts a row into the 'light' table
+var TestInsertLight = TestDesc{
+	name:        "insert-light",
+	metric:      "rows/sec",
+	description: "insert a row into the 'light' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   withCockroach(ALL),
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// insertKeyOrderColumnTypes maps --insert-key-order values to the primary key fake column types
+var insertKeyOrderColumnTypes = map[string]string{
+	"sequential": "seq_key", // monotonic zero-padded int, always appended to the end of the index
+	"random":     "uuid",    // random UUID v4, causes index page splits and fragmentation
+	"uuid":       "uuid_v7", // time-ordered UUID v7, mostly appended to the end of the index
+}
+
+// TestInsertKeyOrder inserts a row into the 'key_order' table with primary key generated in given order (see --insert-key-order)
+var TestInsertKeyOrder = TestDesc{
+	name:        "insert-key-order",
+	metric:      "rows/sec",
+	description: "insert a row into the 'key_order' table with sequential, random (UUID v4) or time-ordered (UUID v7) primary key, see --insert-key-order",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableKeyOrder,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		keyOrder := b.TestOpts.(*TestOpts).TestcaseOpts.InsertKeyOrder
+		columnType, ok := insertKeyOrderColumnTypes[keyOrder]
+		if !ok {
+			b.Exit("unsupported insert key order: '%s', supported values are: sequential|random|uuid", keyOrder)
+		}
+
+		setKeyColumnType(testDesc, columnType)
+
+		b.Log(benchmark.LogInfo, 0, fmt.Sprintf("insert key order: %s", keyOrder))
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// setKeyColumnType sets the fake column type of the 'id' primary key of the 'key_order' table
+func setKeyColumnType(testDesc *TestDesc, columnType string) {
+	testDesc.table.InitColumnsConf()
+
+	for i := range testDesc.table.ColumnsConf {
+		if testDesc.table.ColumnsConf[i].ColumnName == "id" {
+			testDesc.table.ColumnsConf[i].ColumnType = columnType
+		}
+	}
+}
+
+// uuidVersionColumnTypes maps --uuid-version values to the primary key fake column types
+var uuidVersionColumnTypes = map[int]string{
+	4: "uuid",
+	7: "uuid_v7",
+}
+
+// TestInsertUUIDPK inserts a row into the 'key_order' table with UUID primary key of given version (see --uuid-version)
+var TestInsertUUIDPK = TestDesc{
+	name:        "insert-uuid-pk",
+	metric:      "rows/sec",
+	description: "insert a row into the 'key_order' table with random (v4) or time-ordered (v7) UUID primary key, see --uuid-version",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableKeyOrder,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		insertUUIDPK(b, testDesc, b.TestOpts.(*TestOpts).TestcaseOpts.UUIDVersion)
+	},
+}
+
+// insertUUIDPK inserts the rows with UUID primary key of given version into the 'key_order' table
+func insertUUIDPK(b *benchmark.Benchmark, testDesc *TestDesc, version int) {
+	columnType, ok := uuidVersionColumnTypes[version]
+	if !ok {
+		b.Exit("unsupported UUID version: %d, supported values are: 4|7", version)
+	}
+
+	setKeyColumnType(testDesc, columnType)
+
+	b.Log(benchmark.LogInfo, 0, fmt.Sprintf("UUID version: %d", version))
+	testInsertGeneric(b, testDesc)
+}
+
+const (
+	uuidRangeRecent = time.Minute // uuidRangeRecent is the age of the newest keys the 'select-by-uuid-range' test scans
+	uuidRangeWindow = time.Second // uuidRangeWindow is the time window of keys the 'select-by-uuid-range' test scans at once
+)
+
+// TestSelectByUUIDRange selects the UUID v7 keys generated within a random second of the last minute of the 'key_order' table
+var TestSelectByUUIDRange = TestDesc{
+	name:        "select-by-uuid-range",
+	metric:      "rows/sec",
+	description: "select the 'key_order' table rows WHERE id >= {} AND id < {} for the UUID v7 bounds of a random second among the newest keys, see --uuid-version",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableKeyOrder,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		version := b.TestOpts.(*TestOpts).TestcaseOpts.UUIDVersion
+		if _, ok := uuidVersionColumnTypes[version]; !ok {
+			b.Exit("unsupported UUID version: %d, supported values are: 4|7", version)
+		}
+		if version != 7 {
+			fmt.Fprintf(out(b), "WARNING: UUID v%d keys are not time-ordered, so the time window range scan is meaningless for them\n", version)
+		}
+
+		selectByUUIDRange(b, testDesc)
+	},
+}
+
+// selectByUUIDRange range scans the UUID v7 keys of the 'key_order' table generated within a random time window
+func selectByUUIDRange(b *benchmark.Benchmark, testDesc *TestDesc) {
+	var minID, maxID string
+
+	c := dbConnector(b)
+	if c.TableExists(testDesc.table.TableName) {
+		c.QueryRowAndScan(fmt.Sprintf("SELECT COALESCE(MIN(id), ''), COALESCE(MAX(id), '') FROM %s", testDesc.table.TableName), &minID, &maxID)
+	}
+	c.Release()
+
+	oldest, err := benchmark.UUIDv7Millis(minID)
+	if err == nil {
+		_, err = benchmark.UUIDv7Millis(maxID)
+	}
+	if err != nil {
+		b.Exit("the '%s' table has no UUID keys (%v), run the 'insert-uuid-pk' test first", testDesc.table.TableName, err)
+	}
+	newest, _ := benchmark.UUIDv7Millis(maxID)
+
+	from := newest - uuidRangeRecent.Milliseconds()
+	if from < oldest {
+		from = oldest
+	}
+	span := newest - from + 1
+	explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+
+	testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		lo := from + b.Randomizer.GetWorker(c.WorkerID).Seeded().Int63n(span)
+		where := fmt.Sprintf("id >= '%s' AND id < '%s'", benchmark.UUIDv7Bound(lo), benchmark.UUIDv7Bound(lo+uuidRangeWindow.Milliseconds()))
+
+		return rowsOrOne(c.Select(testDesc.table.TableName, "id", where, "id ASC", batch, explain))
+	}, 1)
+}
+
+// TestUUIDPKVersions compares the UUID v4 and v7 primary keys of the 'key_order' table on inserts and range scans
+var TestUUIDPKVersions = TestDesc{
+	name:        "uuid-pk-versions",
+	metric:      "rows/sec",
+	description: "insert rows with random (v4) and time-ordered (v7) UUID primary key into the emptied 'key_order' table, then range scan the v7 keys, and compare the rates",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableKeyOrder,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		versions := []int{4, 7}
+		modes := []string{"v4", "v7"}
+
+		var v4Rate float64
+		testModes(b, "UUID", modes, []string{"VS V4", "RANGE SCAN"}, func(i int) []string {
+			// every version starts from the empty table, so the index page splits are caused by its keys only
+			c := dbConnector(b)
+			t := TestTables[testDesc.table.TableName]
+			t.Create(c, b)
+			c.ExecOrExit("DELETE FROM " + testDesc.table.TableName)
+			c.Release()
+
+			insertUUIDPK(b, testDesc, versions[i])
+
+			values := []string{"n/a", "n/a"}
+			if i == 0 {
+				v4Rate = b.Score.Rate
+			} else if v4Rate > 0 {
+				values[0] = fmt.Sprintf("%+.1f%%", (b.Score.Rate-v4Rate)*100/v4Rate)
+			}
+
+			if versions[i] == 7 && !b.NeedToExit {
+				// the range scan score is reported as the extra column, the mode rate stays the insert one
+				insertScore := b.Score
+				selectByUUIDRange(b, &TestSelectByUUIDRange)
+				values[1] = b.Score.FormatRate(4) + " " + b.Score.Metric
+				b.Score = insertScore
+			}
+
+			return values
+		})
+	},
+}
+
+// idManualSequence is the sequence generating the ids of the 'id_manual' table in the 'insert-max-id-plus-one' test
+const idManualSequence = "acronis_db_bench_id_manual_seq"
+
+// the counters of a single id generation mode of the 'insert-max-id-plus-one' test
+const (
+	idGenInserted   = iota // inserted rows
+	idGenDuplicates        // inserts failed on a duplicate id
+	idGenConflicts         // inserts failed on a serialization conflict
+	idGenCounters
+)
+
+// TestInsertMaxIDPlusOne inserts rows with the ids generated as SELECT MAX(id)+1, by a sequence and by an identity column
+// of the 'id_identity' table
+var TestInsertMaxIDPlusOne = TestDesc{
+	name:        "insert-max-id-plus-one",
+	metric:      "rows/sec",
+	description: "insert a row with the id generated as SELECT MAX(id)+1 (anti-pattern), by a sequence and by an identity column, report the duplicate-key error rate",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableIDManual,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		driver := getDBDriver(b)
+		manualTable := testDesc.table.TableName
+		identityTable := TestTableIDIdentity.TableName
+
+		c := dbConnector(b)
+		for _, name := range []string{manualTable, identityTable} {
+			t := TestTables[name]
+			t.Create(c, b)
+		}
+		c.CreateSequence(idManualSequence)
+		c.Release()
+
+		insertManual := formatSQL(fmt.Sprintf("INSERT INTO %s (id, uuid) VALUES ($1, $2)", manualTable), driver)
+		insertIdentity := formatSQL(fmt.Sprintf("INSERT INTO %s (uuid) VALUES ($1)", identityTable), driver)
+
+		// nextID is called before the transaction start, the sequence values are not transactional anyway
+		modes := []struct {
+			nextID func(c *benchmark.DBConnector) uint64
+			insert func(c *benchmark.DBConnector, id uint64, uuid string) error
+		}{
+			{nil, func(c *benchmark.DBConnector, _ uint64, uuid string) error {
+				var id int64
+				c.QueryRowAndScan(fmt.Sprintf("SELECT COALESCE(MAX(id), 0) + 1 FROM %s", manualTable), &id)
+				_, err := c.Exec(insertManual, id, uuid)
+
+				return err
+			}},
+			{func(c *benchmark.DBConnector) uint64 {
+				return c.GetNextVal(idManualSequence)
+			}, func(c *benchmark.DBConnector, id uint64, uuid string) error {
+				_, err := c.Exec(insertManual, id, uuid)
+
+				return err
+			}},
+			{nil, func(c *benchmark.DBConnector, _ uint64, uuid string) error {
+				_, err := c.Exec(insertIdentity, uuid)
+
+				return err
+			}},
+		}
+
+		extra := []string{"INSERTED/SEC", "DUPLICATES", "DUPLICATE %", "CONFLICTS"}
+
+		testModes(b, "ID", []string{"MAX(id)+1", "sequence", "identity"}, extra, func(i int) []string {
+			mode := modes[i]
+			counters := newTestCounters(b, idGenCounters)
+
+			// MAX(id)+1 and the sequence share the table, so the ids of the previous mode must not collide, and every mode
+			// starts with the empty table
+			c = dbConnector(b)
+			c.ExecOrExit("DELETE FROM " + manualTable)
+			c.ExecOrExit("DELETE FROM " + identityTable)
+			c.Release()
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				var id uint64
+				if mode.nextID != nil {
+					id = mode.nextID(c)
+				}
+
+				c.Begin()
+
+				err := mode.insert(c, id, b.Randomizer.GetWorker(c.WorkerID).UUID())
+				switch {
+				case err == nil:
+					c.Commit()
+					counters.add(idGenInserted, 1)
+				case benchmark.IsUniqueViolation(err):
+					c.Rollback()
+					counters.add(idGenDuplicates, 1)
+				case benchmark.IsRetryableTxError(err):
+					c.Rollback()
+					counters.add(idGenConflicts, 1)
+				default:
+					c.Exit("can't insert a row: %v", err)
+				}
+
+				return 1
+			}, 0)
+
+			insertedRate := 0.0
+			if b.Score.Seconds > 0 {
+				insertedRate = float64(counters.get(idGenInserted)) / b.Score.Seconds
+			}
+
+			return []string{
+				fmt.Sprintf("%.1f", insertedRate),
+				strconv.FormatUint(counters.get(idGenDuplicates), 10),
+				fmt.Sprintf("%.2f%%", counters.percent(idGenDuplicates, idGenInserted, idGenDuplicates, idGenConflicts)),
+				strconv.FormatUint(counters.get(idGenConflicts), 10),
+			}
+		})
+	},
+}
+
+// tenantSchemaPrefix is the name prefix of the tenant schemas (postgres) or databases (mysql), see --tenant-isolation
+const tenantSchemaPrefix = "acronis_db_bench_tenant_"
+
+// tenantSchemaName returns the name of the schema (postgres) or database (mysql) of given tenant, see --tenant-isolation
+func tenantSchemaName(n int) string {
+	return fmt.Sprintf("%s%d", tenantSchemaPrefix, n)
+}
+
+// dropTenantSchemas drops all the tenant schemas with their tables whatever --tenant-schemas they were created with
+func dropTenantSchemas(c *benchmark.DBConnector) {
+	var query string
+
+	switch c.DbOpts.Driver {
+	case benchmark.POSTGRES:
+		query = "DROP SCHEMA IF EXISTS %s CASCADE"
+	case benchmark.MYSQL:
+		query = "DROP DATABASE IF EXISTS %s"
+	default:
+		return
+	}
+
+	rows, err := c.Query(fmt.Sprintf("SELECT schema_name FROM information_schema.schemata WHERE schema_name LIKE '%s%%'",
+		strings.ReplaceAll(tenantSchemaPrefix, "_", "\\_")))
+	if err != nil {
+		c.Exit("can't list the tenant schemas: %v", err)
+	}
+
+	var schemas []string
+	for rows.Next() {
+		var schema string
+		if err = rows.Scan(&schema); err != nil {
+			c.Exit("can't list the tenant schemas: %v", err)
+		}
+		schemas = append(schemas, schema)
+	}
+	rows.Close()
+
+	for _, schema := range schemas {
+		c.ExecOrExit(fmt.Sprintf(query, schema))
+	}
+}
+
+// createTenantSchemas creates given amount of tenant schemas, each one having its own copy of given table
+func createTenantSchemas(b *benchmark.Benchmark, table *TestTable, schemas int) {
+	c := dbConnector(b)
+	defer c.Release()
+
+	for n := 0; n < schemas; n++ {
+		schema := tenantSchemaName(n)
+
+		switch c.DbOpts.Driver {
+		case benchmark.POSTGRES:
+			c.ExecOrExit(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema))
+		case benchmark.MYSQL:
+			c.ExecOrExit(fmt.Sprintf("CREATE DATABASE IF NOT EXISTS %s", schema))
+		}
+
+		exists := false
+		c.QueryRowAndScan(fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = '%s' AND table_name = '%s')",
+			schema, table.TableName), &exists)
+		if exists {
+			continue
+		}
+
+		// TableExists() doesn't look at the schema, so create the tenant's copy by the schema-qualified name and w/o the shared table indexes
+		t := *table
+		t.TableName = schema + "." + table.TableName
+		t.Indexes = nil
+		t.Create(c, b)
+	}
+}
+
+// the counters of the 'tenant-isolation-insert-select' test
+const (
+	tenantSwitches   = iota // schema switches
+	tenantSwitchTime        // time spent switching the schemas, nsec
+	tenantCounters
+)
+
+// TestTenantIsolation inserts a row and selects the tenant's last row either from the shared 'medium' table filtering by tenant_id
+// or from the tenant's own schema copy of the table, see --tenant-isolation
+var TestTenantIsolation = TestDesc{
+	name:        "tenant-isolation-insert-select",
+	metric:      "ops/sec",
+	description: "insert a row and select the tenant's last row using shared table with tenant_id or schema per tenant, see --tenant-isolation",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		isolation := b.TestOpts.(*TestOpts).TestcaseOpts.TenantIsolation
+		tenants := b.TestOpts.(*TestOpts).TestcaseOpts.TenantSchemas
+		tableName := testDesc.table.TableName
+
+		if isolation != "column" && isolation != "schema" {
+			b.Exit("unsupported tenant isolation: '%s', supported values are: column|schema", isolation)
+		}
+		if tenants <= 0 {
+			b.Exit("--tenant-schemas must be positive")
+		}
+
+		var defaultDB string
+		if isolation == "schema" {
+			createTenantSchemas(b, &testDesc.table, tenants)
+
+			if getDBDriver(b) == benchmark.MYSQL {
+				c := dbConnector(b)
+				c.QueryRowAndScan("SELECT DATABASE()", &defaultDB)
+				c.Release()
+			}
+		}
+
+		insertSQL := formatSQL(fmt.Sprintf("INSERT INTO %s (uuid, tenant_id, euc_id, progress) VALUES ($1, $2, $3, $4)", tableName), getDBDriver(b))
+		selectSQL := fmt.Sprintf("SELECT id, progress FROM %s WHERE tenant_id = '%%s' ORDER BY id DESC LIMIT 1", tableName)
+
+		counters := newTestCounters(b, tenantCounters)
+
+		// switchTo switches the session to given tenant schema, the empty schema restores the default one
+		switchTo := func(c *benchmark.DBConnector, schema string) {
+			var query string
+
+			switch {
+			case c.DbOpts.Driver == benchmark.POSTGRES && schema != "":
+				// SET LOCAL is reverted on COMMIT, so pooled connections are never left in the tenant's schema
+				query = fmt.Sprintf("SET LOCAL search_path TO %s", schema)
+			case c.DbOpts.Driver == benchmark.POSTGRES:
+				return
+			case schema != "":
+				query = fmt.Sprintf("USE %s", schema)
+			default:
+				query = fmt.Sprintf("USE %s", defaultDB)
+			}
+
+			start := time.Now()
+			c.ExecOrExit(query)
+			counters.addTime(tenantSwitchTime, start)
+			counters.add(tenantSwitches, 1)
+		}
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			rw := b.Randomizer.GetWorker(c.WorkerID)
+			n := rw.Intn(tenants)
+			tenantID := fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+
+			// the transaction pins a single connection of the pool for the schema switch and the queries
+			c.Begin()
+
+			if isolation == "schema" {
+				switchTo(c, tenantSchemaName(n))
+			}
+
+			c.ExecOrExit(insertSQL, rw.UUID(), tenantID, rw.Intn(2147483647), rw.Intn(100))
+
+			var id int64
+			var progress int
+			c.QueryRowAndScan(fmt.Sprintf(selectSQL, tenantID), &id, &progress)
+
+			if isolation == "schema" {
+				switchTo(c, "")
+			}
+
+			c.Commit()
+
+			return 1
+		}, 0)
+
+		fmt.Fprintf(out(b), "tenant isolation: %s; tenants: %d\n", isolation, tenants)
+		if n := counters.get(tenantSwitches); n > 0 && b.Score.Loops > 0 {
+			spent := float64(counters.get(tenantSwitchTime))
+			loopTime := b.Score.Seconds * float64(b.Score.Workers) / float64(b.Score.Loops)
+			switchPerLoop := spent / float64(time.Second) / float64(b.Score.Loops)
+			fmt.Fprintf(out(b), "schema switches: %d; avg switch: %.3f ms; switch cost per query: %.3f ms (%.1f%% of the loop time)\n",
+				n, spent/float64(n)/float64(time.Millisecond), switchPerLoop/2*1000, switchPerLoop*100/loopTime)
+		}
+	},
+}
+
+// insertByPreparedDataWorker inserts a row into the 'light' table using prepared statement for the batch
+func insertByPreparedDataWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+	workerID := c.WorkerID
+
+	tx := c.Begin()
+
+	columns, _ := b.GenFakeData(workerID, colConfs, false)
+
+	parametersPlaceholder := genParameterPlaceholders(b, colConfs)
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s)", testDesc.table.TableName, strings.Join(columns, ","), parametersPlaceholder)
+	sql = formatSQL(sql, c.DbOpts.Driver)
+
+	t := c.StatementEnter(sql, nil)
+	stmt, err := tx.Prepare(sql)
+	c.StatementExit("Prepare()", t, err, false, nil, sql, nil, nil, nil)
+
+	if err != nil {
+		c.Exit(err.Error())
+	}
+	for i := 0; i < batch; i++ {
+		_, values := b.GenFakeData(workerID, colConfs, false)
+		pgParamValues(b, colConfs, values)
+
+		t := c.StatementEnter("", nil)
+		_, err = stmt.Exec(values...)
+		c.StatementExit("Exec()", t, err, false, nil, "<< stdin ", values, nil, nil)
+
+		if err != nil {
+			stmt.Close() //nolint:sqlclosecheck
+			c.Exit(err.Error())
+		}
+	}
+	c.Commit()
+
+	return batch
+}
+
+// TestInsertLightPrepared inserts a row into the 'light' table using prepared statement for the batch
+var TestInsertLightPrepared = TestDesc{
+	name:        "insert-light-prepared",
+	metric:      "rows/sec",
+	description: "insert a row into the 'light' table using prepared statement for the batch",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+	},
+}
+
+// multiValueInsertQuery returns INSERT INTO t (x, y, z) VALUES (..., ..., ...), (...) query for given amount of rows
+func multiValueInsertQuery(tableName string, columns []string, rows int) string {
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", tableName, strings.Join(columns, ","))
+
+	for i := 0; i < rows; i++ {
+		if i == 0 {
+			sql = fmt.Sprintf("%s (%s)", sql, benchmark.GenDBParameterPlaceholders(i*len(columns), len(columns)))
+		} else {
+			sql = fmt.Sprintf("%s, (%s)", sql, benchmark.GenDBParameterPlaceholders(i*len(columns), len(columns)))
+		}
+	}
+
+	return sql
+}
+
+// insertMultiValueDataWorker inserts a row into the 'light' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...)
+func insertMultiValueDataWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+	workerID := c.WorkerID
+
+	columns, _ := b.GenFakeData(workerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+	if c.DbOpts.Driver == benchmark.CASSANDRA {
+		var values []interface{}
+		var sql string
+
+		sqlInsertTpl := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", testDesc.table.TableName, strings.Join(columns, ","))
+
+		for i := 0; i < batch; i++ {
+			sql = fmt.Sprintf("%s\n%s (%s);", sql, sqlInsertTpl, benchmark.GenDBParameterPlaceholdersCassandra(i*len(*colConfs), len(*colConfs)))
+		}
+
+		sql = formatSQL(sql, c.DbOpts.Driver)
+
+		for i := 0; i < batch; i++ {
+			_, vals := b.GenFakeData(workerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+			values = append(values, vals...)
+		}
+
+		sql = fmt.Sprintf("BEGIN BATCH%s\nAPPLY BATCH;", sql)
+		c.ExecOrExit(sql, values...)
+
+		return batch
+	}
+
+	var values []interface{}
+
+	sql := formatSQL(multiValueInsertQuery(testDesc.table.TableName, columns, batch), c.DbOpts.Driver)
+
+	for i := 0; i < batch; i++ {
+		_, vals := b.GenFakeData(workerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+		values = append(values, vals...)
+	}
+
+	c.Begin()
+	c.ExecOrExit(sql, values...)
+	c.Commit()
+
+	return batch
+}
+
+// TestInsertLightMultiValue inserts a row into the 'light' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...)
+var TestInsertLightMultiValue = TestDesc{
+	name:        "insert-light-multivalue",
+	metric:      "rows/sec",
+	description: "insert a row into the 'light' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) ",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   PMWSA,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertMultiValueDataWorker, 0)
+	},
+}
+
+// returningIdsQuery returns the multi-value INSERT returning the generated ids: RETURNING id (postgres, sqlite) or OUTPUT INSERTED.id (MSSQL),
+// empty string means the dialect can't return them
+func returningIdsQuery(driver string, insertSQL string) string {
+	switch driver {
+	case benchmark.POSTGRES, benchmark.SQLITE:
+		return insertSQL + " RETURNING id"
+	case benchmark.MSSQL:
+		return strings.Replace(insertSQL, " VALUES ", " OUTPUT INSERTED.id VALUES ", 1)
+	default:
+		return ""
+	}
+}
+
+// TestBulkInsertReturningIds inserts --batch rows into the 'light' table by a multi-value INSERT collecting all the generated ids
+// and compares with the same INSERT not returning them
+var TestBulkInsertReturningIds = TestDesc{
+	name:        "insert-light-multivalue-returning-ids",
+	metric:      "rows/sec",
+	description: "insert --batch rows into the 'light' table by INSERT INTO t (x, y) VALUES (...), (...) RETURNING id / OUTPUT INSERTED.id collecting all the generated ids, compare with the INSERT w/o returning",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		driver := getDBDriver(b)
+
+		defer setDefaultBatch(b, 100)()
+
+		colConfs := testDesc.table.GetColumnsForInsert(false)
+
+		// insert generates the batch of rows and returns the query and its arguments
+		insert := func(b *benchmark.Benchmark, workerID int, batch int) (string, []interface{}) {
+			var columns []string
+			values := make([]interface{}, 0, batch*len(*colConfs))
+
+			for i := 0; i < batch; i++ {
+				var vals []interface{}
+				columns, vals = b.GenFakeData(workerID, colConfs, false)
+				values = append(values, vals...)
+			}
+
+			return formatSQL(multiValueInsertQuery(testDesc.table.TableName, columns, batch), driver), values
+		}
+
+		// collect returns the generated ids of the inserted batch
+		collect := func(c *benchmark.DBConnector, insertSQL string, values []interface{}) []int64 {
+			ids := make([]int64, 0, len(values))
+
+			if query := returningIdsQuery(driver, insertSQL); query != "" {
+				rows, err := c.Query(query, values...)
+				if err != nil {
+					c.Exit(err.Error())
+				}
+				defer rows.Close()
+
+				for rows.Next() {
+					var id int64
+					if err = rows.Scan(&id); err != nil {
+						c.Exit(err.Error())
+					}
+					ids = append(ids, id)
+				}
+				if err = rows.Err(); err != nil {
+					c.Exit(err.Error())
+				}
+
+				return ids
+			}
+
+			// MySQL returns the first generated id of the batch only, the rest are derived from it which is correct
+			// as long as the ids of a multi-value INSERT are consecutive (innodb_autoinc_lock_mode 0, 1 or 2 for simple inserts)
+			result, err := c.Exec(insertSQL, values...)
+			if err != nil {
+				c.Exit(err.Error())
+			}
+			first, err := result.LastInsertId()
+			if err != nil {
+				c.Exit(err.Error())
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				c.Exit(err.Error())
+			}
+			for i := int64(0); i < rowsAffected; i++ {
+				ids = append(ids, first+i)
+			}
+
+			return ids
+		}
+
+		returningMode := "RETURNING id"
+		switch driver {
+		case benchmark.MSSQL:
+			returningMode = "OUTPUT INSERTED.id"
+		case benchmark.MYSQL:
+			returningMode = "LastInsertId (EMULATED)"
+		}
+
+		rates := testModes(b, "MODE", []string{"no returning", returningMode}, nil, func(i int) []string {
+			returning := i == 1
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				insertSQL, values := insert(b, c.WorkerID, batch)
+
+				if !returning {
+					c.ExecOrExit(insertSQL, values...)
+
+					return batch
+				}
+
+				if ids := collect(c, insertSQL, values); len(ids) != batch {
+					c.Exit("got %d generated ids for %d inserted rows", len(ids), batch)
+				}
+
+				return batch
+			}, 0)
+
+			return nil
+		})
+
+		if len(rates) == 2 && rates[1] > 0 {
+			fmt.Fprintf(out(b), "returning ids overhead: %.1f%%\n", (rates[0]/rates[1]-1)*100)
+		}
+		if driver == benchmark.MYSQL {
+			fmt.Fprintf(out(b), "NOTE: MySQL returns the first generated id of a multi-value INSERT only, the rest of the ids are derived from it\n")
+		}
+	},
+}
+
+// copyChunk copies given amount of rows into the table using a single COPY statement in a separate transaction
+func copyChunk(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, colConfs *[]benchmark.DBFakeColumnConf, columns []string, rows int) error {
+	var sql string
+	workerID := c.WorkerID
+
+	tx := c.Begin()
+
+	switch c.DbOpts.Driver {
+	case benchmark.POSTGRES:
+		sql = pq.CopyIn(testDesc.table.TableName, columns...)
+	case benchmark.MSSQL:
+		sql = mssql.CopyIn(testDesc.table.TableName, mssql.BulkOptions{KeepNulls: true, RowsPerBatch: rows}, columns...)
+	default:
+		b.Exit("unsupported driver: '%v', supported drivers are: %s|%s", b.TestOpts.(*TestOpts).DBOpts.Driver, benchmark.POSTGRES, benchmark.MSSQL)
+	}
+
+	t := c.StatementEnter(sql, nil)
+	stmt, err := tx.Prepare(sql)
+	c.StatementExit("Prepare()", t, err, false, nil, sql, nil, nil, nil)
+
+	if err != nil {
+		c.Rollback()
+		return err
+	}
+	for i := 0; i < rows; i++ {
+		_, values := b.GenFakeData(workerID, colConfs, false)
+
+		t := c.StatementEnter("", nil)
+		_, err = stmt.Exec(values...)
+		c.StatementExit("Exec()", t, err, false, nil, "<< stdin ", values, nil, nil)
+
+		if err != nil {
+			stmt.Close() //nolint:sqlclosecheck
+			c.Rollback()
+			return err
+		}
+	}
+	_, err = stmt.Exec()
+	if err != nil {
+		stmt.Close()
+		c.Rollback()
+		return err
+	}
+	c.Commit()
+
+	return nil
+}
+
+// copyDataWorker copies a row into the 'light' table
+func copyDataWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+	columns, _ := b.GenFakeData(c.WorkerID, colConfs, false)
+
+	chunk := b.TestOpts.(*TestOpts).TestcaseOpts.CopyCommitRows
+	if chunk <= 0 || chunk > batch {
+		chunk = batch
+	}
+
+	for loops < batch {
+		rows := batch - loops
+		if rows > chunk {
+			rows = chunk
+		}
+
+		if err := copyChunk(b, c, testDesc, colConfs, columns, rows); err != nil {
+			c.Exit(err.Error())
+		}
+		loops += rows
+
+		testData := b.Vault.(*DBTestData)
+		for peak := atomic.LoadUint64(&testData.CopyPeakTxRows); uint64(rows) > peak; peak = atomic.LoadUint64(&testData.CopyPeakTxRows) {
+			if atomic.CompareAndSwapUint64(&testData.CopyPeakTxRows, peak, uint64(rows)) {
+				break
+			}
+		}
+
+		// the in-flight chunk is already committed, so just stop copying the rest of the batch
+		if b.NeedToExit {
+			break
+		}
+	}
+
+	return loops
+}
+
+// testCopy runs the COPY test and reports the peak transaction size if --copy-commit-rows is set
+func testCopy(b *benchmark.Benchmark, testDesc *TestDesc) {
+	atomic.StoreUint64(&b.Vault.(*DBTestData).CopyPeakTxRows, 0)
+
+	testGeneric(b, testDesc, copyDataWorker, 0)
+
+	if b.TestOpts.(*TestOpts).TestcaseOpts.CopyCommitRows > 0 {
+		fmt.Fprintf(out(b), "COPY commit every: %d rows; peak transaction size: %d rows\n",
+			b.TestOpts.(*TestOpts).TestcaseOpts.CopyCommitRows, atomic.LoadUint64(&b.Vault.(*DBTestData).CopyPeakTxRows))
+	}
+}
+
+// TestCopyLight copies a row into the 'light' table
+var TestCopyLight = TestDesc{
+	name:        "copy-light",
+	metric:      "rows/sec",
+	description: "copy a row into the 'light' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testCopy(b, testDesc)
+	},
+}
+
+// TestInsertLightDBR inserts a row into the 'light' table using goland DBR query builder
+var TestInsertLightDBR = TestDesc{
+	name:        "dbr-insert-light",
+	metric:      "rows/sec",
+	description: "insert a row into the 'light' table using goland DBR query builder",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableLight,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestInsertMedium inserts a row into the 'medium' table
+var TestInsertMedium = TestDesc{
+	name:        "insert-medium",
+	metric:      "rows/sec",
+	description: "insert a row into the 'medium' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   withCockroach(ALL),
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestInsertMediumPrepared inserts a row into the 'medium' table using prepared statement for the batch
+var TestInsertMediumPrepared = TestDesc{
+	name:        "insert-medium-prepared",
+	metric:      "rows/sec",
+	description: "insert a row into the 'medium' table using prepared statement for the batch",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+	},
+}
+
+// TestInsertMediumMultiValue inserts a row into the 'medium' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...)
+var TestInsertMediumMultiValue = TestDesc{
+	name:        "insert-medium-multivalue",
+	metric:      "rows/sec",
+	description: "insert a row into the 'medium' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) ",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   PMWSA,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertMultiValueDataWorker, 0)
+	},
+}
+
+// TestCopyMedium copies a row into the 'medium' table
+var TestCopyMedium = TestDesc{
+	name:        "copy-medium",
+	metric:      "rows/sec",
+	description: "copy a row into the 'medium' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testCopy(b, testDesc)
+	},
+}
+
+// TestInsertMediumDBR inserts a row into the 'medium' table using goland DBR query builder
+var TestInsertMediumDBR = TestDesc{
+	name:        "dbr-insert-medium",
+	metric:      "rows/sec",
+	description: "insert a row into the 'medium' table using goland DBR query builder",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// setBlobColumnsConf applies --min-blob-size, --max-blob-size and --blob-compressible to the blob columns of the test table
+func setBlobColumnsConf(b *benchmark.Benchmark, testDesc *TestDesc) {
+	testcaseOpts := b.TestOpts.(*TestOpts).TestcaseOpts
+	if testcaseOpts.BlobCompressible < 0 || testcaseOpts.BlobCompressible > 100 {
+		b.Exit("--blob-compressible must be within 0..100")
+	}
+
+	testDesc.table.InitColumnsConf()
+	for i := range testDesc.table.ColumnsConf {
+		conf := &testDesc.table.ColumnsConf[i]
+		if conf.ColumnType != "blob" && conf.ColumnType != "compressible_blob" {
+			continue
+		}
+
+		conf.MaxSize = testcaseOpts.MaxBlobSize
+		conf.MinSize = testcaseOpts.MinBlobSize
+		if testcaseOpts.BlobCompressible > 0 {
+			conf.ColumnType = "compressible_blob"
+			conf.Cardinality = testcaseOpts.BlobCompressible
+		}
+	}
+}
+
+// TestInsertBlob inserts a row with large random blob into the 'blob' table
+var TestInsertBlob = TestDesc{
+	name:        "insert-blob",
+	metric:      "rows/sec",
+	description: "insert a row with large random blob into the 'blob' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   ALL,
+	table:       TestTableBlob,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		setBlobColumnsConf(b, testDesc)
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestCopyBlob copies a row with large random blob into the 'blob' table
+var TestCopyBlob = TestDesc{
+	name:        "copy-blob",
+	metric:      "rows/sec",
+	description: "copy a row with large random blob into the 'blob' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
+	table:       TestTableBlob,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		setBlobColumnsConf(b, testDesc)
+		testCopy(b, testDesc)
+	},
+}
+
+// parsePayloadSizes parses the --payload-sizes option and returns payload size bands in ascending order
+func parsePayloadSizes(b *benchmark.Benchmark, payloadSizes string) []int {
+	var sizes []int
+
+	for _, s := range strings.Split(payloadSizes, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || size <= 0 {
+			b.Exit("invalid payload size '%s' in --payload-sizes=%s, positive integers are expected", s, payloadSizes)
+		}
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+
+	return sizes
+}
+
+// TestInsertGrowingPayload inserts rows into the 'blob' table growing the payload size band by band (see --payload-sizes)
+var TestInsertGrowingPayload = TestDesc{
+	name:        "insert-growing-payload",
+	metric:      "rows/sec",
+	description: "insert rows into the 'blob' table growing the payload size band by band (see --payload-sizes) to find the off-page storage cliff",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableBlob,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		sizes := parsePayloadSizes(b, b.TestOpts.(*TestOpts).TestcaseOpts.PayloadSizes)
+		bands := make([]string, len(sizes))
+		for i, size := range sizes {
+			bands[i] = strconv.Itoa(size)
+		}
+
+		testDesc.table.InitColumnsConf()
+
+		testModes(b, "PAYLOAD (BYTES)", bands, nil, func(band int) []string {
+			size := sizes[band]
+			for i := range testDesc.table.ColumnsConf {
+				if testDesc.table.ColumnsConf[i].ColumnType == "blob" {
+					testDesc.table.ColumnsConf[i].MaxSize = size
+					testDesc.table.ColumnsConf[i].MinSize = size
+				}
+			}
+
+			b.Log(benchmark.LogInfo, 0, fmt.Sprintf("payload size band: %d bytes", size))
+			testInsertGeneric(b, testDesc)
+
+			return nil
+		})
+	},
+}
+
+// createLargeObjectWorker inserts a row with large random object into the 'largeobject' table
+func createLargeObjectWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+	parametersPlaceholder := benchmark.GenDBParameterPlaceholders(0, len(*colConfs))
+	testOpts := b.TestOpts.(*TestOpts)
+	workerID := c.WorkerID
+
+	c.Begin()
+
+	var sql string
+
+	for i := 0; i < batch; i++ {
+		columns, values := b.GenFakeData(workerID, colConfs, false)
+
+		blob := b.GenFakeValue(workerID, "blob", "", 0, b.TestOpts.(*TestOpts).TestcaseOpts.MaxBlobSize, b.TestOpts.(*TestOpts).TestcaseOpts.MinBlobSize, "")
+
+		var oid int
+		var fd int
+
+		c.QueryRowAndScan("SELECT lo_create(0)", &oid)
+		c.QueryRowAndScan(fmt.Sprintf("SELECT lo_open(%d, 131072)", oid), &fd) // 131072 == 0x20000 - write mode
+
+		c.ExecOrExit("SELECT lowrite($1, $2)", fd, blob)
+		c.ExecOrExit("SELECT lo_close($1)", fd)
+
+		for c := range columns {
+			if columns[c] == "oid" {
+				values[c] = oid
+			}
+		}
+
+		if i == 0 {
+			insertSQL := "INSERT INTO %s (%s) VALUES(%s)"
+			sqlTemplate := fmt.Sprintf(insertSQL, testDesc.table.TableName, strings.Join(columns, ","), parametersPlaceholder)
+			sql = formatSQL(sqlTemplate, testOpts.DBOpts.Driver)
+		}
+
+		c.ExecOrExit(sql, values...)
+	}
+	c.Commit()
+
+	return batch
+}
+
+// TestInsertLargeObj inserts a row with large random object into the 'largeobject' table
+var TestInsertLargeObj = TestDesc{
+	name:        "insert-largeobj",
+	metric:      "rows/sec",
+	description: "insert a row with large random object into the 'largeobject' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableLargeObj,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, createLargeObjectWorker, 0)
+	},
+}
+
+// the counters of a single mode of the 'insert-check-then-insert' test
+const (
+	ctiApplied          = iota // rows inserted or upserted
+	ctiAlreadyExists           // keys found by the check
+	ctiUniqueViolations        // inserts failed on a key inserted by another worker after the check
+	ctiFailures                // inserts or upserts failed on other errors
+	ctiCounters
+)
+
+// upsertQuery returns driver specific atomic insert-or-update query for the 'unique keys' table
+func upsertQuery(driver string, tableName string) string {
+	switch driver {
+	case benchmark.MYSQL:
+		return fmt.Sprintf("INSERT INTO %s (key_id, value) VALUES (?, ?) ON DUPLICATE KEY UPDATE value = VALUES(value)", tableName)
+	case benchmark.MSSQL:
+		return fmt.Sprintf("MERGE %s WITH (HOLDLOCK) AS dst USING (SELECT ? AS key_id, ? AS value) AS src ON dst.key_id = src.key_id "+
+			"WHEN MATCHED THEN UPDATE SET value = src.value WHEN NOT MATCHED THEN INSERT (key_id, value) VALUES (src.key_id, src.value);", tableName)
+	default:
+		return formatSQL(fmt.Sprintf("INSERT INTO %s (key_id, value) VALUES ($1, $2) ON CONFLICT (key_id) DO UPDATE SET value = excluded.value", tableName), driver)
+	}
+}
+
+// tsLatestUpsertQuery returns driver specific query upserting the last value of the (device, metric) pair
+func tsLatestUpsertQuery(driver string, tableName string) string {
+	switch driver {
+	case benchmark.MYSQL:
+		return fmt.Sprintf("INSERT INTO %s (device_id, metric_id, ts, value) VALUES (?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE value = VALUES(value), ts = VALUES(ts)", tableName)
+	case benchmark.MSSQL:
+		return fmt.Sprintf("MERGE %s WITH (HOLDLOCK) AS dst USING (SELECT ? AS device_id, ? AS metric_id, ? AS ts, ? AS value) AS src "+
+			"ON dst.device_id = src.device_id AND dst.metric_id = src.metric_id "+
+			"WHEN MATCHED THEN UPDATE SET value = src.value, ts = src.ts "+
+			"WHEN NOT MATCHED THEN INSERT (device_id, metric_id, ts, value) VALUES (src.device_id, src.metric_id, src.ts, src.value);", tableName)
+	default:
+		return formatSQL(fmt.Sprintf("INSERT INTO %s (device_id, metric_id, ts, value) VALUES ($1, $2, $3, $4) "+
+			"ON CONFLICT (device_id, metric_id) DO UPDATE SET value = excluded.value, ts = excluded.ts", tableName), driver)
+	}
+}
+
+// TestUpsertTimeSeriesLatest upserts the last value of random (device, metric) pair into the 'ts_latest' table
+var TestUpsertTimeSeriesLatest = TestDesc{
+	name:        "upsert-ts-latest",
+	metric:      "upserts/sec",
+	description: "upsert the last value of random (device, metric) pair into the 'ts_latest' table (last-value cache), keyspace is set by --ts-latest-devices and --ts-latest-metrics",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	table:       TestTableTimeSeriesLatest,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		devices := b.TestOpts.(*TestOpts).TestcaseOpts.TSLatestDevices
+		metrics := b.TestOpts.(*TestOpts).TestcaseOpts.TSLatestMetrics
+		if devices <= 0 || metrics <= 0 {
+			b.Exit("--ts-latest-devices and --ts-latest-metrics must be positive")
+		}
+
+		tableName := testDesc.table.TableName
+		upsertSQL := tsLatestUpsertQuery(getDBDriver(b), tableName)
+
+		c := dbConnector(b)
+		t := TestTables[tableName]
+		t.Create(c, b)
+		rowsBefore := c.GetRowsCount(tableName, "")
+		c.Release()
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			rw := b.Randomizer.GetWorker(c.WorkerID)
+
+			c.ExecOrExit(upsertSQL, rw.Intn(devices), rw.Intn(metrics), time.Now().UTC(), rw.Intn(100))
+
+			return 1
+		}, 0)
+
+		c = dbConnector(b)
+		inserted := c.GetRowsCount(tableName, "") - rowsBefore
+		c.Release()
+
+		// every upsert not adding a new row has hit an existing (device, metric) pair
+		var conflictRate float64
+		if b.Score.Loops > 0 && uint64(b.Score.Loops) >= inserted {
+			conflictRate = float64(uint64(b.Score.Loops)-inserted) * 100 / float64(b.Score.Loops)
+		}
+
+		fmt.Fprintf(out(b), "keyspace: %d devices x %d metrics; upserts: %d; new keys: %d; updates of existing keys: %.2f%%\n",
+			devices, metrics, b.Score.Loops, inserted, conflictRate)
+	},
+}
+
+// counterUpsertQuery returns driver specific query atomically adding the hits to the counter, inserting it if absent
+func counterUpsertQuery(driver string, tableName string) string {
+	switch driver {
+	case benchmark.MYSQL:
+		return fmt.Sprintf("INSERT INTO %s (counter_key, hits) VALUES (?, ?) ON DUPLICATE KEY UPDATE hits = hits + VALUES(hits)", tableName)
+	case benchmark.MSSQL:
+		return fmt.Sprintf("MERGE %s WITH (HOLDLOCK) AS dst USING (SELECT ? AS counter_key, ? AS hits) AS src ON dst.counter_key = src.counter_key "+
+			"WHEN MATCHED THEN UPDATE SET hits = dst.hits + src.hits WHEN NOT MATCHED THEN INSERT (counter_key, hits) VALUES (src.counter_key, src.hits);", tableName)
+	default:
+		return formatSQL(fmt.Sprintf("INSERT INTO %[1]s (counter_key, hits) VALUES ($1, $2) "+
+			"ON CONFLICT (counter_key) DO UPDATE SET hits = %[1]s.hits + excluded.hits", tableName), driver)
+	}
+}
+
+// the counters of a single key space of the 'upsert-counter' test
+const (
+	counterIncrements = iota // increments committed
+	counterRetries           // transactions retried after deadlock or serialization failure
+	counterFailures          // transactions failed after all the retries
+	counterCounters
+)
+
+// TestUpsertCounter increments random counters of a small key space using atomic INSERT ... ON CONFLICT DO UPDATE
+var TestUpsertCounter = TestDesc{
+	name:        "upsert-counter",
+	metric:      "upserts/sec",
+	description: "increment --batch random counters per transaction using atomic INSERT ... ON CONFLICT DO UPDATE SET hits = hits + excluded.hits, shrinking the key space (see --counter-keyspaces) and reporting contention",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	table:       TestTableCounters,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var keyspaces []int
+		for _, s := range strings.Split(b.TestOpts.(*TestOpts).TestcaseOpts.CounterKeyspaces, ",") {
+			keyspace, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || keyspace <= 0 {
+				b.Exit("invalid key space '%s' in --counter-keyspaces, positive integers are expected", s)
+			}
+			keyspaces = append(keyspaces, keyspace)
+		}
+
+		tableName := testDesc.table.TableName
+		upsertSQL := counterUpsertQuery(getDBDriver(b), tableName)
+
+		// several counters per transaction, so concurrent transactions may lock them in the opposite order
+		defer setDefaultBatch(b, 4)()
+
+		names := make([]string, len(keyspaces))
+		for i, keyspace := range keyspaces {
+			names[i] = strconv.Itoa(keyspace)
+		}
+
+		testModes(b, "KEYSPACE", names, []string{"RETRIES", "FAILED", "LOST UPDATES"}, func(i int) []string {
+			keyspace := keyspaces[i]
+
+			c := dbConnector(b)
+			t := TestTables[tableName]
+			t.Create(c, b)
+			c.ExecOrExit("DELETE FROM " + tableName)
+			c.Release()
+
+			counters := newTestCounters(b, counterCounters)
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				rw := b.Randomizer.GetWorker(c.WorkerID)
+
+				keys := make([]int, batch)
+				for i := range keys {
+					keys[i] = rw.Intn(keyspace)
+				}
+
+				attempts := 0
+				err := c.Transact(func() error {
+					attempts++
+					for _, key := range keys {
+						if _, err := c.Exec(upsertSQL, key, 1); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				})
+
+				counters.add(counterRetries, uint64(attempts-1))
+				if err != nil {
+					if !benchmark.IsRetryableTxError(err) {
+						c.Exit(err.Error())
+					}
+					counters.add(counterFailures, 1)
+
+					// zero loops would stop the worker
+					return 1
+				}
+				counters.add(counterIncrements, uint64(batch))
+
+				return batch
+			}, 0)
+
+			// every committed increment must be visible in the counters, otherwise an update has been lost
+			var hits int64
+			c = dbConnector(b)
+			c.QueryRowAndScan(fmt.Sprintf("SELECT COALESCE(SUM(hits), 0) FROM %s", tableName), &hits)
+			c.Release()
+
+			var retryRate float64
+			if txs := counters.get(counterIncrements)/uint64(b.Vault.(*DBTestData).EffectiveBatch) + counters.get(counterFailures); txs > 0 {
+				retryRate = float64(counters.get(counterRetries)) * 100 / float64(txs)
+			}
+
+			return []string{
+				fmt.Sprintf("%.2f%%", retryRate),
+				strconv.FormatUint(counters.get(counterFailures), 10),
+				strconv.FormatInt(int64(counters.get(counterIncrements))-hits, 10),
+			}
+		})
+	},
+}
+
+// mediumUpsertQuery returns driver specific query inserting the row into the 'medium upsert' table or updating the
+// one with the same uuid
+func mediumUpsertQuery(driver string, tableName string, columns []string) string {
+	names := strings.Join(columns, ", ")
+
+	sets := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column == "uuid" {
+			continue
+		}
+		switch driver {
+		case benchmark.MYSQL:
+			sets = append(sets, fmt.Sprintf("%[1]s = VALUES(%[1]s)", column))
+		case benchmark.MSSQL:
+			sets = append(sets, fmt.Sprintf("%[1]s = src.%[1]s", column))
+		default:
+			sets = append(sets, fmt.Sprintf("%[1]s = excluded.%[1]s", column))
+		}
+	}
+
+	switch driver {
+	case benchmark.MYSQL:
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s", tableName, names,
+			strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "), strings.Join(sets, ", "))
+	case benchmark.MSSQL:
+		aliases := make([]string, len(columns))
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			aliases[i] = "? AS " + column
+			values[i] = "src." + column
+		}
+
+		return fmt.Sprintf("MERGE %s WITH (HOLDLOCK) AS dst USING (SELECT %s) AS src ON dst.uuid = src.uuid "+
+			"WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);", tableName,
+			strings.Join(aliases, ", "), strings.Join(sets, ", "), names, strings.Join(values, ", "))
+	default:
+		return formatSQL(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (uuid) DO UPDATE SET %s", tableName, names,
+			benchmark.GenDBParameterPlaceholders(0, len(columns)), strings.Join(sets, ", ")), driver)
+	}
+}
+
+// mediumUpsertUUID returns the uuid of the n-th row upserted by given worker of the 'upsert-medium' test, so a worker can
+// hit its existing rows w/o keeping their uuids
+func mediumUpsertUUID(workerID int, n int64) string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", workerID, n)
+}
+
+// TestUpsertMedium upserts a row into the 'medium upsert' table hitting an existing uuid at the --upsert-hit-ratio rate
+var TestUpsertMedium = TestDesc{
+	name:        "upsert-medium",
+	metric:      "upserts/sec",
+	description: "upsert a row into the 'medium upsert' table by uuid (INSERT ... ON CONFLICT DO UPDATE, ON DUPLICATE KEY UPDATE or MERGE), --upsert-hit-ratio of the upserts hit an existing uuid",
+	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   ALL,
-	table:       TestTableLight,
+	databases:   RELATIONAL,
+	table:       TestTableMediumUpsert,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		hitRatio := b.TestOpts.(*TestOpts).TestcaseOpts.UpsertHitRatio
+		if hitRatio < 0 || hitRatio > 100 {
+			b.Exit("--upsert-hit-ratio must be within 0...100")
+		}
+
+		tableName := testDesc.table.TableName
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "euc_id", "progress"}, false)
+
+		columns := []string{"uuid"}
+		for _, conf := range *colConfs {
+			columns = append(columns, conf.ColumnName)
+		}
+		upsertSQL := mediumUpsertQuery(getDBDriver(b), tableName, columns)
+
+		// the uuids are reproducible, so the rows of the previous runs would turn the inserts into the updates
+		c := dbConnector(b)
+		t := TestTables[tableName]
+		t.Create(c, b)
+		c.ExecOrExit("DELETE FROM " + tableName)
+		c.Release()
+
+		// amount of rows inserted by every worker, a worker updates its own counter only
+		inserted := make([]int64, b.CommonOpts.Workers)
+		var hits uint64
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			rw := b.Randomizer.GetWorker(c.WorkerID)
+
+			var uuid string
+			if n := inserted[c.WorkerID]; n > 0 && rw.Intn(100) < hitRatio {
+				uuid = mediumUpsertUUID(c.WorkerID, int64(rw.Uintn64(uint64(n))))
+				atomic.AddUint64(&hits, 1)
+			} else {
+				uuid = mediumUpsertUUID(c.WorkerID, n)
+				inserted[c.WorkerID]++
+			}
+
+			_, values := b.GenFakeData(c.WorkerID, colConfs, false)
+			c.ExecOrExit(upsertSQL, append([]interface{}{uuid}, values...)...)
+
+			return 1
+		}, 0)
+
+		var inserts uint64
+		for _, n := range inserted {
+			inserts += uint64(n)
+		}
+
+		// every insert must have added a row, otherwise the upsert has matched a wrong one
+		c = dbConnector(b)
+		rows := c.GetRowsCount(tableName, "")
+		c.Release()
+
+		var hitRate float64
+		if inserts+hits > 0 {
+			hitRate = float64(hits) * 100 / float64(inserts+hits)
+		}
+
+		fmt.Fprintf(out(b), "inserts: %d; updates: %d; hit rate: %.2f%%; rows in the table: %d\n", inserts, hits, hitRate, rows)
+	},
+}
+
+// TestInsertCheckThenInsert inserts a row into the 'unique keys' table if it is absent using non-atomic SELECT and then INSERT
+var TestInsertCheckThenInsert = TestDesc{
+	name:        "insert-check-then-insert",
+	metric:      "rows/sec",
+	description: "insert a row into the 'unique keys' table if absent by SELECT and then INSERT in a transaction, compare with atomic upsert and report the unique violations rate",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	table:       TestTableUniqueKeys,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		driver := getDBDriver(b)
+		tableName := testDesc.table.TableName
+		colConfs := testDesc.table.GetColumnsConf([]string{"key_id", "value"}, false)
+		insertSQL := formatSQL(fmt.Sprintf("INSERT INTO %s (key_id, value) VALUES ($1, $2)", tableName), driver)
+		upsertSQL := upsertQuery(driver, tableName)
+
+		extra := []string{"APPLIED", "EXISTS", "UNIQUE VIOL.", "FAILURES", "CONFLICT %"}
+
+		testModes(b, "INSERT", []string{"check-then-insert", "atomic upsert"}, extra, func(i int) []string {
+			atomicMode := i == 1
+			counters := newTestCounters(b, ctiCounters)
+
+			// every mode starts from the empty table, so the keys left by the previous run or mode don't hit the check
+			c := dbConnector(b)
+			t := TestTables[tableName]
+			t.Create(c, b)
+			c.ExecOrExit(fmt.Sprintf("DELETE FROM %s", tableName))
+			c.Release()
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+				key, value := (*w)["key_id"], (*w)["value"]
+
+				if atomicMode {
+					if _, err := c.Exec(upsertSQL, key, value); err != nil {
+						counters.add(ctiFailures, 1)
+					} else {
+						counters.add(ctiApplied, 1)
+					}
+
+					return 1
+				}
+
+				c.Begin()
+
+				var count int
+				c.QueryRowAndScan(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE key_id = '%s'", tableName, key), &count)
+				if count > 0 {
+					c.Commit()
+					counters.add(ctiAlreadyExists, 1)
+
+					return 1
+				}
+
+				// another worker may insert the same key between the SELECT and the INSERT
+				if _, err := c.Exec(insertSQL, key, value); err != nil {
+					c.Rollback()
+					if benchmark.IsUniqueViolation(err) {
+						counters.add(ctiUniqueViolations, 1)
+					} else {
+						counters.add(ctiFailures, 1)
+					}
+
+					return 1
+				}
+
+				c.Commit()
+				counters.add(ctiApplied, 1)
+
+				return 1
+			}, 0)
+
+			conflictRate := counters.percent(ctiUniqueViolations, ctiApplied, ctiAlreadyExists, ctiUniqueViolations, ctiFailures) +
+				counters.percent(ctiFailures, ctiApplied, ctiAlreadyExists, ctiUniqueViolations, ctiFailures)
+
+			return []string{
+				strconv.FormatUint(counters.get(ctiApplied), 10),
+				strconv.FormatUint(counters.get(ctiAlreadyExists), 10),
+				strconv.FormatUint(counters.get(ctiUniqueViolations), 10),
+				strconv.FormatUint(counters.get(ctiFailures), 10),
+				fmt.Sprintf("%.2f%%", conflictRate),
+			}
+		})
+	},
+}
+
+// TestInsertHeavy inserts a row into the 'heavy' table
+var TestInsertHeavy = TestDesc{
+	name:        "insert-heavy",
+	metric:      "rows/sec",
+	description: "insert a row into the 'heavy' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   withCockroach(RELATIONAL),
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		testInsertGeneric(b, testDesc)
 	},
 }
 
-// insertByPreparedDataWorker inserts a row into the 'light' table using prepared statement for the batch
-func insertByPreparedDataWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
-	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
-	workerID := c.WorkerID
+// TestInsertHeavyPrepared inserts a row into the 'heavy' table using prepared statement for the batch
+var TestInsertHeavyPrepared = TestDesc{
+	name:        "insert-heavy-prepared",
+	metric:      "rows/sec",
+	description: "insert a row into the 'heavy' table using prepared statement for the batch",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+	},
+}
 
-	tx := c.Begin()
+// TestInsertHeavyMultivalue inserts a row into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) "
+var TestInsertHeavyMultivalue = TestDesc{
+	name:        "insert-heavy-multivalue",
+	metric:      "rows/sec",
+	description: "insert a row into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) ",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testGeneric(b, testDesc, insertMultiValueDataWorker, 0)
+	},
+}
 
-	columns, _ := b.GenFakeData(workerID, colConfs, false)
+// the counters of a single mode of the 'insert-heavy-check-constraint' test
+const (
+	checkInserted = iota // inserted rows
+	checkRejected        // rows rejected by the CHECK constraint
+	checkCounters
+)
 
-	parametersPlaceholder := benchmark.GenDBParameterPlaceholders(0, len(*colConfs))
-	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s)", testDesc.table.TableName, strings.Join(columns, ","), parametersPlaceholder)
-	sql = formatSQL(sql, c.DbOpts.Driver)
+// TestInsertHeavyCheckConstraint inserts rows into copies of the 'heavy' table w/o and with the CHECK constraints,
+// a share of the rows violates the constraint, so the validation overhead and the rejection rate are reported
+var TestInsertHeavyCheckConstraint = TestDesc{
+	name:        "insert-heavy-check-constraint",
+	metric:      "rows/sec",
+	description: "insert a row into a copy of the 'heavy' table w/o and with CHECK (progress BETWEEN 0 AND 100), --check-violations percent of rows are out of range, report the constraint overhead and the rejection rate",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testcaseOpts := &b.TestOpts.(*TestOpts).TestcaseOpts
+		violations := testcaseOpts.CheckViolations
+		if violations < 0 || violations > 100 {
+			b.Exit("--check-violations must be within 0..100")
+		}
 
-	t := c.StatementEnter(sql, nil)
-	stmt, err := tx.Prepare(sql)
-	c.StatementExit("Prepare()", t, err, false, nil, sql, nil, nil, nil)
+		modes := []string{"no constraint", "CHECK"}
 
-	if err != nil {
-		c.Exit(err.Error())
+		tables := make([]TestTable, len(modes))
+		for i := range modes {
+			tables[i] = testDesc.table
+			tables[i].TableName = fmt.Sprintf("%s_chk_%d", testDesc.table.TableName, i)
+			tables[i].ColumnsConf = nil
+			tables[i].RowsCount = 0
+			if i == 0 {
+				tables[i].Checks = nil
+			}
+		}
+
+		dropTables := func() {
+			c := scratchConnector(b)
+			defer c.Close()
+			for i := range tables {
+				c.DropTable(tables[i].TableName)
+			}
+		}
+
+		defer cleanupOnExit(b, dropTables)()
+
+		// the copies are created with --with-check-constraints forced on, the first copy just has no constraints
+		origChecks := testcaseOpts.CheckConstraints
+		testcaseOpts.CheckConstraints = true
+		c := dbConnector(b)
+		for i := range tables {
+			c.DropTable(tables[i].TableName)
+			tables[i].Create(c, b)
+		}
+		c.Release()
+		testcaseOpts.CheckConstraints = origChecks
+
+		rates := testModes(b, "MODE", modes, []string{"REJECTED"}, func(i int) []string {
+			counters := newTestCounters(b, checkCounters)
+
+			modeDesc := *testDesc
+			modeDesc.table = tables[i]
+
+			testGeneric(b, &modeDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+				columns, values := b.GenFakeData(c.WorkerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+
+				// both modes get the same share of out-of-range values, the constraint is the only difference
+				r := b.Randomizer.GetWorker(c.WorkerID)
+				if r.Intn(100) < violations {
+					for n, column := range columns {
+						if column == "progress" {
+							values[n] = 101 + r.Intn(100)
+						}
+					}
+				}
+
+				_, err := c.Exec(formatSQL(multiValueInsertQuery(testDesc.table.TableName, columns, 1), c.DbOpts.Driver), values...)
+				switch {
+				case err == nil:
+					counters.add(checkInserted, 1)
+				case benchmark.IsCheckViolation(err):
+					counters.add(checkRejected, 1)
+				default:
+					c.Exit("can't insert a row: %v", err)
+				}
+
+				return 1
+			}, 0)
+
+			return []string{fmt.Sprintf("%.1f%%", counters.percent(checkRejected, checkInserted, checkRejected))}
+		})
+
+		if len(rates) == 2 && rates[0] > 0 {
+			fmt.Fprintf(out(b), "CHECK constraint overhead: %.1f%%\n", (rates[0]-rates[1])*100/rates[0])
+		}
+	},
+}
+
+// parseTenantSpreads parses the --tenant-spreads option and returns the spreads in ascending order
+func parseTenantSpreads(b *benchmark.Benchmark, tenantSpreads string) []int {
+	var spreads []int
+
+	for _, s := range strings.Split(tenantSpreads, ",") {
+		spread, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || spread <= 0 {
+			b.Exit("invalid tenant spread '%s' in --tenant-spreads=%s, positive integers are expected", s, tenantSpreads)
+		}
+		spreads = append(spreads, spread)
 	}
-	for i := 0; i < batch; i++ {
-		_, values := b.GenFakeData(workerID, colConfs, false)
+	sort.Ints(spreads)
 
-		t := c.StatementEnter("", nil)
-		_, err = stmt.Exec(values...)
-		c.StatementExit("Exec()", t, err, false, nil, "<< stdin ", values, nil, nil)
+	return spreads
+}
+
+// pickDistinctTenants returns up to n distinct random tenants of the tenants working set
+func pickDistinctTenants(b *benchmark.Benchmark, workerID int, n int) []benchmark.TenantUUID {
+	rw := b.Randomizer.GetWorker(workerID)
+	seen := make(map[benchmark.TenantUUID]bool, n)
+	tenants := make([]benchmark.TenantUUID, 0, n)
 
+	// the working set may be smaller than n or skewed to the hot tenants, so don't try forever
+	for attempt := 0; len(tenants) < n && attempt < 10*n; attempt++ {
+		tenant, err := b.TenantsCache.GetRandomTenantUUID(rw, 0)
 		if err != nil {
-			stmt.Close() //nolint:sqlclosecheck
-			c.Exit(err.Error())
+			b.Exit(err.Error())
+		}
+		if !seen[tenant] {
+			seen[tenant] = true
+			tenants = append(tenants, tenant)
 		}
 	}
-	c.Commit()
 
-	return batch
+	return tenants
 }
 
-// TestInsertLightPrepared inserts a row into the 'light' table using prepared statement for the batch
-var TestInsertLightPrepared = TestDesc{
-	name:        "insert-light-prepared",
+// the counters of a single spread of the 'insert-heavy-multivalue-many-tenants' test
+const (
+	spreadBatches = iota // batches inserted
+	spreadTenants        // distinct tenants of the batches
+	spreadCounters
+)
+
+// TestInsertHeavyManyTenants inserts multi-value batches into the 'heavy' table spreading the rows of every batch across many tenants
+var TestInsertHeavyManyTenants = TestDesc{
+	name:        "insert-heavy-multivalue-many-tenants",
 	metric:      "rows/sec",
-	description: "insert a row into the 'light' table using prepared statement for the batch",
+	description: "insert a batch of rows into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) with the rows spread across N distinct tenants, see --tenant-spreads",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableLight,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+		spreads := parseTenantSpreads(b, b.TestOpts.(*TestOpts).TestcaseOpts.TenantSpreads)
+
+		batch := spreads[len(spreads)-1]
+		if b.TestOpts.(*TestOpts).DBOpts.Driver == benchmark.MSSQL {
+			// MSSQL accepts up to 2100 parameters per statement
+			batch = benchmark.Min(batch, 2000/len(testDesc.table.columns))
+		}
+		defer setDefaultBatch(b, batch)()
+
+		names := make([]string, len(spreads))
+		for i, spread := range spreads {
+			names[i] = strconv.Itoa(spread)
+		}
+
+		testModes(b, "SPREAD", names, []string{"TENANTS/BATCH"}, func(i int) []string {
+			spread := spreads[i]
+			counters := newTestCounters(b, spreadCounters)
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+				columns, _ := b.GenFakeData(c.WorkerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+
+				tenantColumn := -1
+				for i, column := range columns {
+					if column == "tenant_id" {
+						tenantColumn = i
+					}
+				}
+
+				batchTenants := pickDistinctTenants(b, c.WorkerID, benchmark.Min(spread, batch))
+				counters.add(spreadBatches, 1)
+				counters.add(spreadTenants, uint64(len(batchTenants)))
+
+				values := make([]interface{}, 0, batch*len(columns))
+				for i := 0; i < batch; i++ {
+					_, vals := b.GenFakeData(c.WorkerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+					// interleave the tenants, so the neighbour rows of the batch never hit the same index page
+					vals[tenantColumn] = batchTenants[i%len(batchTenants)]
+					values = append(values, vals...)
+				}
+
+				c.Begin()
+				c.ExecOrExit(formatSQL(multiValueInsertQuery(testDesc.table.TableName, columns, batch), c.DbOpts.Driver), values...)
+				c.Commit()
+
+				return batch
+			}, 0)
+
+			avgTenants := 0.0
+			if n := counters.get(spreadBatches); n > 0 {
+				avgTenants = float64(counters.get(spreadTenants)) / float64(n)
+			}
+
+			return []string{fmt.Sprintf("%.1f", avgTenants)}
+		})
 	},
 }
 
-// insertMultiValueDataWorker inserts a row into the 'light' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...)
-func insertMultiValueDataWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
-	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
-	workerID := c.WorkerID
-
-	columns, _ := b.GenFakeData(workerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
-	if c.DbOpts.Driver == benchmark.CASSANDRA {
-		var values []interface{}
-		var sql string
+// TestInsertHeavyDecoupledGen compares inserting into the 'heavy' table with the rows generated by the INSERT workers
+// and by the --decouple-gen producers
+var TestInsertHeavyDecoupledGen = TestDesc{
+	name:        "insert-heavy-decoupled-gen",
+	metric:      "rows/sec",
+	description: "insert a row into the 'heavy' table generating the rows in the INSERT workers and then in producer goroutines feeding the workers via a buffered channel, see --decouple-gen",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testcaseOpts := &b.TestOpts.(*TestOpts).TestcaseOpts
+		origDecoupleGen := testcaseOpts.DecoupleGen
 
-		sqlInsertTpl := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", testDesc.table.TableName, strings.Join(columns, ","))
+		rates := testModes(b, "GENERATION", []string{"coupled", "decoupled"}, nil, func(i int) []string {
+			testcaseOpts.DecoupleGen = i == 1
+			testInsertGeneric(b, testDesc)
 
-		for i := 0; i < batch; i++ {
-			sql = fmt.Sprintf("%s\n%s (%s);", sql, sqlInsertTpl, benchmark.GenDBParameterPlaceholdersCassandra(i*len(*colConfs), len(*colConfs)))
-		}
+			return nil
+		})
 
-		sql = formatSQL(sql, c.DbOpts.Driver)
+		testcaseOpts.DecoupleGen = origDecoupleGen
 
-		for i := 0; i < batch; i++ {
-			_, vals := b.GenFakeData(workerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
-			values = append(values, vals...)
+		if len(rates) == 2 && rates[0] > 0 {
+			fmt.Fprintf(out(b), "decoupled/coupled rate: %.2f\n", rates[1]/rates[0])
 		}
+	},
+}
 
-		sql = fmt.Sprintf("BEGIN BATCH%s\nAPPLY BATCH;", sql)
-		c.ExecOrExit(sql, values...)
+// TestCopyHeavy copies a row into the 'heavy' table
+var TestCopyHeavy = TestDesc{
+	name:        "copy-heavy",
+	metric:      "rows/sec",
+	description: "copy a row into the 'heavy' table",
+	category:    TestInsert,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testCopy(b, testDesc)
+	},
+}
 
-		return batch
+// partitionRouting returns the amount of rows per partition of the partitioned 'heavy' table and the amount of rows
+// which don't belong to the partition expected for their 'progress' value
+func partitionRouting(c *benchmark.DBConnector, table string) (perPartition map[string]int64, misrouted int64, err error) {
+	rows, err := c.Query(fmt.Sprintf("SELECT tableoid::regclass::text, progress / %d, COUNT(*) FROM %s GROUP BY 1, 2", heavyPartitionStep, table))
+	if err != nil {
+		return nil, 0, err
 	}
+	defer rows.Close()
 
-	var values []interface{}
-
-	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", testDesc.table.TableName, strings.Join(columns, ","))
+	perPartition = make(map[string]int64)
+	for rows.Next() {
+		var partition string
+		var bucket int
+		var n int64
 
-	for i := 0; i < batch; i++ {
-		if i == 0 {
-			sql = fmt.Sprintf("%s (%s)", sql, benchmark.GenDBParameterPlaceholders(i*len(*colConfs), len(*colConfs)))
-		} else {
-			sql = fmt.Sprintf("%s, (%s)", sql, benchmark.GenDBParameterPlaceholders(i*len(*colConfs), len(*colConfs)))
+		if err = rows.Scan(&partition, &bucket, &n); err != nil {
+			return nil, 0, err
 		}
-	}
-
-	sql = formatSQL(sql, c.DbOpts.Driver)
 
-	for i := 0; i < batch; i++ {
-		_, vals := b.GenFakeData(workerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
-		values = append(values, vals...)
+		expected := table + "_default"
+		if bucket < heavyPartitions {
+			expected = heavyPartitionName(table, bucket)
+		}
+		if partition != expected {
+			misrouted += n
+		}
+		perPartition[partition] += n
 	}
 
-	c.Begin()
-	c.ExecOrExit(sql, values...)
-	c.Commit()
-
-	return batch
+	return perPartition, misrouted, rows.Err()
 }
 
-// TestInsertLightMultiValue inserts a row into the 'light' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...)
-var TestInsertLightMultiValue = TestDesc{
-	name:        "insert-light-multivalue",
+// TestCopyHeavyPartitioned copies rows into the range-partitioned 'heavy' table and compares with the unpartitioned one
+var TestCopyHeavyPartitioned = TestDesc{
+	name:        "copy-heavy-partitioned",
 	metric:      "rows/sec",
-	description: "insert a row into the 'light' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) ",
+	description: "copy a row into the 'heavy' table range-partitioned by 'progress' and compare with the unpartitioned 'heavy' table to show the partition routing cost",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   PMWSA,
-	table:       TestTableLight,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableHeavyPartitioned,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertMultiValueDataWorker, 0)
-	},
-}
+		plain := *testDesc
+		plain.table = TestTables[TestTableHeavy.TableName]
 
-// copyDataWorker copies a row into the 'light' table
-func copyDataWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
-	var sql string
-	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
-	workerID := c.WorkerID
+		descs := []*TestDesc{&plain, testDesc}
 
-	tx := c.Begin()
+		rates := testModes(b, "TABLE", []string{"unpartitioned", "partitioned"}, nil, func(i int) []string {
+			testCopy(b, descs[i])
 
-	columns, _ := b.GenFakeData(workerID, colConfs, false)
+			return nil
+		})
 
-	switch c.DbOpts.Driver {
-	case benchmark.POSTGRES:
-		sql = pq.CopyIn(testDesc.table.TableName, columns...)
-	case benchmark.MSSQL:
-		sql = mssql.CopyIn(testDesc.table.TableName, mssql.BulkOptions{KeepNulls: true, RowsPerBatch: batch}, columns...)
-	default:
-		b.Exit("unsupported driver: '%v', supported drivers are: %s|%s", b.TestOpts.(*TestOpts).DBOpts.Driver, benchmark.POSTGRES, benchmark.MSSQL)
-	}
+		if len(rates) == 2 && rates[0] > 0 {
+			fmt.Fprintf(out(b), "partition routing cost: %.1f%%\n", 100*(1-rates[1]/rates[0]))
+		}
 
-	t := c.StatementEnter(sql, nil)
-	stmt, err := tx.Prepare(sql)
-	c.StatementExit("Prepare()", t, err, false, nil, sql, nil, nil, nil)
+		c := dbConnector(b)
+		defer c.Release()
 
-	if err != nil {
-		c.Exit(err.Error())
-	}
-	for i := 0; i < batch; i++ {
-		_, values := b.GenFakeData(workerID, colConfs, false)
+		perPartition, misrouted, err := partitionRouting(c, testDesc.table.TableName)
+		if err != nil {
+			b.Exit("can't check partition routing: %v", err)
+		}
 
-		t := c.StatementEnter("", nil)
-		_, err = stmt.Exec(values...)
-		c.StatementExit("Exec()", t, err, false, nil, "<< stdin ", values, nil, nil)
+		partitions := make([]string, 0, len(perPartition))
+		for p := range perPartition {
+			partitions = append(partitions, p)
+		}
+		sort.Strings(partitions)
 
-		if err != nil {
-			stmt.Close() //nolint:sqlclosecheck
-			c.Exit(err.Error())
+		for _, p := range partitions {
+			fmt.Fprintf(out(b), "%40s %10d rows\n", p, perPartition[p])
+		}
+		fmt.Fprintf(out(b), "rows in unexpected partitions: %d\n", misrouted)
+	},
+}
+
+// bulkLoadRows copies given amount of rows into the table in chunks of the effective batch and returns the elapsed time
+func bulkLoadRows(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, rows int) (time.Duration, error) {
+	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
+	columns, _ := b.GenFakeData(c.WorkerID, colConfs, false)
+
+	chunk := b.Vault.(*DBTestData).EffectiveBatch
+	start := time.Now()
+
+	for loaded := 0; loaded < rows; loaded += chunk {
+		if rows-loaded < chunk {
+			chunk = rows - loaded
+		}
+		if err := copyChunk(b, c, testDesc, colConfs, columns, chunk); err != nil {
+			return time.Since(start), err
 		}
 	}
-	_, err = stmt.Exec()
-	if err != nil {
-		stmt.Close()
-		c.Exit(err.Error())
-	}
-	c.Commit()
 
-	return batch
+	return time.Since(start), nil
 }
 
-// TestCopyLight copies a row into the 'light' table
-var TestCopyLight = TestDesc{
-	name:        "copy-light",
+// TestBulkLoadDropRebuildIndex copies --limit rows into the 'heavy' table with indexes present and with indexes dropped and rebuilt afterwards
+var TestBulkLoadDropRebuildIndex = TestDesc{
+	name:        "bulk-load-heavy-drop-rebuild-index",
 	metric:      "rows/sec",
-	description: "copy a row into the 'light' table",
+	description: "copy --limit rows into the 'heavy' table with secondary indexes present vs dropping them before the load and rebuilding after",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
 	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
-	table:       TestTableLight,
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, copyDataWorker, 0)
+		t := TestTables[testDesc.table.TableName]
+		rows := b.TestOpts.(*TestOpts).BenchOpts.Limit
+
+		defer setDefaultBatch(b, 10000)()
+
+		c := dbConnector(b)
+		defer c.Release()
+
+		// also brings back the indexes possibly left dropped by an interrupted run
+		t.Create(c, b)
+
+		// the indexes must be rebuilt whatever way the test exits
+		var rebuild time.Duration
+		rebuildIndexes := cleanupOnExit(b, func() {
+			start := time.Now()
+			for n, columns := range t.Indexes {
+				c.CreateIndex(t.TableName, columns, n)
+			}
+			rebuild = time.Since(start)
+		})
+
+		results := make([]string, 0, 2)
+		report := func(mode string, load time.Duration, rebuild time.Duration) {
+			total := load + rebuild
+			results = append(results, fmt.Sprintf("%20s %10.1f %10.1f %10.1f %15.0f %s", mode,
+				load.Seconds(), rebuild.Seconds(), total.Seconds(), float64(rows)/total.Seconds(), testDesc.metric))
+		}
+
+		load, err := bulkLoadRows(b, c, testDesc, rows)
+		if err != nil {
+			b.Exit("bulk load with indexes present failed: %v", err)
+		}
+		report("indexes present", load, 0)
+
+		for n, columns := range t.Indexes {
+			c.DropTableIndex(t.TableName, columns, n)
+		}
+
+		load, err = bulkLoadRows(b, c, testDesc, rows)
+		if err != nil {
+			b.Exit("bulk load with indexes dropped failed: %v", err)
+		}
+		rebuildIndexes()
+		report("drop + rebuild", load, rebuild)
+
+		fmt.Fprintf(out(b), "%20s %10s %10s %10s %15s\n", "MODE", "LOAD, s", "REBUILD, s", "TOTAL, s", "RATE")
+		fmt.Fprintf(out(b), "%s\n", strings.Join(results, "\n"))
 	},
 }
 
-// TestInsertLightDBR inserts a row into the 'light' table using goland DBR query builder
-var TestInsertLightDBR = TestDesc{
-	name:        "dbr-insert-light",
+// TestInsertHeavyDBR inserts a row into the 'heavy' table using golang DB query builder
+var TestInsertHeavyDBR = TestDesc{
+	name:        "dbr-insert-heavy",
 	metric:      "rows/sec",
-	description: "insert a row into the 'light' table using goland DBR query builder",
+	description: "insert a row into the 'heavy' table using golang DB query builder",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   true,
 	databases:   RELATIONAL,
-	table:       TestTableLight,
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		testInsertGeneric(b, testDesc)
 	},
 }
 
-// TestInsertMedium inserts a row into the 'medium' table
-var TestInsertMedium = TestDesc{
-	name:        "insert-medium",
+// TestInsertJSON inserts a row into a table with JSON(b) column
+var TestInsertJSON = TestDesc{
+	name:        "insert-json",
 	metric:      "rows/sec",
-	description: "insert a row into the 'medium' table",
+	description: "insert a row into a table with JSON(b) column",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   ALL,
-	table:       TestTableMedium,
+	databases:   withCockroach(RELATIONAL),
+	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		testInsertGeneric(b, testDesc)
 	},
 }
 
-// TestInsertMediumPrepared inserts a row into the 'medium' table using prepared statement for the batch
-var TestInsertMediumPrepared = TestDesc{
-	name:        "insert-medium-prepared",
+// TestInsertJSONDBR inserts a row into a table with JSON(b) column using golang DBR driver
+var TestInsertJSONDBR = TestDesc{
+	name:        "dbr-insert-json",
 	metric:      "rows/sec",
-	description: "insert a row into the 'medium' table using prepared statement for the batch",
+	description: "insert a row into a table with JSON(b) column using golang DBR driver",
 	category:    TestInsert,
 	isReadonly:  false,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableJSON,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// TestSelectJSONByIndexedValue selects a row from the 'json' table by some json condition
+var TestSelectJSONByIndexedValue = TestDesc{
+	name:        "select-json-by-indexed-value",
+	metric:      "rows/sec",
+	description: "select a row from the 'json' table by some json condition",
+	category:    TestSelect,
+	isReadonly:  true,
 	isDBRTest:   false,
 	databases:   RELATIONAL,
-	table:       TestTableMedium,
+	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+
+			switch driver {
+			case benchmark.MYSQL:
+				return "_data_f0f0 = '10' AND id > " + strconv.FormatUint(id, 10)
+			case benchmark.POSTGRES:
+				return "json_data @> '{\"field0\": {\"field0\": 10}}' AND id > " + strconv.FormatUint(id, 10)
+			default:
+				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
+			}
+
+			return ""
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id ASC"
+		}
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
 	},
 }
 
-// TestInsertMediumMultiValue inserts a row into the 'medium' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...)
-var TestInsertMediumMultiValue = TestDesc{
-	name:        "insert-medium-multivalue",
+// TestInsertJSONTags inserts a JSON document with the tags array into the 'json_tags' table
+var TestInsertJSONTags = TestDesc{
+	name:        "insert-json-tags",
 	metric:      "rows/sec",
-	description: "insert a row into the 'medium' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) ",
+	description: "insert a JSON document with the tags array into the 'json_tags' table",
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   PMWSA,
-	table:       TestTableMedium,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableJSONTags,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertMultiValueDataWorker, 0)
+		testInsertGeneric(b, testDesc)
+	},
+}
+
+// jsonTagsIndexExists returns true if the tags array index of the 'json_tags' table exists
+func jsonTagsIndexExists(c *benchmark.DBConnector) bool {
+	exists := false
+
+	switch c.DbOpts.Driver {
+	case benchmark.MYSQL:
+		c.QueryRowAndScan("SELECT EXISTS (SELECT 1 FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_NAME = 'acronis_db_bench_json_tags' AND INDEX_NAME = '"+jsonTagsIndexName+"')", &exists)
+	default:
+		c.QueryRowAndScan("SELECT EXISTS (SELECT * FROM pg_indexes WHERE indexname = '"+jsonTagsIndexName+"')", &exists)
+	}
+
+	return exists
+}
+
+// TestSelectJSONArrayContains selects documents from the 'json_tags' table having given tag in the tags array with and w/o the index
+var TestSelectJSONArrayContains = TestDesc{
+	name:        "select-json-array-contains",
+	metric:      "rows/sec",
+	description: "select up to 100 documents from the 'json_tags' table which tags array contains random tag (@> / JSON_CONTAINS) with and w/o GIN (multi-valued) index",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableJSONTags,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		var where string
+
+		switch getDBDriver(b) {
+		case benchmark.MYSQL:
+			where = "JSON_CONTAINS(doc->'$.tags', CAST('[\"tag_%d\"]' AS JSON))"
+		default:
+			where = "doc -> 'tags' @> '[\"tag_%d\"]'"
+		}
+
+		c := scratchConnector(b)
+		defer c.Close()
+
+		testModes(b, "MODE", []string{"GIN index", "no index"}, nil, func(i int) []string {
+			index := i == 0
+
+			switch exists := jsonTagsIndexExists(c); {
+			case index && !exists:
+				c.ExecOrExit(jsonTagsIndexQuery(c.DbOpts.Driver))
+			case !index && exists && c.DbOpts.Driver == benchmark.MYSQL:
+				c.ExecOrExit("DROP INDEX " + jsonTagsIndexName + " ON acronis_db_bench_json_tags")
+			case !index && exists:
+				c.ExecOrExit("DROP INDEX " + jsonTagsIndexName)
+			}
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				tag := b.Randomizer.GetWorker(c.WorkerID).Intn(1000)
+				c.Select(testDesc.table.TableName, "id", fmt.Sprintf(where, tag), "", 100, b.TestOpts.(*TestOpts).BenchOpts.Explain)
+
+				return 1
+			}, 1)
+
+			return nil
+		})
+
+		// the index is a part of the table schema, so bring it back
+		if !jsonTagsIndexExists(c) {
+			c.ExecOrExit(jsonTagsIndexQuery(c.DbOpts.Driver))
+		}
+	},
+}
+
+// TestSearchJSONByIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
+var TestSearchJSONByIndexedValue = TestDesc{
+	name:        "search-json-by-indexed-value",
+	metric:      "rows/sec",
+	description: "search a row from the 'json' table using some json condition using LIKE {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   RELATIONAL,
+	table:       TestTableJSON,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+
+			switch driver {
+			case benchmark.MYSQL:
+				return "_data_f0f0f0 LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10)
+			case benchmark.POSTGRES:
+				return "json_data->'field0'->'field0'->>'field0' LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10) // searching for the 'needle' word
+			default:
+				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
+			}
+
+			return ""
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id ASC"
+		}
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+	},
+}
+
+// jsonDoc is a Go-side model of the 'json' table documents, every field is either a nested document or a scalar value
+type jsonDoc struct {
+	Field0 interface{} `json:"field0"`
+	Field1 interface{} `json:"field1"`
+	Field2 interface{} `json:"field2"`
+	Field3 interface{} `json:"field3"`
+	Field4 interface{} `json:"field4"`
+	Field5 interface{} `json:"field5"`
+}
+
+// TestSelectJSONDeserialize selects a page of documents from the 'json' table and decodes every document into a Go struct
+var TestSelectJSONDeserialize = TestDesc{
+	name:        "select-json-deserialize",
+	metric:      "docs/sec",
+	description: "select a page of documents from the 'json' table and unmarshal every document into a Go struct, compare with raw text fetch, page size is set by --batch (default 100)",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableJSON,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		defer setDefaultBatch(b, 100)()
+
+		testModes(b, "JSON", []string{"raw text", "decoded"}, nil, func(i int) []string {
+			decode := i == 1
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				id := b.Randomizer.GetWorker(c.WorkerID).Uintn64(testDesc.table.RowsCount - 1)
+
+				rows := c.Select(testDesc.table.TableName, "json_data", fmt.Sprintf("id > %d", id), "id ASC", batch, b.TestOpts.(*TestOpts).BenchOpts.Explain)
+				if rows == nil {
+					return 1
+				}
+
+				docs := 0
+				for rows.Next() {
+					var data string
+					if err := rows.Scan(&data); err != nil {
+						c.Exit("can't fetch json_data: %v", err)
+					}
+
+					if decode {
+						var doc jsonDoc
+						if err := json.Unmarshal([]byte(data), &doc); err != nil {
+							c.Exit("can't unmarshal json_data: %v", err)
+						}
+					}
+					docs++
+				}
+
+				if docs == 0 {
+					return 1
+				}
+
+				return docs
+			}, 1)
+
+			return nil
+		})
 	},
 }
 
-// TestCopyMedium copies a row into the 'medium' table
-var TestCopyMedium = TestDesc{
-	name:        "copy-medium",
+// TestSelectJSONByNonIndexedValue selects a row from the 'json' table by some json condition
+var TestSelectJSONByNonIndexedValue = TestDesc{
+	name:        "select-json-by-nonindexed-value",
 	metric:      "rows/sec",
-	description: "copy a row into the 'medium' table",
-	category:    TestInsert,
-	isReadonly:  false,
+	description: "select a row from the 'json' table by some json condition",
+	category:    TestSelect,
+	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
-	table:       TestTableMedium,
+	databases:   RELATIONAL,
+	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, copyDataWorker, 0)
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+
+			switch driver {
+			case benchmark.MYSQL:
+				return "JSON_EXTRACT(json_data, '$.field0.field1') = '10' AND id > " + strconv.FormatUint(id, 10)
+			case benchmark.POSTGRES:
+				return "json_data @> '{\"field0\": {\"field1\": 10}}' AND id > " + strconv.FormatUint(id, 10)
+			default:
+				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
+			}
+
+			return ""
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id ASC"
+		}
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
 	},
 }
 
-// TestInsertMediumDBR inserts a row into the 'medium' table using goland DBR query builder
-var TestInsertMediumDBR = TestDesc{
-	name:        "dbr-insert-medium",
+// TestSearchJSONByNonIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
+var TestSearchJSONByNonIndexedValue = TestDesc{
+	name:        "search-json-by-nonindexed-value",
 	metric:      "rows/sec",
-	description: "insert a row into the 'medium' table using goland DBR query builder",
-	category:    TestInsert,
-	isReadonly:  false,
-	isDBRTest:   true,
+	description: "search a row from the 'json' table using some json condition using LIKE {}",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
 	databases:   RELATIONAL,
-	table:       TestTableMedium,
+	table:       TestTableJSON,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testInsertGeneric(b, testDesc)
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+
+			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+
+			switch driver {
+			case benchmark.MYSQL:
+				return "JSON_EXTRACT(json_data, '$.field0.field1') LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10)
+			case benchmark.POSTGRES:
+				return "json_data->'field0'->'field0'->>'field0' LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10) // searching for the 'needle' word
+			default:
+				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
+			}
+
+			return ""
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id ASC"
+		}
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
 	},
 }
 
-// TestInsertBlob inserts a row with large random blob into the 'blob' table
-var TestInsertBlob = TestDesc{
-	name:        "insert-blob",
+// TestUpdateMedium updates random row in the 'medium' table
+var TestUpdateMedium = TestDesc{
+	name:        "update-medium",
 	metric:      "rows/sec",
-	description: "insert a row with large random blob into the 'blob' table",
-	category:    TestInsert,
+	description: "update random row in the 'medium' table",
+	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   ALL,
-	table:       TestTableBlob,
+	databases:   withCockroach(RELATIONAL),
+	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testDesc.table.InitColumnsConf()
-		for i := range testDesc.table.ColumnsConf {
-			if testDesc.table.ColumnsConf[i].ColumnType == "blob" {
-				testDesc.table.ColumnsConf[i].MaxSize = b.TestOpts.(*TestOpts).TestcaseOpts.MaxBlobSize
-				testDesc.table.ColumnsConf[i].MinSize = b.TestOpts.(*TestOpts).TestcaseOpts.MinBlobSize
-			}
-		}
-		testInsertGeneric(b, testDesc)
+		testUpdateGeneric(b, testDesc, 1, nil)
 	},
 }
 
-// TestCopyBlob copies a row with large random blob into the 'blob' table
-var TestCopyBlob = TestDesc{
-	name:        "copy-blob",
-	metric:      "rows/sec",
-	description: "copy a row with large random blob into the 'blob' table",
-	category:    TestInsert,
+// the counters of a single workers count of the 'update-single-hot-row' test
+const (
+	hotRowUpdates  = iota // committed increments
+	hotRowFailures        // increments failed on lock wait timeouts and deadlocks
+	hotRowCounters
+)
+
+// TestUpdateSingleHotRow updates the same row of the 'medium' table by all the workers, see --hot-row-workers
+var TestUpdateSingleHotRow = TestDesc{
+	name:        "update-single-hot-row",
+	metric:      "ops/sec",
+	description: "increment progress of the same row in the 'medium' table by every worker, report the throughput and lock wait timeouts/deadlocks vs workers count, see --hot-row-workers",
+	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
-	table:       TestTableBlob,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testDesc.table.InitColumnsConf()
-		for i := range testDesc.table.ColumnsConf {
-			if testDesc.table.ColumnsConf[i].ColumnType == "blob" {
-				testDesc.table.ColumnsConf[i].MaxSize = b.TestOpts.(*TestOpts).TestcaseOpts.MaxBlobSize
-				testDesc.table.ColumnsConf[i].MinSize = b.TestOpts.(*TestOpts).TestcaseOpts.MinBlobSize
+		var workerCounts []int
+		for _, s := range strings.Split(b.TestOpts.(*TestOpts).TestcaseOpts.HotRowWorkers, ",") {
+			workers, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || workers <= 0 {
+				b.Exit("invalid workers count '%s' in --hot-row-workers, positive integers are expected", s)
 			}
+			workerCounts = append(workerCounts, workers)
 		}
-		testGeneric(b, testDesc, copyDataWorker, 0)
-	},
-}
 
-// createLargeObjectWorker inserts a row with large random object into the 'largeobject' table
-func createLargeObjectWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
-	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
-	parametersPlaceholder := benchmark.GenDBParameterPlaceholders(0, len(*colConfs))
-	testOpts := b.TestOpts.(*TestOpts)
-	workerID := c.WorkerID
+		tableName := testDesc.table.TableName
 
-	c.Begin()
+		// the first row is the hot one
+		var id, progress int64
+		c := dbConnector(b)
+		if !c.TableExists(tableName) {
+			c.Release()
+			b.Exit("The '%s' table doesn't exist, please create tables using -I option, or use individual insert test using the -t `insert-***`", tableName)
+		}
+		c.QueryRowAndScan(fmt.Sprintf("SELECT COALESCE(MIN(id), 0) FROM %s", tableName), &id)
+		if id == 0 {
+			c.Release()
+			b.Exit("The '%s' table is empty, please fill it using the -t 'insert-medium' test", tableName)
+		}
+		c.ExecOrExit(fmt.Sprintf("UPDATE %s SET progress = 0 WHERE id = %d AND progress IS NULL", tableName, id))
+		c.Release()
 
-	var sql string
+		query := fmt.Sprintf("UPDATE %s SET progress = progress + 1 WHERE id = $1", tableName)
 
-	for i := 0; i < batch; i++ {
-		columns, values := b.GenFakeData(workerID, colConfs, false)
+		origWorkers := b.CommonOpts.Workers
+		var baseRate float64
 
-		blob := b.GenFakeValue(workerID, "blob", "", 0, b.TestOpts.(*TestOpts).TestcaseOpts.MaxBlobSize, b.TestOpts.(*TestOpts).TestcaseOpts.MinBlobSize, "")
+		names := make([]string, len(workerCounts))
+		for i, workers := range workerCounts {
+			names[i] = strconv.Itoa(workers)
+		}
 
-		var oid int
-		var fd int
+		testModes(b, "WORKERS", names, []string{"SCALING", "LATENCY MSEC", "FAILED", "LOST UPDATES"}, func(i int) []string {
+			workers := workerCounts[i]
+			b.CommonOpts.Workers = workers
 
-		c.QueryRowAndScan("SELECT lo_create(0)", &oid)
-		c.QueryRowAndScan(fmt.Sprintf("SELECT lo_open(%d, 131072)", oid), &fd) // 131072 == 0x20000 - write mode
+			c = dbConnector(b)
+			c.QueryRowAndScan(fmt.Sprintf("SELECT progress FROM %s WHERE id = %d", tableName, id), &progress)
+			c.Release()
 
-		c.ExecOrExit("SELECT lowrite($1, $2)", fd, blob)
-		c.ExecOrExit("SELECT lo_close($1)", fd)
+			counters := newTestCounters(b, hotRowCounters)
 
-		for c := range columns {
-			if columns[c] == "oid" {
-				values[c] = oid
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				if _, err := c.Exec(query, id); err != nil {
+					if !benchmark.IsRetryableTxError(err) && !benchmark.IsLockNotAvailable(err) {
+						c.Exit(err.Error())
+					}
+					counters.add(hotRowFailures, 1)
+
+					return 1
+				}
+				counters.add(hotRowUpdates, 1)
+
+				return 1
+			}, 1)
+
+			// every successful increment must be visible in the row, otherwise an update has been lost
+			var after int64
+			c = dbConnector(b)
+			c.QueryRowAndScan(fmt.Sprintf("SELECT progress FROM %s WHERE id = %d", tableName, id), &after)
+			c.Release()
+
+			rate := b.Score.Rate
+			if baseRate == 0 {
+				baseRate = rate
 			}
-		}
 
-		if i == 0 {
-			insertSQL := "INSERT INTO %s (%s) VALUES(%s)"
-			sqlTemplate := fmt.Sprintf(insertSQL, testDesc.table.TableName, strings.Join(columns, ","), parametersPlaceholder)
-			sql = formatSQL(sqlTemplate, testOpts.DBOpts.Driver)
-		}
+			var latency float64
+			if attempts := counters.get(hotRowUpdates) + counters.get(hotRowFailures); attempts > 0 {
+				latency = b.Score.Seconds * float64(workers) * 1000 / float64(attempts)
+			}
 
-		c.ExecOrExit(sql, values...)
-	}
-	c.Commit()
+			return []string{
+				fmt.Sprintf("%.2fx", rate/baseRate),
+				fmt.Sprintf("%.3f", latency),
+				fmt.Sprintf("%.2f%%", counters.percent(hotRowFailures, hotRowUpdates, hotRowFailures)),
+				strconv.FormatInt(int64(counters.get(hotRowUpdates))-(after-progress), 10),
+			}
+		})
 
-	return batch
+		b.CommonOpts.Workers = origWorkers
+	},
 }
 
-// TestInsertLargeObj inserts a row with large random object into the 'largeobject' table
-var TestInsertLargeObj = TestDesc{
-	name:        "insert-largeobj",
+// TestUpdateMediumDBR updates random row in the 'medium' table using golang DBR query builder
+var TestUpdateMediumDBR = TestDesc{
+	name:        "dbr-update-medium",
 	metric:      "rows/sec",
-	description: "insert a row with large random object into the 'largeobject' table",
-	category:    TestInsert,
+	description: "update random row in the 'medium' table using golang DB driver",
+	category:    TestUpdate,
 	isReadonly:  false,
-	isDBRTest:   false,
-	databases:   []string{benchmark.POSTGRES},
-	table:       TestTableLargeObj,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, createLargeObjectWorker, 0)
+		testUpdateGeneric(b, testDesc, 1, nil)
 	},
 }
 
-// TestInsertHeavy inserts a row into the 'heavy' table
-var TestInsertHeavy = TestDesc{
-	name:        "insert-heavy",
+// TestUpdateHeavy updates random row in the 'heavy' table
+var TestUpdateHeavy = TestDesc{
+	name:        "update-heavy",
 	metric:      "rows/sec",
-	description: "insert a row into the 'heavy' table",
-	category:    TestInsert,
+	description: "update random row in the 'heavy' table",
+	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
+	databases:   withCockroach(RELATIONAL),
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		testUpdateGeneric(b, testDesc, 1, nil)
+	},
+}
+
+// TestUpdateHeavyDBR updates random row in the 'heavy' table using golang DBR query builder
+var TestUpdateHeavyDBR = TestDesc{
+	name:        "dbr-update-heavy",
+	metric:      "rows/sec",
+	description: "update random row in the 'heavy' table using golang DB driver",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   true,
 	databases:   RELATIONAL,
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testInsertGeneric(b, testDesc)
+		testUpdateGeneric(b, testDesc, 1, nil)
 	},
 }
 
-// TestInsertHeavyPrepared inserts a row into the 'heavy' table using prepared statement for the batch
-var TestInsertHeavyPrepared = TestDesc{
-	name:        "insert-heavy-prepared",
+// TestUpdateHeavyBulk updates N rows (see --batch=, default 50000) in the 'heavy' table by single transaction
+var TestUpdateHeavyBulk = TestDesc{
+	name:        "bulkupdate-heavy",
 	metric:      "rows/sec",
-	description: "insert a row into the 'heavy' table using prepared statement for the batch",
-	category:    TestInsert,
+	description: "update N rows (see --batch=, default 50000) in the 'heavy' table by single transaction",
+	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
 	databases:   RELATIONAL,
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertByPreparedDataWorker, 0)
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		testBatch := origBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			testBatch = 50000
+		}
+		b.Vault.(*DBTestData).EffectiveBatch = 1
+
+		testUpdateGeneric(b, testDesc, uint64(testBatch), nil)
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
 	},
 }
 
-// TestInsertHeavyMultivalue inserts a row into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) "
-var TestInsertHeavyMultivalue = TestDesc{
-	name:        "insert-heavy-multivalue",
+// TestUpdateHeavyBulkDBR updates N rows (see --batch=, default 50000) in the 'heavy' table by single transaction using DBR query builder
+var TestUpdateHeavyBulkDBR = TestDesc{
+	name:        "dbr-bulkupdate-heavy",
 	metric:      "rows/sec",
-	description: "insert a row into the 'heavy' table using INSERT INTO t (x, y, z) VALUES (..., ..., ...) ",
-	category:    TestInsert,
+	description: "update N rows (see --update-rows-count= ) in the 'heavy' table by single transaction using DBR query builder",
+	category:    TestUpdate,
+	isReadonly:  false,
+	isDBRTest:   true,
+	databases:   RELATIONAL,
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		b.Vault.(*DBTestData).EffectiveBatch = 1
+		testBatch := origBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			testBatch = 50000
+		}
+
+		testUpdateGeneric(b, testDesc, uint64(testBatch), nil)
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+	},
+}
+
+// TestUpdateHeavySameVal updates random row in the 'heavy' table putting the value which already exists
+var TestUpdateHeavySameVal = TestDesc{
+	name:        "update-heavy-sameval",
+	metric:      "rows/sec",
+	description: "update random row in the 'heavy' table putting the value which already exists",
+	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   withCockroach(RELATIONAL),
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, insertMultiValueDataWorker, 0)
+		colConf := testDesc.table.GetColumnsConf([]string{"const_val"}, false)
+		testUpdateGeneric(b, testDesc, 1, colConf)
 	},
 }
 
-// TestCopyHeavy copies a row into the 'heavy' table
-var TestCopyHeavy = TestDesc{
-	name:        "copy-heavy",
+// TestUpdateHeavyPartialSameVal updates random row in the 'heavy' table putting two values, where one of them is already exists in this row
+var TestUpdateHeavyPartialSameVal = TestDesc{
+	name:        "update-heavy-partial-sameval",
 	metric:      "rows/sec",
-	description: "copy a row into the 'heavy' table",
-	category:    TestInsert,
+	description: "update random row in the 'heavy' table putting two values, where one of them is already exists in this row",
+	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   []string{benchmark.POSTGRES, benchmark.MSSQL},
+	databases:   withCockroach(RELATIONAL),
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testGeneric(b, testDesc, copyDataWorker, 0)
+		colConf := testDesc.table.GetColumnsConf([]string{"const_val", "progress"}, false)
+		testUpdateGeneric(b, testDesc, 1, colConf)
 	},
 }
 
-// TestInsertHeavyDBR inserts a row into the 'heavy' table using golang DB query builder
-var TestInsertHeavyDBR = TestDesc{
-	name:        "dbr-insert-heavy",
+// TestDeleteHeavy deletes random row in the 'heavy' table and re-inserts it
+var TestDeleteHeavy = TestDesc{
+	name:        "delete-heavy",
 	metric:      "rows/sec",
-	description: "insert a row into the 'heavy' table using golang DB query builder",
-	category:    TestInsert,
+	description: "delete random row in the 'heavy' table by id and re-insert the row with the same id in the same transaction",
+	category:    TestDelete,
 	isReadonly:  false,
-	isDBRTest:   true,
+	isDBRTest:   false,
 	databases:   RELATIONAL,
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testInsertGeneric(b, testDesc)
+		testDeleteReseed(b, testDesc, 1)
 	},
 }
 
-// TestInsertJSON inserts a row into a table with JSON(b) column
-var TestInsertJSON = TestDesc{
-	name:        "insert-json",
+// TestDeleteHeavyBulk deletes N rows (see --batch=, default 1000) in the 'heavy' table by single transaction and re-inserts them
+var TestDeleteHeavyBulk = TestDesc{
+	name:        "bulkdelete-heavy",
 	metric:      "rows/sec",
-	description: "insert a row into a table with JSON(b) column",
-	category:    TestInsert,
+	description: "delete the range of N ids (see --batch=, default 1000) in the 'heavy' table and re-insert the rows by single transaction",
+	category:    TestDelete,
 	isReadonly:  false,
 	isDBRTest:   false,
 	databases:   RELATIONAL,
-	table:       TestTableJSON,
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testInsertGeneric(b, testDesc)
-	},
-}
+		testBatch := b.Vault.(*DBTestData).EffectiveBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			testBatch = 1000
+		}
+		defer setBatch(b, 1)()
 
-// TestInsertJSONDBR inserts a row into a table with JSON(b) column using golang DBR driver
-var TestInsertJSONDBR = TestDesc{
-	name:        "dbr-insert-json",
-	metric:      "rows/sec",
-	description: "insert a row into a table with JSON(b) column using golang DBR driver",
-	category:    TestInsert,
-	isReadonly:  false,
-	isDBRTest:   true,
-	databases:   RELATIONAL,
-	table:       TestTableJSON,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testInsertGeneric(b, testDesc)
+		testDeleteReseed(b, testDesc, testBatch)
 	},
 }
 
-// TestSelectJSONByIndexedValue selects a row from the 'json' table by some json condition
-var TestSelectJSONByIndexedValue = TestDesc{
-	name:        "select-json-by-indexed-value",
-	metric:      "rows/sec",
-	description: "select a row from the 'json' table by some json condition",
-	category:    TestSelect,
-	isReadonly:  true,
-	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableJSON,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+// deleteReseedMaxParams is the max amount of bind parameters of a single re-inserting statement, MSSQL accepts up to 2100
+const deleteReseedMaxParams = 2000
 
-			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+// testDeleteReseed deletes the range of deleteRows random ids of the test table --batch times per transaction and then
+// re-inserts the rows with the same ids, so long runs don't exhaust the table and every loop deletes existing rows,
+// the ranges are aligned to deleteRows and striped across the workers, so concurrent re-inserts never collide
+func testDeleteReseed(b *benchmark.Benchmark, testDesc *TestDesc, deleteRows int) {
+	driver := getDBDriver(b)
+	table := testDesc.table.TableName
 
-			switch driver {
-			case benchmark.MYSQL:
-				return "_data_f0f0 = '10' AND id > " + strconv.FormatUint(id, 10)
-			case benchmark.POSTGRES:
-				return "json_data @> '{\"field0\": {\"field0\": 10}}' AND id > " + strconv.FormatUint(id, 10)
-			default:
-				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
-			}
+	colConfs := testDesc.table.GetColumnsForInsert(false)
+	columns := []string{"id"}
+	for _, conf := range *colConfs {
+		columns = append(columns, conf.ColumnName)
+	}
 
-			return ""
-		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id ASC"
+	chunk := deleteReseedMaxParams / len(columns)
+	if chunk > deleteRows {
+		chunk = deleteRows
+	}
+
+	insertQuery := func(rows int) string {
+		query := formatSQL(multiValueInsertQuery(table, columns, rows), driver)
+		if driver == benchmark.MSSQL {
+			// explicit values of an IDENTITY column must be allowed for the session
+			query = fmt.Sprintf("SET IDENTITY_INSERT %[1]s ON; %[2]s; SET IDENTITY_INSERT %[1]s OFF", table, query)
 		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
-	},
-}
 
-// TestSearchJSONByIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
-var TestSearchJSONByIndexedValue = TestDesc{
-	name:        "search-json-by-indexed-value",
-	metric:      "rows/sec",
-	description: "search a row from the 'json' table using some json condition using LIKE {}",
-	category:    TestSelect,
-	isReadonly:  true,
-	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableJSON,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+		return query
+	}
+	insertChunk := insertQuery(chunk)
+	insertTail := ""
+	if tail := deleteRows % chunk; tail > 0 {
+		insertTail = insertQuery(tail)
+	}
 
-			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+	deleteSQL := formatSQL(fmt.Sprintf("DELETE FROM %s WHERE id >= $1 AND id < $2", table), driver)
 
-			switch driver {
-			case benchmark.MYSQL:
-				return "_data_f0f0f0 LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10)
-			case benchmark.POSTGRES:
-				return "json_data->'field0'->'field0'->>'field0' LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10) // searching for the 'needle' word
-			default:
-				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
-			}
+	workers := b.CommonOpts.Workers
 
-			return ""
-		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id ASC"
+	worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+		rw := b.Randomizer.GetWorker(c.WorkerID)
+
+		// the worker owns the ranges #WorkerID, #WorkerID + workers, #WorkerID + 2 * workers, ...
+		ranges := int(testDesc.table.RowsCount) / deleteRows
+		if ranges <= c.WorkerID {
+			c.Exit("the '%s' table has %d rows, at least %d rows are required to give every of %d workers its own range of %d ids",
+				table, testDesc.table.RowsCount, workers*deleteRows, workers, deleteRows)
 		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
-	},
-}
+		owned := (ranges - c.WorkerID + workers - 1) / workers
 
-// TestSelectJSONByNonIndexedValue selects a row from the 'json' table by some json condition
-var TestSelectJSONByNonIndexedValue = TestDesc{
-	name:        "select-json-by-nonindexed-value",
-	metric:      "rows/sec",
-	description: "select a row from the 'json' table by some json condition",
-	category:    TestSelect,
-	isReadonly:  true,
-	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableJSON,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
+		err := c.Transact(func() error {
+			for i := 0; i < batch; i++ {
+				first := 1 + int64((c.WorkerID+workers*rw.Intn(owned))*deleteRows)
 
-			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+				if _, err := c.Exec(deleteSQL, first, first+int64(deleteRows)); err != nil {
+					return err
+				}
 
-			switch driver {
-			case benchmark.MYSQL:
-				return "JSON_EXTRACT(json_data, '$.field0.field1') = '10' AND id > " + strconv.FormatUint(id, 10)
-			case benchmark.POSTGRES:
-				return "json_data @> '{\"field0\": {\"field1\": 10}}' AND id > " + strconv.FormatUint(id, 10)
-			default:
-				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
+				for id := first; id < first+int64(deleteRows); id += int64(chunk) {
+					rows, query := chunk, insertChunk
+					if left := int(first + int64(deleteRows) - id); left < chunk {
+						rows, query = left, insertTail
+					}
+
+					values := make([]interface{}, 0, rows*len(columns))
+					for r := 0; r < rows; r++ {
+						_, rowValues := b.GenFakeData(c.WorkerID, colConfs, false)
+						values = append(values, id+int64(r))
+						values = append(values, rowValues...)
+					}
+
+					if _, err := c.Exec(query, values...); err != nil {
+						return err
+					}
+				}
 			}
 
-			return ""
-		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id ASC"
+			return nil
+		})
+		if err != nil {
+			c.Exit(err.Error())
 		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
-	},
+
+		return batch * deleteRows
+	}
+
+	testGeneric(b, testDesc, worker, uint64(deleteRows))
 }
 
-// TestSearchJSONByNonIndexedValue searches a row from the 'json' table using some json condition using LIKE {}
-var TestSearchJSONByNonIndexedValue = TestDesc{
-	name:        "search-json-by-nonindexed-value",
+// TestUpdateThenVacuumCost updates random rows in the 'heavy' table and then measures the VACUUM (ANALYZE) / OPTIMIZE TABLE duration and reclaimed space
+var TestUpdateThenVacuumCost = TestDesc{
+	name:        "update-heavy-then-vacuum",
 	metric:      "rows/sec",
-	description: "search a row from the 'json' table using some json condition using LIKE {}",
-	category:    TestSelect,
-	isReadonly:  true,
+	description: "update random rows in the 'heavy' table, then run VACUUM (ANALYZE) / OPTIMIZE TABLE once and report its duration and reclaimed space",
+	category:    TestUpdate,
+	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableJSON,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL},
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			id := b.Randomizer.GetWorker(workerId).Uintn64(testDesc.table.RowsCount - 1)
-
-			driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+		testUpdateGeneric(b, testDesc, 1, nil)
 
-			switch driver {
-			case benchmark.MYSQL:
-				return "JSON_EXTRACT(json_data, '$.field0.field1') LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10)
-			case benchmark.POSTGRES:
-				return "json_data->'field0'->'field0'->>'field0' LIKE '%eedl%' AND id > " + strconv.FormatUint(id, 10) // searching for the 'needle' word
-			default:
-				b.Exit("The %s test is not supported on driver: %s", testDesc.name, driver)
-			}
+		tableName := testDesc.table.TableName
 
-			return ""
-		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id ASC"
+		var maintenanceSQL string
+		switch getDBDriver(b) {
+		case benchmark.MYSQL:
+			maintenanceSQL = "OPTIMIZE TABLE " + tableName
+		default:
+			maintenanceSQL = "VACUUM (ANALYZE) " + tableName
 		}
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+
+		c := dbConnector(b)
+		defer c.Release()
+
+		sizeBefore := c.GetTableSizeMB(tableName) + c.GetIndexesSizeMB(tableName)
+
+		start := time.Now()
+		c.QueryOrExit(maintenanceSQL)
+		duration := time.Since(start)
+
+		sizeAfter := c.GetTableSizeMB(tableName) + c.GetIndexesSizeMB(tableName)
+
+		fmt.Fprintf(out(b), "maintenance: %s; duration: %.3f sec; size before: %d MB; size after: %d MB; reclaimed: %d MB\n",
+			maintenanceSQL, duration.Seconds(), sizeBefore, sizeAfter, sizeBefore-sizeAfter)
 	},
 }
 
-// TestUpdateMedium updates random row in the 'medium' table
-var TestUpdateMedium = TestDesc{
-	name:        "update-medium",
+// TestUpdateHeavyFromAggregate updates rows of a random tenant in the 'heavy' table putting the aggregate (rows count) of the tenant rows
+var TestUpdateHeavyFromAggregate = TestDesc{
+	name:        "update-heavy-from-aggregate",
 	metric:      "rows/sec",
-	description: "update random row in the 'medium' table",
+	description: "update rows of a random tenant in the 'heavy' table by UPDATE ... FROM (SELECT tenant_id, COUNT(*) ... GROUP BY tenant_id)",
 	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableMedium,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
+	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
+		tableName := testDesc.table.TableName
+		aggregateSQL := "SELECT tenant_id, COUNT(*) c FROM " + tableName + " WHERE tenant_id = '%s' GROUP BY tenant_id"
+
+		var queryTpl string
+
+		switch getDBDriver(b) {
+		case benchmark.MYSQL:
+			queryTpl = "UPDATE " + tableName + " h JOIN (" + aggregateSQL + ") sub ON h.tenant_id = sub.tenant_id SET h.progress = sub.c"
+		case benchmark.MSSQL:
+			queryTpl = "UPDATE h SET h.progress = sub.c FROM " + tableName + " h JOIN (" + aggregateSQL + ") sub ON h.tenant_id = sub.tenant_id"
+		default:
+			queryTpl = "UPDATE " + tableName + " SET progress = sub.c FROM (" + aggregateSQL + ") sub WHERE " + tableName + ".tenant_id = sub.tenant_id"
+		}
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+
+			result, err := c.Exec(fmt.Sprintf(queryTpl, (*w)["tenant_id"]))
+			if err != nil {
+				b.Exit(err.Error())
+			}
+
+			if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+				return int(rows)
+			}
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
 	},
 }
 
-// TestUpdateMediumDBR updates random row in the 'medium' table using golang DBR query builder
-var TestUpdateMediumDBR = TestDesc{
-	name:        "dbr-update-medium",
-	metric:      "rows/sec",
-	description: "update random row in the 'medium' table using golang DB driver",
-	category:    TestUpdate,
-	isReadonly:  false,
-	isDBRTest:   true,
-	databases:   RELATIONAL,
-	table:       TestTableMedium,
-	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
-	},
+const (
+	tempJoinTableName  = "acronis_db_bench_heavy_tmp_join" // tempJoinTableName is a temporary table with (id, progress) pairs to be applied to the 'heavy' table
+	tempJoinInsertRows = 500                               // tempJoinInsertRows is max rows per INSERT into the temporary table (MSSQL allows up to 2100 parameters)
+)
+
+// tempJoinQueries returns queries to create and drop the temporary table and the set-based UPDATE query for given driver
+func tempJoinQueries(driver string, tableName string) (tmpTable string, createTmp string, dropTmp string, update string) {
+	switch driver {
+	case benchmark.POSTGRES:
+		tmpTable = tempJoinTableName
+		createTmp = fmt.Sprintf("CREATE TEMPORARY TABLE %s (id bigint primary key, progress int) ON COMMIT DROP", tmpTable)
+		update = fmt.Sprintf("UPDATE %s SET progress = t.progress FROM %s t WHERE %s.id = t.id", tableName, tmpTable, tableName)
+	case benchmark.MYSQL:
+		tmpTable = tempJoinTableName
+		createTmp = fmt.Sprintf("CREATE TEMPORARY TABLE %s (id bigint primary key, progress int)", tmpTable)
+		dropTmp = fmt.Sprintf("DROP TEMPORARY TABLE %s", tmpTable)
+		update = fmt.Sprintf("UPDATE %s h JOIN %s t ON h.id = t.id SET h.progress = t.progress", tableName, tmpTable)
+	case benchmark.MSSQL:
+		tmpTable = "#" + tempJoinTableName
+		createTmp = fmt.Sprintf("CREATE TABLE %s (id bigint primary key, progress int)", tmpTable)
+		dropTmp = fmt.Sprintf("DROP TABLE %s", tmpTable)
+		update = fmt.Sprintf("MERGE INTO %s AS h USING %s AS t ON h.id = t.id WHEN MATCHED THEN UPDATE SET h.progress = t.progress;", tableName, tmpTable)
+	}
+
+	return tmpTable, createTmp, dropTmp, update
 }
 
-// TestUpdateHeavy updates random row in the 'heavy' table
-var TestUpdateHeavy = TestDesc{
-	name:        "update-heavy",
+// TestUpdateHeavyViaTempJoin loads N (id, value) pairs (see --batch=, default 10000) into a temporary table and applies them to the 'heavy' table by single UPDATE ... FROM
+var TestUpdateHeavyViaTempJoin = TestDesc{
+	name:        "update-heavy-via-temp-join",
 	metric:      "rows/sec",
-	description: "update random row in the 'heavy' table",
+	description: "load N (id, value) pairs (see --batch=, default 10000) into a temp table and apply them by UPDATE ... FROM / JOIN / MERGE, compare with 'update-heavy'",
 	category:    TestUpdate,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
+		defer setDefaultBatch(b, 10000)()
+
+		driver := getDBDriver(b)
+		tmpTable, createTmpSQL, dropTmpSQL, updateSQL := tempJoinQueries(driver, testDesc.table.TableName)
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			rw := b.Randomizer.GetWorker(c.WorkerID)
+
+			if uint64(batch) > testDesc.table.RowsCount {
+				batch = int(testDesc.table.RowsCount)
+			}
+
+			ids := make(map[int64]bool, batch)
+			for len(ids) < batch {
+				ids[int64(rw.Uintn64(testDesc.table.RowsCount)+1)] = true
+			}
+
+			// the temporary table is visible only within the session, so everything must be done in the same transaction
+			c.Begin()
+			c.ExecOrExit(createTmpSQL)
+
+			var placeholders []string
+			var values []interface{}
+
+			flush := func() {
+				if len(values) == 0 {
+					return
+				}
+				c.ExecOrExit(formatSQL(fmt.Sprintf("INSERT INTO %s (id, progress) VALUES %s", tmpTable, strings.Join(placeholders, ", ")), driver), values...)
+				placeholders = placeholders[:0]
+				values = values[:0]
+			}
+
+			for id := range ids {
+				placeholders = append(placeholders, fmt.Sprintf("(%s)", benchmark.GenDBParameterPlaceholders(len(values), 2)))
+				values = append(values, id, rw.Intn(100))
+				if len(placeholders) == tempJoinInsertRows {
+					flush()
+				}
+			}
+			flush()
+
+			c.ExecOrExit(updateSQL)
+
+			if dropTmpSQL != "" {
+				c.ExecOrExit(dropTmpSQL)
+			}
+			c.Commit()
+
+			return batch
+		}, 1)
 	},
 }
 
-// TestUpdateHeavyDBR updates random row in the 'heavy' table using golang DBR query builder
-var TestUpdateHeavyDBR = TestDesc{
-	name:        "dbr-update-heavy",
+// TestCreateTableAsSelect materializes the rows of a random tenant from the 'heavy' table into a new table using CREATE TABLE AS SELECT (SELECT INTO on MSSQL)
+var TestCreateTableAsSelect = TestDesc{
+	name:        "create-table-as-select-heavy",
 	metric:      "rows/sec",
-	description: "update random row in the 'heavy' table using golang DB driver",
-	category:    TestUpdate,
+	description: "materialize the rows of a random tenant from the 'heavy' table by CREATE TABLE AS SELECT (SELECT INTO on MSSQL) and drop the new table",
+	category:    TestOther,
 	isReadonly:  false,
-	isDBRTest:   true,
-	databases:   RELATIONAL,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL},
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		testUpdateGeneric(b, testDesc, 1, nil)
+		driver := getDBDriver(b)
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+		columns := "id, uuid, tenant_id, state, progress, update_time_ns"
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+			ctasTableName := fmt.Sprintf("%s_ctas_%d", testDesc.table.TableName, c.WorkerID)
+			where := fmt.Sprintf("tenant_id = '%s'", (*w)["tenant_id"])
+
+			var query string
+			switch driver {
+			case benchmark.MSSQL:
+				query = fmt.Sprintf("SELECT %s INTO %s FROM %s WHERE %s", columns, ctasTableName, testDesc.table.TableName, where)
+			default:
+				query = fmt.Sprintf("CREATE TABLE %s AS SELECT %s FROM %s WHERE %s", ctasTableName, columns, testDesc.table.TableName, where)
+			}
+
+			// not c.DropTable(), because --use-truncate would keep the table
+			dropSQL := "DROP TABLE IF EXISTS " + ctasTableName
+			c.ExecOrExit(dropSQL)
+
+			result, err := c.Exec(query)
+			if err != nil {
+				b.Exit(err.Error())
+			}
+
+			c.ExecOrExit(dropSQL)
+
+			if rows, err := result.RowsAffected(); err == nil && rows > 0 {
+				return int(rows)
+			}
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
 	},
 }
 
-// TestUpdateHeavyBulk updates N rows (see --batch=, default 50000) in the 'heavy' table by single transaction
-var TestUpdateHeavyBulk = TestDesc{
-	name:        "bulkupdate-heavy",
+/*
+ * Cassandra lightweight transactions (LWT) tests
+ */
+
+const lwtRowsPerWorker = 1000 // lwtRowsPerWorker is a number of rows cached by every worker for conditional updates
+
+// the applied / not applied counters of the conditional statements
+const (
+	lwtApplied    = iota // applied statements
+	lwtNotApplied        // statements not applied as the condition doesn't hold
+	lwtCounters
+)
+
+// printLWT prints applied / not applied ratio
+func printLWT(b *benchmark.Benchmark, counters testCounters) {
+	fmt.Fprintf(out(b), "LWT applied: %d; not applied: %d; applied ratio: %.2f%%\n", counters.get(lwtApplied), counters.get(lwtNotApplied),
+		counters.percent(lwtApplied, lwtApplied, lwtNotApplied))
+}
+
+// execLWT executes a conditional (IF ...) statement and returns the [applied] flag along with the current row values returned when the statement is not applied
+func execLWT(c *benchmark.DBConnector, counters testCounters, query string, args ...interface{}) (applied bool, current []interface{}) {
+	rows, err := c.Query(query, args...)
+	if err != nil {
+		c.Exit(err.Error())
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		c.Exit(err.Error())
+	}
+
+	if !rows.Next() {
+		c.Exit("LWT query returned no [applied] row: %s", query)
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	if err = rows.Scan(pointers...); err != nil {
+		c.Exit(err.Error())
+	}
+
+	applied, _ = values[0].(bool)
+	if applied {
+		counters.add(lwtApplied, 1)
+	} else {
+		counters.add(lwtNotApplied, 1)
+	}
+
+	return applied, values[1:]
+}
+
+// TestInsertLightLWT inserts a row into the 'light' table using INSERT ... IF NOT EXISTS (Paxos based lightweight transaction)
+var TestInsertLightLWT = TestDesc{
+	name:        "insert-light-lwt",
 	metric:      "rows/sec",
-	description: "update N rows (see --batch=, default 50000) in the 'heavy' table by single transaction",
-	category:    TestUpdate,
+	description: "insert a row into the 'light' table using INSERT ... IF NOT EXISTS, compare with 'insert-light' to see the LWT cost",
+	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
+	databases:   []string{benchmark.CASSANDRA},
+	table:       TestTableLight,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		origBatch := b.Vault.(*DBTestData).EffectiveBatch
-		testBatch := origBatch
-		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
-			testBatch = 50000
-		}
-		b.Vault.(*DBTestData).EffectiveBatch = 1
+		counters := newTestCounters(b, lwtCounters)
 
-		testUpdateGeneric(b, testDesc, uint64(testBatch), nil)
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(c.DbOpts.Driver))
 
-		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+			for i := 0; i < batch; i++ {
+				columns, values := b.GenFakeData(c.WorkerID, colConfs, benchmark.WithAutoInc(c.DbOpts.Driver))
+				query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) IF NOT EXISTS", testDesc.table.TableName, strings.Join(columns, ","),
+					benchmark.GenDBParameterPlaceholdersCassandra(0, len(columns)))
+				execLWT(c, counters, query, values...)
+			}
+
+			return batch
+		}, 0)
+
+		printLWT(b, counters)
 	},
 }
 
-// TestUpdateHeavyBulkDBR updates N rows (see --batch=, default 50000) in the 'heavy' table by single transaction using DBR query builder
-var TestUpdateHeavyBulkDBR = TestDesc{
-	name:        "dbr-bulkupdate-heavy",
+// TestUpdateLightLWT updates a row in the 'light' table using UPDATE ... IF uuid = ? (Paxos based lightweight transaction)
+var TestUpdateLightLWT = TestDesc{
+	name:        "update-light-lwt",
 	metric:      "rows/sec",
-	description: "update N rows (see --update-rows-count= ) in the 'heavy' table by single transaction using DBR query builder",
+	description: "update a row in the 'light' table using UPDATE ... IF uuid = ?, workers compete for the same rows",
 	category:    TestUpdate,
 	isReadonly:  false,
-	isDBRTest:   true,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
+	isDBRTest:   false,
+	databases:   []string{benchmark.CASSANDRA},
+	table:       TestTableLight,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		origBatch := b.Vault.(*DBTestData).EffectiveBatch
-		b.Vault.(*DBTestData).EffectiveBatch = 1
-		testBatch := origBatch
-		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
-			testBatch = 50000
-		}
+		counters := newTestCounters(b, lwtCounters)
+
+		// every worker caches (id, uuid) of the same rows, so concurrent updates of the same row are not applied
+		cache := make([][][]interface{}, b.CommonOpts.Workers)
+		tableName := testDesc.table.TableName
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			rows := cache[c.WorkerID]
+			if rows == nil {
+				r := c.SelectRaw(false, fmt.Sprintf("SELECT id, uuid FROM %s LIMIT %d", tableName, lwtRowsPerWorker))
+				for r.Next() {
+					var id, uuid interface{}
+					if err := r.Scan(&id, &uuid); err != nil {
+						b.Exit(err.Error())
+					}
+					rows = append(rows, []interface{}{id, uuid})
+				}
+				cache[c.WorkerID] = rows
+			}
 
-		testUpdateGeneric(b, testDesc, uint64(testBatch), nil)
+			rw := b.Randomizer.GetWorker(c.WorkerID)
+			query := fmt.Sprintf("UPDATE %s SET uuid = ? WHERE id = ? IF uuid = ?", tableName)
 
-		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+			for i := 0; i < batch; i++ {
+				row := rows[rw.Intn(len(rows))]
+				newUUID := rw.UUID()
+
+				applied, current := execLWT(c, counters, query, newUUID, row[0], row[1])
+				if applied {
+					row[1] = newUUID
+				} else if len(current) > 0 {
+					row[1] = current[0]
+				}
+			}
+
+			return batch
+		}, 1)
+
+		printLWT(b, counters)
 	},
 }
 
-// TestUpdateHeavySameVal updates random row in the 'heavy' table putting the value which already exists
-var TestUpdateHeavySameVal = TestDesc{
-	name:        "update-heavy-sameval",
+// the counters of a single consistency pair of the 'read-after-write-consistency' test
+const (
+	rawWrites    = iota // written rows
+	rawVisible          // written rows visible to the immediate read
+	rawWriteTime        // time spent on the writes, nsec
+	rawReadTime         // time spent on the reads, nsec
+	rawCounters
+)
+
+// TestReadAfterWriteConsistency writes a row at one consistency level and immediately reads it at another one
+var TestReadAfterWriteConsistency = TestDesc{
+	name:        "read-after-write-consistency",
 	metric:      "rows/sec",
-	description: "update random row in the 'heavy' table putting the value which already exists",
-	category:    TestUpdate,
+	description: "insert a row into the 'light' table at one consistency level and immediately read it at another, report the visibility rate, see --consistency-pairs",
+	category:    TestOther,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
-	table:       TestTableHeavy,
+	databases:   []string{benchmark.CASSANDRA},
+	table:       TestTableLight,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		colConf := testDesc.table.GetColumnsConf([]string{"const_val"}, false)
-		testUpdateGeneric(b, testDesc, 1, colConf)
+		tableName := testDesc.table.TableName
+		pairs := strings.Split(b.TestOpts.(*TestOpts).TestcaseOpts.ConsistencyPairs, ",")
+
+		names := make([]string, len(pairs))
+		for i, pair := range pairs {
+			writeLevel, readLevel, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(pair)), ":")
+			if !ok || writeLevel == "" || readLevel == "" {
+				b.Exit("invalid --consistency-pairs value: '%s', expected comma-separated write:read pairs, e.g. ONE:QUORUM", pair)
+			}
+			names[i] = writeLevel + ":" + readLevel
+		}
+
+		testModes(b, "WRITE:READ", names, []string{"VISIBLE", "WRITE, ms", "READ, ms"}, func(i int) []string {
+			writeLevel, readLevel, _ := strings.Cut(names[i], ":")
+
+			writeOpts := b.TestOpts.(*TestOpts).DBOpts
+			writeOpts.Dsn = withCassandraConsistency(writeOpts.Dsn, writeLevel)
+			readOpts := b.TestOpts.(*TestOpts).DBOpts
+			readOpts.Dsn = withCassandraConsistency(readOpts.Dsn, readLevel)
+
+			writers := make([]*benchmark.DBConnector, b.CommonOpts.Workers)
+			readers := make([]*benchmark.DBConnector, b.CommonOpts.Workers)
+			for w := range writers {
+				writers[w] = benchmark.NewDBConnector(&writeOpts, w, b.Logger, 10)
+				writers[w].Connect()
+
+				// the connectors of the same DSN and worker share the pool slot, so the same level pair shares the connector
+				if readOpts.Dsn == writeOpts.Dsn {
+					readers[w] = writers[w]
+				} else {
+					readers[w] = benchmark.NewDBConnector(&readOpts, w, b.Logger, 10)
+					readers[w].Connect()
+				}
+			}
+
+			counters := newTestCounters(b, rawCounters)
+
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				rw := b.Randomizer.GetWorker(c.WorkerID)
+				id := int64(rw.Uintn64(1 << 62))
+
+				start := time.Now()
+				writers[c.WorkerID].ExecOrExit(fmt.Sprintf("INSERT INTO %s (id, uuid) VALUES (?, ?)", tableName), id, rw.UUID())
+				counters.addTime(rawWriteTime, start)
+
+				var count int
+				start = time.Now()
+				readers[c.WorkerID].QueryRowAndScan(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = %d", tableName, id), &count)
+				counters.addTime(rawReadTime, start)
+
+				counters.add(rawWrites, 1)
+				if count > 0 {
+					counters.add(rawVisible, 1)
+				}
+
+				return 1
+			}, 0)
+
+			for w := range writers {
+				writers[w].Release()
+				if readers[w] != writers[w] {
+					readers[w].Release()
+				}
+			}
+
+			return []string{
+				fmt.Sprintf("%.3f%%", counters.percent(rawVisible, rawWrites)),
+				fmt.Sprintf("%.3f", counters.avgMsec(rawWriteTime, rawWrites)),
+				fmt.Sprintf("%.3f", counters.avgMsec(rawReadTime, rawWrites)),
+			}
+		})
 	},
 }
 
-// TestUpdateHeavyPartialSameVal updates random row in the 'heavy' table putting two values, where one of them is already exists in this row
-var TestUpdateHeavyPartialSameVal = TestDesc{
-	name:        "update-heavy-partial-sameval",
-	metric:      "rows/sec",
-	description: "update random row in the 'heavy' table putting two values, where one of them is already exists in this row",
-	category:    TestUpdate,
+/*
+ * Online DDL tests
+ */
+
+const (
+	onlineDDLColumn       = "ddl_probe"             // onlineDDLColumn is a column added and dropped by the DDL worker
+	onlineDDLInterval     = 100 * time.Millisecond  // onlineDDLInterval is a delay between two DDL statements
+	onlineDDLStallTimeout = 1000 * time.Millisecond // onlineDDLStallTimeout is a DML duration considered as a stall
+)
+
+// the DML counters of the 'online-ddl-under-load' test while DDL is running on the same table
+const (
+	onlineDDLStatements = iota // DDL statements
+	onlineDMLStatements        // DML statements
+	onlineDMLErrors            // failed DML statements
+	onlineDMLStalls            // DML statements longer than onlineDDLStallTimeout
+	onlineDDLCounters
+)
+
+// onlineDDLQueries returns ADD COLUMN and DROP COLUMN queries for given driver
+func onlineDDLQueries(driver string, tableName string) (addColumn string, dropColumn string) {
+	switch driver {
+	case benchmark.MSSQL:
+		addColumn = fmt.Sprintf("ALTER TABLE %s ADD %s int", tableName, onlineDDLColumn)
+	default:
+		addColumn = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s int", tableName, onlineDDLColumn)
+	}
+	dropColumn = fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName, onlineDDLColumn)
+
+	return addColumn, dropColumn
+}
+
+// TestOnlineDDLUnderLoad adds and drops a column in the 'medium' table by worker #0 while other workers do INSERT and UPDATE
+var TestOnlineDDLUnderLoad = TestDesc{
+	name:        "online-ddl-under-load",
+	metric:      "ops/sec",
+	description: "add/drop a column in the 'medium' table by one worker while other workers do INSERT/UPDATE, report DML errors and stalls",
+	category:    TestOther,
 	isReadonly:  false,
 	isDBRTest:   false,
 	databases:   RELATIONAL,
-	table:       TestTableHeavy,
+	table:       TestTableMedium,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
-		colConf := testDesc.table.GetColumnsConf([]string{"const_val", "progress"}, false)
-		testUpdateGeneric(b, testDesc, 1, colConf)
+		if b.CommonOpts.Workers < 2 {
+			b.Exit("the '%s' test requires at least 2 workers (one for DDL and others for DML), use -c option", testDesc.name)
+		}
+
+		driver := getDBDriver(b)
+		table := &testDesc.table
+		colConfs := table.GetColumnsForInsert(benchmark.WithAutoInc(driver))
+		addColumnSQL, dropColumnSQL := onlineDDLQueries(driver, table.TableName)
+		updateSQL := formatSQL(fmt.Sprintf("UPDATE %s SET progress = $1 WHERE id = $2", table.TableName), driver)
+
+		counters := newTestCounters(b, onlineDDLCounters)
+		var columnAdded bool // accessed by worker #0 only
+
+		initCommon(b, testDesc, 1)
+
+		finishPerWorker := b.FinishPerWorker
+		b.FinishPerWorker = func(workerId int) {
+			if workerId == 0 && columnAdded {
+				c := b.WorkerData[workerId].(*DBWorkerData).conn
+				c.ExecOrExit(dropColumnSQL)
+				columnAdded = false
+			}
+			finishPerWorker(workerId)
+		}
+
+		b.Worker = func(workerId int) (loops int) {
+			c := b.WorkerData[workerId].(*DBWorkerData).conn
+
+			if workerId == 0 {
+				if columnAdded {
+					c.ExecOrExit(dropColumnSQL)
+				} else {
+					c.ExecOrExit(addColumnSQL)
+				}
+				columnAdded = !columnAdded
+				counters.add(onlineDDLStatements, 1)
+				time.Sleep(onlineDDLInterval)
+
+				return 1
+			}
+
+			rw := b.Randomizer.GetWorker(workerId)
+
+			var query string
+			var values []interface{}
+
+			if rw.Intn(2) == 0 {
+				var columns []string
+				columns, values = b.GenFakeData(workerId, colConfs, benchmark.WithAutoInc(driver))
+				query = formatSQL(fmt.Sprintf("INSERT INTO %s (%s) VALUES(%s)", table.TableName, strings.Join(columns, ","),
+					benchmark.GenDBParameterPlaceholders(0, len(columns))), driver)
+			} else {
+				query = updateSQL
+				values = []interface{}{rw.Intn(100), int64(rw.Uintn64(table.RowsCount) + 1)}
+			}
+
+			start := time.Now()
+			_, err := c.Exec(query, values...)
+			if err != nil {
+				counters.add(onlineDMLErrors, 1)
+			} else if time.Since(start) > onlineDDLStallTimeout {
+				counters.add(onlineDMLStalls, 1)
+			}
+			counters.add(onlineDMLStatements, 1)
+
+			return 1
+		}
+
+		b.Run()
+
+		b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
+
+		fmt.Fprintf(out(b), "DDL statements: %d; DML statements: %d; DML errors: %d (%.2f%%); DML stalls (> %v): %d (%.2f%%)\n",
+			counters.get(onlineDDLStatements), counters.get(onlineDMLStatements),
+			counters.get(onlineDMLErrors), counters.percent(onlineDMLErrors, onlineDMLStatements),
+			onlineDDLStallTimeout, counters.get(onlineDMLStalls), counters.percent(onlineDMLStalls, onlineDMLStatements))
 	},
 }
 
@@ -1296,7 +5915,7 @@ var TestInsertTenant = TestDesc{
 	metric:      "tenants/sec",
 	description: "insert a tenant into the 'tenants' table",
 	category:    TestInsert,
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	table:       TestTableTenants,
 	isReadonly:  false,
 	isDBRTest:   false,
@@ -1311,7 +5930,7 @@ var TestInsertCTI = TestDesc{
 	metric:      "ctiEntity/sec",
 	description: "insert a CTI entity into the 'cti' table",
 	category:    TestInsert,
-	databases:   ALL,
+	databases:   withCockroach(ALL),
 	table:       TestTableCTIEntities,
 	isReadonly:  false,
 	isDBRTest:   false,
@@ -1332,52 +5951,267 @@ var TestInsertTimeSeriesSQL = TestDesc{
 	category:    TestInsert,
 	isReadonly:  false,
 	isDBRTest:   false,
-	databases:   PMWSA,
+	databases:   withCockroach(PMWSA),
+	table:       TestTableTimeSeriesSQL,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			b.Vault.(*DBTestData).EffectiveBatch = 256
+		}
+
+		testInsertGeneric(b, testDesc)
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+	},
+}
+
+// TestSelectTimeSeriesSQL selects last inserted row from the 'timeseries' SQL table
+var TestSelectTimeSeriesSQL = TestDesc{
+	name:        "select-ts-sql",
+	metric:      "values/sec",
+	description: "batch select from the 'timeseries' SQL table",
+	category:    TestSelect,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   PMWSA,
+	table:       TestTableTimeSeriesSQL,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+
+		origBatch := b.Vault.(*DBTestData).EffectiveBatch
+		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
+			b.Vault.(*DBTestData).EffectiveBatch = 256
+		}
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "device_id", "metric_id"}, false)
+
+		where := func(b *benchmark.Benchmark, workerId int) string {
+			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+
+			return fmt.Sprintf("tenant_id = '%s' AND device_id = '%s' AND metric_id = '%s'", (*w)["tenant_id"], (*w)["device_id"], (*w)["metric_id"])
+		}
+		orderby := func(b *benchmark.Benchmark) string {
+			return "id DESC"
+		}
+
+		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+
+		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+	},
+}
+
+// tsWindow is the length of the time window read by the 'select-ts-sql-recency-skewed' test
+const tsWindow = time.Hour
+
+// tsWindowAgeBuckets are the window age buckets the 'select-ts-sql-recency-skewed' test latencies are reported by
+var tsWindowAgeBuckets = []struct {
+	name   string
+	maxAge time.Duration // 0 - unlimited
+}{
+	{"last hour", time.Hour},
+	{"1h - 1d", 24 * time.Hour},
+	{"1d - 7d", 7 * 24 * time.Hour},
+	{"older", 0},
+}
+
+// recencySkewedAge returns a random age of the queried window: recentShare percent of the windows are within the last hour,
+// the rest are spread log-uniformly up to the given history depth, so the older the data the more rarely it's read
+func recencySkewedAge(r *rand.Rand, recentShare int, history time.Duration) time.Duration {
+	if history <= tsWindow || r.Float64()*100 < float64(recentShare) {
+		return time.Duration(r.Float64() * float64(tsWindow))
+	}
+
+	return time.Duration(float64(tsWindow) * math.Exp(r.Float64()*math.Log(float64(history)/float64(tsWindow))))
+}
+
+// tsWindowAgeBucket returns the index of the tsWindowAgeBuckets bucket given window age belongs to
+func tsWindowAgeBucket(age time.Duration) int {
+	for i, bucket := range tsWindowAgeBuckets {
+		if bucket.maxAge == 0 || age < bucket.maxAge {
+			return i
+		}
+	}
+
+	return len(tsWindowAgeBuckets) - 1
+}
+
+// TestSelectTimeSeriesRecencySkewed is the same as TestSelectTimeSeriesSQL but reads a time window drawn from a recency-biased distribution
+var TestSelectTimeSeriesRecencySkewed = TestDesc{
+	name:        "select-ts-sql-recency-skewed",
+	metric:      "queries/sec",
+	description: "select a time window from the 'timeseries' SQL table, recent windows are read far more often than old ones, report latency by window age, see --ts-recent-share",
+	category:    TestSelect,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.MSSQL, benchmark.SQLITE},
 	table:       TestTableTimeSeriesSQL,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		const tsLayout = "2006-01-02 15:04:05"
 
-		origBatch := b.Vault.(*DBTestData).EffectiveBatch
-		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
-			b.Vault.(*DBTestData).EffectiveBatch = 256
+		defer setDefaultBatch(b, 256)()
+
+		recentShare := b.TestOpts.(*TestOpts).TestcaseOpts.TSRecentShare
+		history := time.Duration(b.TestOpts.(*TestOpts).TestcaseOpts.TSHistoryDays) * 24 * time.Hour
+		explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "device_id", "metric_id"}, false)
+
+		// the queries and the latency of the i-th bucket are the counters i and len(tsWindowAgeBuckets)+i
+		counters := newTestCounters(b, 2*len(tsWindowAgeBuckets))
+		queries, latency := 0, len(tsWindowAgeBuckets)
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+
+			age := recencySkewedAge(b.Randomizer.GetWorker(c.WorkerID).Seeded(), recentShare, history)
+			end := time.Now().UTC().Add(-age)
+
+			where := fmt.Sprintf("tenant_id = '%s' AND device_id = '%s' AND metric_id = '%s' AND ts >= '%s' AND ts < '%s'",
+				(*w)["tenant_id"], (*w)["device_id"], (*w)["metric_id"], end.Add(-tsWindow).Format(tsLayout), end.Format(tsLayout))
+
+			start := time.Now()
+			c.Select(testDesc.table.TableName, "id", where, "id DESC", batch, explain)
+
+			bucket := tsWindowAgeBucket(age)
+			counters.add(queries+bucket, 1)
+			counters.addTime(latency+bucket, start)
+
+			return 1
+		}, 1)
+
+		var total uint64
+		for i := range tsWindowAgeBuckets {
+			total += counters.get(queries + i)
 		}
 
-		testInsertGeneric(b, testDesc)
+		fmt.Fprintf(out(b), "%15s %10s %10s %15s\n", "WINDOW AGE", "QUERIES", "SHARE", "AVG LATENCY")
+		for i, bucket := range tsWindowAgeBuckets {
+			n := counters.get(queries + i)
+			if n == 0 {
+				fmt.Fprintf(out(b), "%15s %10d %9.1f%% %15s\n", bucket.name, 0, 0.0, "-")
 
-		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+				continue
+			}
+			avg := time.Duration(counters.get(latency+i) / n)
+			fmt.Fprintf(out(b), "%15s %10d %9.1f%% %15s\n", bucket.name, n, 100*float64(n)/float64(total), avg.Round(time.Microsecond))
+		}
 	},
 }
 
-// TestSelectTimeSeriesSQL selects last inserted row from the 'timeseries' SQL table
-var TestSelectTimeSeriesSQL = TestDesc{
-	name:        "select-ts-sql",
-	metric:      "values/sec",
-	description: "batch select from the 'timeseries' SQL table",
+// timeBucketExpr returns the SQL expression truncating given timestamp column to the buckets of given amount of seconds
+func timeBucketExpr(driver string, column string, seconds int) string {
+	switch driver {
+	case benchmark.POSTGRES:
+		return fmt.Sprintf("to_timestamp(floor(extract(epoch from %s) / %d) * %d)", column, seconds, seconds)
+	case benchmark.MYSQL:
+		return fmt.Sprintf("FROM_UNIXTIME(FLOOR(UNIX_TIMESTAMP(%s) / %d) * %d)", column, seconds, seconds)
+	case benchmark.MSSQL:
+		return fmt.Sprintf("DATEADD(second, DATEDIFF(second, '2000-01-01', %s) / %d * %d, '2000-01-01')", column, seconds, seconds)
+	case benchmark.SQLITE:
+		return fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER) / %d * %d", column, seconds, seconds)
+	case benchmark.CLICKHOUSE:
+		return fmt.Sprintf("toStartOfInterval(%s, INTERVAL %d second)", column, seconds)
+	default:
+		return ""
+	}
+}
+
+// TestSelectTimeSeriesRollingWindow ingests a value into the 'timeseries' SQL table and then reads the time buckets averages of
+// the window ending now, like a live monitoring dashboard polling a chart while the data grows
+var TestSelectTimeSeriesRollingWindow = TestDesc{
+	name:        "select-ts-sql-rolling-window",
+	metric:      "queries/sec",
+	description: "insert a value into the 'timeseries' SQL table and select AVG(value) GROUP BY time bucket over the window sliding with now(), report latency as the table grows, see --ts-rolling-window and --ts-bucket-seconds",
 	category:    TestSelect,
 	isReadonly:  false,
 	isDBRTest:   false,
 	databases:   PMWSA,
 	table:       TestTableTimeSeriesSQL,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		const tsLayout = "2006-01-02 15:04:05"
 
-		origBatch := b.Vault.(*DBTestData).EffectiveBatch
-		if b.TestOpts.(*TestOpts).BenchOpts.Batch == 0 {
-			b.Vault.(*DBTestData).EffectiveBatch = 256
+		driver := getDBDriver(b)
+		window := time.Duration(b.TestOpts.(*TestOpts).TestcaseOpts.TSRollingWindow) * time.Minute
+		bucketSeconds := b.TestOpts.(*TestOpts).TestcaseOpts.TSBucketSeconds
+		if window <= 0 || bucketSeconds <= 0 {
+			b.Exit("--ts-rolling-window and --ts-bucket-seconds must be positive")
 		}
+		bucket := timeBucketExpr(driver, "ts", bucketSeconds)
+
+		colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(driver))
+
+		// latency is reported by the table size classes, the size class is the bit length of the rows count
+		var lock sync.Mutex
+		queries := map[int]uint64{}
+		latency := map[int]time.Duration{}
+		buckets := map[int]uint64{}
+		var inserted uint64
+
+		testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			columns, values := b.GenFakeData(c.WorkerID, colConfs, benchmark.WithAutoInc(driver))
+			series := map[string]interface{}{}
+			for i, column := range columns {
+				series[column] = values[i]
+			}
 
-		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id", "device_id", "metric_id"}, false)
+			if driver == benchmark.CASSANDRA {
+				c.ExecOrExit(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", testDesc.table.TableName, strings.Join(columns, ","),
+					benchmark.GenDBParameterPlaceholdersCassandra(0, len(columns))), values...)
+			} else {
+				c.ExecOrExit(formatSQL(multiValueInsertQuery(testDesc.table.TableName, columns, 1), driver), values...)
+			}
+			size := bits.Len64(testDesc.table.RowsCount + atomic.AddUint64(&inserted, 1))
+
+			end := time.Now().UTC()
+			where := fmt.Sprintf("tenant_id = '%s' AND device_id = '%s' AND metric_id = '%s' AND ts >= '%s' AND ts <= '%s'",
+				series["tenant_id"], series["device_id"], series["metric_id"], end.Add(-window).Format(tsLayout), end.Format(tsLayout))
+
+			start := time.Now()
+			var n uint64
+			if driver == benchmark.CASSANDRA {
+				// no GROUP BY by an expression, the values of the partition are bucketed on the client side
+				res, err := c.Query(fmt.Sprintf("SELECT ts, value FROM %s WHERE %s ALLOW FILTERING", testDesc.table.TableName, where))
+				if err != nil {
+					c.Exit("rolling window query failed: %v", err)
+				}
+				seen := map[int64]bool{}
+				for res.Next() {
+					var ts time.Time
+					var value int
+					if err = res.Scan(&ts, &value); err != nil {
+						c.Exit("can't scan the rolling window row: %v", err)
+					}
+					seen[ts.Unix()/int64(bucketSeconds)] = true
+				}
+				res.Close()
+				n = uint64(len(seen))
+			} else {
+				n = uint64(rowsOrOne(c.SelectRaw(false, fmt.Sprintf("SELECT %s AS bucket, AVG(value) FROM %s WHERE %s GROUP BY %s",
+					bucket, testDesc.table.TableName, where, bucket))))
+			}
+			elapsed := time.Since(start)
 
-		where := func(b *benchmark.Benchmark, workerId int) string {
-			w := b.GenFakeDataAsMap(workerId, colConfs, false)
+			lock.Lock()
+			queries[size]++
+			latency[size] += elapsed
+			buckets[size] += n
+			lock.Unlock()
 
-			return fmt.Sprintf("tenant_id = '%s' AND device_id = '%s' AND metric_id = '%s'", (*w)["tenant_id"], (*w)["device_id"], (*w)["metric_id"])
-		}
-		orderby := func(b *benchmark.Benchmark) string {
-			return "id DESC"
-		}
+			return 1
+		}, 0)
 
-		testSelect(b, testDesc, nil, "id", where, orderby, 1)
+		sizes := make([]int, 0, len(queries))
+		for size := range queries {
+			sizes = append(sizes, size)
+		}
+		sort.Ints(sizes)
 
-		b.Vault.(*DBTestData).EffectiveBatch = origBatch
+		fmt.Fprintf(out(b), "%15s %10s %15s %12s\n", "TABLE ROWS <", "QUERIES", "AVG LATENCY", "AVG BUCKETS")
+		for _, size := range sizes {
+			n := queries[size]
+			fmt.Fprintf(out(b), "%15d %10d %15s %12.1f\n", uint64(1)<<size, n, (latency[size] / time.Duration(n)).Round(time.Microsecond),
+				float64(buckets[size])/float64(n))
+		}
 	},
 }
 
@@ -1630,12 +6464,30 @@ func tenantAwareWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, testDes
 }
 
 func buildTenantAwareQuery(tableName string) string {
-	return fmt.Sprintf("SELECT `%[1]s`.`id` id, `%[1]s`.`tenant_id` FROM `%[1]s` "+
+	return fmt.Sprintf("SELECT `%[1]s`.`id` id, `%[1]s`.`tenant_id` FROM ", tableName) +
+		buildTenantAwareJoin(tableName, "AND (`tenants_parent`.`uuid` IN ('{tenant_uuid}')) ")
+}
+
+// buildTenantAwareJoin returns the FROM clause joining given table with the tenants hierarchy, parentFilter is an extra condition for the parent tenant
+func buildTenantAwareJoin(tableName string, parentFilter string) string {
+	return fmt.Sprintf("`%[1]s` "+
 		"JOIN `%[2]s` AS `tenants_child` ON ((`tenants_child`.`uuid` = `%[1]s`.`tenant_id`) AND (`tenants_child`.`is_deleted` != {true})) "+
 		"JOIN `%[3]s` AS `tenants_closure` ON ((`tenants_closure`.`child_id` = `tenants_child`.`id`) AND (`tenants_closure`.`barrier` <= 0)) "+
 		"JOIN `%[2]s` AS `tenants_parent` ON ((`tenants_parent`.`id` = `tenants_closure`.`parent_id`) "+
-		"AND (`tenants_parent`.`uuid` IN ('{tenant_uuid}')) AND (`tenants_parent`.`is_deleted` != {true}))",
-		tableName, benchmark.TableNameTenants, benchmark.TableNameTenantClosure)
+		"%[4]sAND (`tenants_parent`.`is_deleted` != {true}))",
+		tableName, benchmark.TableNameTenants, benchmark.TableNameTenantClosure, parentFilter)
+}
+
+// tenantAwareDialect adapts the tenant-aware query to the DB driver (boolean values and identifiers quoting)
+func tenantAwareDialect(b *benchmark.Benchmark, query string) string {
+	driver := b.TestOpts.(*TestOpts).DBOpts.Driver
+	query = strings.ReplaceAll(query, "{true}", benchmark.RenderBool(driver, true))
+
+	if driver == benchmark.POSTGRES {
+		return strings.ReplaceAll(query, "`", "\"")
+	}
+
+	return query
 }
 
 func tenantAwareGenericWorker(b *benchmark.Benchmark, c *benchmark.DBConnector, query string, orderBy string) (loops int) {
@@ -1646,14 +6498,6 @@ func tenantAwareGenericWorker(b *benchmark.Benchmark, c *benchmark.DBConnector,
 		b.Exit(err.Error())
 	}
 
-	var valTrue string
-
-	if b.TestOpts.(*TestOpts).DBOpts.Driver == benchmark.POSTGRES {
-		valTrue = "true"
-	} else {
-		valTrue = "1"
-	}
-	query = strings.ReplaceAll(query, "{true}", valTrue)
 	query = strings.ReplaceAll(query, "{tenant_uuid}", string(uuid))
 	if orderBy != "" {
 		query += " " + orderBy
@@ -1662,9 +6506,7 @@ func tenantAwareGenericWorker(b *benchmark.Benchmark, c *benchmark.DBConnector,
 
 	var id, tenantID string
 
-	if b.TestOpts.(*TestOpts).DBOpts.Driver == benchmark.POSTGRES {
-		query = strings.ReplaceAll(query, "`", "\"")
-	}
+	query = tenantAwareDialect(b, query)
 
 	c.Log(benchmark.LogTrace, "executing query: %s", query)
 	c.QueryRowAndScanAllowEmpty(query, &id, &tenantID)
@@ -1716,7 +6558,7 @@ var TestSelectHeavyLastTenant = TestDesc{
 	category:    TestSelect,
 	isReadonly:  true,
 	isDBRTest:   false,
-	databases:   RELATIONAL,
+	databases:   withCockroach(RELATIONAL),
 	table:       TestTableHeavy,
 	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
 		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
@@ -1726,6 +6568,166 @@ var TestSelectHeavyLastTenant = TestDesc{
 	},
 }
 
+// tenantAwareViewName is a name of the view over the tenant-aware join of the 'heavy' table
+const tenantAwareViewName = "acronis_db_bench_heavy_tenant_view"
+
+// TestSelectViewTenant is the same as TestSelectHeavyLastTenant but reads from the view hiding the tenant-aware join
+var TestSelectViewTenant = TestDesc{
+	name:        "select-heavy-last-in-tenant-via-view",
+	metric:      "rows/sec",
+	description: "select the last row from the view over the tenant-aware join of the 'heavy' table, compare with 'select-heavy-last-in-tenant'",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES, benchmark.MYSQL, benchmark.SQLITE},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		tableName := testDesc.table.TableName
+
+		c := dbConnector(b)
+		c.ExecOrExit(tenantAwareDialect(b, fmt.Sprintf("DROP VIEW IF EXISTS `%s`", tenantAwareViewName)))
+		c.ExecOrExit(tenantAwareDialect(b, fmt.Sprintf("CREATE VIEW `%[1]s` AS SELECT `%[2]s`.`id` id, `%[2]s`.`tenant_id` tenant_id, "+
+			"`%[2]s`.`enqueue_time_ns` enqueue_time_ns, `tenants_parent`.`uuid` parent_uuid FROM %[3]s",
+			tenantAwareViewName, tableName, buildTenantAwareJoin(tableName, ""))))
+		c.Release()
+
+		worker := func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+			uuid, err := b.TenantsCache.GetRandomTenantUUID(b.Randomizer.GetWorker(c.WorkerID), 0)
+			if err != nil {
+				b.Exit(err.Error())
+			}
+
+			query := tenantAwareDialect(b, fmt.Sprintf("SELECT `id`, `tenant_id` FROM `%s` WHERE `parent_uuid` = '%s' ORDER BY `enqueue_time_ns` DESC LIMIT 1",
+				tenantAwareViewName, uuid))
+
+			var id, tenantID string
+			c.QueryRowAndScanAllowEmpty(query, &id, &tenantID)
+
+			return 1
+		}
+		testGeneric(b, testDesc, worker, 1)
+
+		c = dbConnector(b)
+		c.ExecOrExit(tenantAwareDialect(b, fmt.Sprintf("DROP VIEW IF EXISTS `%s`", tenantAwareViewName)))
+		c.Release()
+	},
+}
+
+// heavyTenantMatView is the materialized view of the 'heavy' table per tenant aggregate
+const heavyTenantMatView = "acronis_db_bench_heavy_tenant_agg"
+
+// heavyTenantAggregateQuery returns the per tenant aggregate of the 'heavy' table, it's the materialized view definition
+func heavyTenantAggregateQuery(tableName string, where string) string {
+	return fmt.Sprintf("SELECT tenant_id, COUNT(*) AS jobs, MAX(completion_time_ns) AS last_completion_ns, AVG(progress) AS avg_progress FROM %s%s GROUP BY tenant_id",
+		tableName, where)
+}
+
+// createHeavyTenantMatView (re)creates the materialized view with the unique index REFRESH MATERIALIZED VIEW CONCURRENTLY requires
+func createHeavyTenantMatView(b *benchmark.Benchmark, tableName string) {
+	c := dbConnector(b)
+	defer c.Release()
+
+	if !c.TableExists(tableName) {
+		b.Exit("The '%s' table doesn't exist, please create tables using -I option, or use individual insert test using the -t `insert-***`", tableName)
+	}
+
+	c.ExecOrExit("DROP MATERIALIZED VIEW IF EXISTS " + heavyTenantMatView)
+	c.ExecOrExit(fmt.Sprintf("CREATE MATERIALIZED VIEW %s AS %s", heavyTenantMatView, heavyTenantAggregateQuery(tableName, "")))
+	c.ExecOrExit(fmt.Sprintf("CREATE UNIQUE INDEX %[1]s_tenant_id ON %[1]s (tenant_id)", heavyTenantMatView))
+}
+
+// dropHeavyTenantMatView drops the materialized view, it's called on the premature exit as well
+func dropHeavyTenantMatView(b *benchmark.Benchmark) {
+	c := scratchConnector(b)
+	defer c.Close()
+
+	c.ExecOrExit("DROP MATERIALIZED VIEW IF EXISTS " + heavyTenantMatView)
+}
+
+// TestRefreshMatView refreshes the materialized view of the 'heavy' table per tenant aggregate with and without CONCURRENTLY
+var TestRefreshMatView = TestDesc{
+	name:        "refresh-matview",
+	metric:      "refreshes/sec",
+	description: "refresh the materialized view of the 'heavy' table per tenant aggregate by REFRESH MATERIALIZED VIEW and then REFRESH MATERIALIZED VIEW CONCURRENTLY, report seconds per refresh",
+	category:    TestOther,
+	isReadonly:  false,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		createHeavyTenantMatView(b, testDesc.table.TableName)
+		defer cleanupOnExit(b, func() { dropHeavyTenantMatView(b) })()
+
+		// refreshes of the same view are serialized by its lock, so concurrent workers would just wait for each other
+		origWorkers := b.CommonOpts.Workers
+		b.CommonOpts.Workers = 1
+
+		queries := []string{
+			"REFRESH MATERIALIZED VIEW " + heavyTenantMatView,
+			"REFRESH MATERIALIZED VIEW CONCURRENTLY " + heavyTenantMatView,
+		}
+
+		testModes(b, "MODE", []string{"REFRESH", "REFRESH CONCURRENTLY"}, []string{"REFRESHES", "SEC/REFRESH"}, func(i int) []string {
+			query := queries[i]
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				c.ExecOrExit(query)
+
+				return 1
+			}, 1)
+
+			perRefresh := 0.0
+			if b.Score.Loops > 0 {
+				perRefresh = b.Score.Seconds / float64(b.Score.Loops)
+			}
+
+			return []string{strconv.FormatUint(b.Score.Loops, 10), fmt.Sprintf("%.3f", perRefresh)}
+		})
+
+		b.CommonOpts.Workers = origWorkers
+	},
+}
+
+// TestSelectMatView selects the tenant aggregate from the materialized view and then computes it live from the 'heavy' table
+var TestSelectMatView = TestDesc{
+	name:        "select-matview-in-tenant",
+	metric:      "rows/sec",
+	description: "select the tenant aggregate from the materialized view over the 'heavy' table and then compute it live by GROUP BY, see 'refresh-matview' for the refresh cost",
+	category:    TestSelect,
+	isReadonly:  true,
+	isDBRTest:   false,
+	databases:   []string{benchmark.POSTGRES},
+	table:       TestTableHeavy,
+	launcherFunc: func(b *benchmark.Benchmark, testDesc *TestDesc) {
+		tableName := testDesc.table.TableName
+		explain := b.TestOpts.(*TestOpts).BenchOpts.Explain
+
+		createHeavyTenantMatView(b, tableName)
+		defer cleanupOnExit(b, func() { dropHeavyTenantMatView(b) })()
+
+		colConfs := testDesc.table.GetColumnsConf([]string{"tenant_id"}, false)
+
+		queries := []string{
+			"SELECT jobs, last_completion_ns, avg_progress FROM " + heavyTenantMatView + " WHERE tenant_id = '%s'",
+			heavyTenantAggregateQuery(tableName, " WHERE tenant_id = '%s'"),
+		}
+
+		rates := testModes(b, "SOURCE", []string{"materialized view", "live aggregate"}, nil, func(i int) []string {
+			query := queries[i]
+			testGeneric(b, testDesc, func(b *benchmark.Benchmark, c *benchmark.DBConnector, testDesc *TestDesc, batch int) (loops int) {
+				w := b.GenFakeDataAsMap(c.WorkerID, colConfs, false)
+
+				return rowsOrOne(c.SelectRaw(explain, fmt.Sprintf(query, (*w)["tenant_id"])))
+			}, 1)
+
+			return nil
+		})
+
+		if len(rates) == 2 && rates[1] > 0 {
+			fmt.Fprintf(out(b), "materialized view is %.1fx the live aggregate rate\n", rates[0]/rates[1])
+		}
+	},
+}
+
 // TestSelectHeavyLastTenantCTI is the same as TestSelectHeavyLastTenant but with CTI-awareness
 var TestSelectHeavyLastTenantCTI = TestDesc{
 	name:        "select-heavy-last-in-tenant-and-cti",
@@ -1765,22 +6767,47 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestInsertLight)
 	tg.add(&TestInsertLightPrepared)
 	tg.add(&TestInsertLightMultiValue)
+	tg.add(&TestInsertLightLWT)
+	tg.add(&TestInsertKeyOrder)
+	tg.add(&TestInsertUUIDPK)
+	tg.add(&TestInsertMaxIDPlusOne)
+	tg.add(&TestSelectByUUIDRange)
+	tg.add(&TestUUIDPKVersions)
 	tg.add(&TestCopyLight)
 	tg.add(&TestInsertMedium)
 	tg.add(&TestInsertMediumPrepared)
 	tg.add(&TestInsertMediumMultiValue)
 	tg.add(&TestCopyMedium)
+	tg.add(&TestUpsertMedium)
 	tg.add(&TestInsertHeavy)
 	tg.add(&TestInsertHeavyPrepared)
 	tg.add(&TestInsertHeavyMultivalue)
 	tg.add(&TestCopyHeavy)
 	tg.add(&TestUpdateMedium)
 	tg.add(&TestUpdateHeavy)
+	tg.add(&TestUpdateLightLWT)
+	tg.add(&TestReadAfterWriteConsistency)
 	tg.add(&TestSelectOne)
+	tg.add(&TestSelectGenerateSeries)
+	tg.add(&TestCancelPropagation)
 	tg.add(&TestSelectMediumLast)
 	tg.add(&TestSelectMediumRand)
+	tg.add(&TestSelectMediumRandPgProtocols)
+	tg.add(&TestSelectMediumNamedPrepared)
 	tg.add(&TestSelectHeavyLast)
 	tg.add(&TestSelectHeavyRand)
+	tg.add(&TestSelectHeavyFetchFirst)
+	tg.add(&TestSelectUnionAllFeed)
+	tg.add(&TestCursorReuse)
+	tg.add(&TestPreparedStatementFootprint)
+	tg.add(&TestPreparedPoolContention)
+	tg.add(&TestSelectPoolerFeatures)
+	tg.add(&TestSelectCrossCatalog)
+	tg.add(&TestSelectHeavyRandAllColumns)
+	tg.add(&TestSelectHeavyRandInList)
+	tg.add(&TestSelectHeavyInListSweep)
+	tg.add(&TestSelectHeavyAnyArray)
+	tg.add(&TestSelectByNaturalKey)
 	tg.add(&TestSelectHeavyMinMaxTenant)
 	tg.add(&TestSelectHeavyMinMaxTenantAndState)
 	tg.add(&TestBaseAll)
@@ -1791,23 +6818,65 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestSelectNextVal)
 	tg.add(&TestPing)
 	tg.add(&TestSelectHeavyForUpdateSkipLocked)
+	tg.add(&TestSelectHeavyForUpdateNowait)
+	tg.add(&TestQueueConsume)
+	tg.add(&TestBulkLoadDropRebuildIndex)
+	tg.add(&TestBulkInsertReturningIds)
+	tg.add(&TestCopyHeavyPartitioned)
+	tg.add(&TestInsertHeavyManyTenants)
+	tg.add(&TestInsertHeavyDecoupledGen)
+	tg.add(&TestInsertHeavyCheckConstraint)
+	tg.add(&TestSelectHeavyRollup)
+	tg.add(&TestSelectHeavyFilteredAgg)
+	tg.add(&TestSelectHeavyLateral)
+	tg.add(&TestSelectHeavyTwoPredicates)
+	tg.add(&TestSelectHeavyExists)
+	tg.add(&TestSelectHeavyWideRow)
+	tg.add(&TestInsertMoney)
+	tg.add(&TestInsertCheckThenInsert)
+	tg.add(&TestUpsertTimeSeriesLatest)
+	tg.add(&TestUpsertCounter)
+	tg.add(&TestTransactionChattyVsBatched)
+	tg.add(&TestTenantIsolation)
+	tg.add(&TestSelectMoneySumByTenant)
+	tg.add(&TestSelectMoneyFloatSumByTenant)
+	tg.add(&TestSelectCacheHitRate)
 	tg.add(&TestInsertJSON)
 	tg.add(&TestSelectJSONByIndexedValue)
+	tg.add(&TestInsertJSONTags)
+	tg.add(&TestSelectJSONArrayContains)
 	tg.add(&TestSearchJSONByIndexedValue)
+	tg.add(&TestSelectJSONDeserialize)
 	tg.add(&TestSelectJSONByNonIndexedValue)
 	tg.add(&TestSearchJSONByNonIndexedValue)
 	tg.add(&TestUpdateHeavySameVal)
 	tg.add(&TestUpdateHeavyPartialSameVal)
 	tg.add(&TestUpdateHeavyBulk)
 	tg.add(&TestUpdateHeavyBulkDBR)
+	tg.add(&TestUpdateHeavyViaTempJoin)
+	tg.add(&TestUpdateThenVacuumCost)
+	tg.add(&TestUpdateHeavyFromAggregate)
+	tg.add(&TestUpdateSingleHotRow)
+	tg.add(&TestOnlineDDLUnderLoad)
+	tg.add(&TestCreateTableAsSelect)
+
+	tg = NewTestGroup("Delete tests")
+	g = append(g, tg)
+
+	tg.add(&TestDeleteHeavy)
+	tg.add(&TestDeleteHeavyBulk)
 
 	tg = NewTestGroup("Tenant-aware tests")
 	g = append(g, tg)
 
 	tg.add(&TestSelectMediumLastTenant)
 	tg.add(&TestSelectHeavyLastTenant)
+	tg.add(&TestSelectViewTenant)
+	tg.add(&TestSelectMatView)
+	tg.add(&TestRefreshMatView)
 	tg.add(&TestSelectHeavyLastTenantCTI)
 	tg.add(&TestSelectHeavyRandTenantLike)
+	tg.add(&TestSelectHeavyCount)
 
 	tg = NewTestGroup("Blob tests")
 	g = append(g, tg)
@@ -1815,6 +6884,7 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	tg.add(&TestInsertBlob)
 	tg.add(&TestCopyBlob)
 	tg.add(&TestInsertLargeObj)
+	tg.add(&TestInsertGrowingPayload)
 	tg.add(&TestSelectBlobLastTenant)
 
 	tg = NewTestGroup("Timeseries tests")
@@ -1822,6 +6892,8 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 
 	tg.add(&TestInsertTimeSeriesSQL)
 	tg.add(&TestSelectTimeSeriesSQL)
+	tg.add(&TestSelectTimeSeriesRecencySkewed)
+	tg.add(&TestSelectTimeSeriesRollingWindow)
 
 	tg = NewTestGroup("Golang DBR query builder tests")
 	g = append(g, tg)
@@ -1863,6 +6935,59 @@ func GetTests() ([]*TestGroup, map[string]*TestDesc) {
 	return g, ret
 }
 
+// tableSizes is a size of a table data and its indexes, see --report-sizes
+type tableSizes struct {
+	Table      string           `json:"table"`
+	Rows       uint64           `json:"rows"`
+	DataBytes  int64            `json:"data_bytes"`
+	IndexBytes map[string]int64 `json:"index_bytes"`
+}
+
+// sizesMilestone is a set of table sizes measured after given amount of rows loaded by the 'all' test
+type sizesMilestone struct {
+	Milestone int          `json:"milestone"`
+	Tables    []tableSizes `json:"tables"`
+}
+
+// reportSizes prints size of the tables loaded by the 'all' test and every their index
+func reportSizes(b *benchmark.Benchmark, milestone int) sizesMilestone {
+	c := dbConnector(b)
+	defer c.Release()
+
+	ret := sizesMilestone{Milestone: milestone}
+
+	fmt.Fprintf(out(b), "--------------------------------------------------------------------\n")
+	fmt.Fprintf(out(b), "sizes after %d rows milestone:\n", milestone)
+
+	for _, t := range []TestTable{TestTableLight, TestTableMedium, TestTableHeavy, TestTableJSON, TestTableTimeSeriesSQL} {
+		sizes := tableSizes{Table: t.TableName, Rows: c.GetRowsCount(t.TableName, "")}
+		sizes.DataBytes, sizes.IndexBytes = c.GetTableAndIndexSizes(t.TableName)
+
+		var indexesTotal int64
+		names := make([]string, 0, len(sizes.IndexBytes))
+		for name, size := range sizes.IndexBytes {
+			indexesTotal += size
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		ratio := 0.0
+		if sizes.DataBytes > 0 {
+			ratio = float64(indexesTotal) / float64(sizes.DataBytes)
+		}
+
+		fmt.Fprintf(out(b), "  %-40s rows: %10d; data: %12d bytes; indexes: %12d bytes; index/data: %.2f\n",
+			t.TableName, sizes.Rows, sizes.DataBytes, indexesTotal, ratio)
+		for _, name := range names {
+			fmt.Fprintf(out(b), "    %-50s %12d bytes\n", name, sizes.IndexBytes[name])
+		}
+
+		ret.Tables = append(ret.Tables, sizes)
+	}
+
+	return ret
+}
+
 func executeAllTests(b *benchmark.Benchmark, testOpts *TestOpts) {
 	if testOpts.BenchOpts.Chunk > testOpts.BenchOpts.Limit {
 		b.Exit("--chunk option must not be less then --limit")
@@ -1880,24 +7005,278 @@ func executeAllTests(b *benchmark.Benchmark, testOpts *TestOpts) {
 		workers = 16
 	}
 
+	var milestones []sizesMilestone
+
 	for i := 0; i < testOpts.BenchOpts.Limit; i += testOpts.BenchOpts.Chunk {
 		executeAllTestsOnce(b, testOpts, workers)
+
+		if testOpts.BenchOpts.ReportSizes {
+			milestones = append(milestones, reportSizes(b, i+testOpts.BenchOpts.Chunk))
+		}
+	}
+
+	if testOpts.BenchOpts.ReportSizes {
+		sizes, err := json.MarshalIndent(milestones, "", "  ")
+		if err != nil {
+			b.Exit("can't marshal table sizes: %v", err)
+		}
+		fmt.Fprintf(out(b), "--------------------------------------------------------------------\n")
+		fmt.Fprintf(out(b), "sizes (JSON):\n%s\n", sizes)
 	}
 
 	testData := b.Vault.(*DBTestData)
 
-	fmt.Printf("--------------------------------------------------------------------\n")
+	fmt.Fprintf(out(b), "--------------------------------------------------------------------\n")
 
 	scores := []string{TestSelect, TestInsert, TestUpdate}
+	geomeans := make(map[string]float64, len(scores))
 	for _, s := range scores {
-		fmt.Printf("%s geomean: %.0f\n", s, b.Geomean(testData.scores[s]))
+		geomeans[s] = b.Geomean(testData.scores[s])
+		if testData.Results == nil {
+			fmt.Fprintf(out(b), "%s geomean: %.0f\n", s, geomeans[s])
+		}
 	}
 
+	normalizeGeomeans(b, scores, geomeans)
+
 	cleanupTables(b)
 }
 
+// normalizeGeomeans stores the current DB driver category geomeans into the --geomeans-file and prints
+// the geomeans of every driver found there relative to the --baseline-dialect one
+func normalizeGeomeans(b *benchmark.Benchmark, categories []string, geomeans map[string]float64) {
+	testOpts := b.TestOpts.(*TestOpts)
+	driver := testOpts.DBOpts.Driver
+
+	// driver -> category -> geomean
+	all := map[string]map[string]float64{}
+
+	if path := testOpts.BenchOpts.GeomeansFile; path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if err = json.Unmarshal(data, &all); err != nil {
+				b.Exit("can't parse geomeans file '%s': %v", path, err)
+			}
+		} else if !os.IsNotExist(err) {
+			b.Exit("can't read geomeans file: %v", err)
+		}
+
+		all[driver] = geomeans
+
+		out, err := json.MarshalIndent(all, "", "  ")
+		if err != nil {
+			b.Exit("can't marshal geomeans: %v", err)
+		}
+		if err = os.WriteFile(path, out, 0o644); err != nil {
+			b.Exit("can't write geomeans file: %v", err)
+		}
+	} else {
+		all[driver] = geomeans
+	}
+
+	baseline := testOpts.BenchOpts.BaselineDialect
+	if baseline == "" {
+		return
+	}
+
+	base, ok := all[baseline]
+	if !ok {
+		b.Exit("no geomeans of the baseline dialect '%s' found, run the 'all' test against it with the same --geomeans-file first", baseline)
+	}
+
+	drivers := make([]string, 0, len(all))
+	for d := range all {
+		drivers = append(drivers, d)
+	}
+	sort.Strings(drivers)
+
+	fmt.Fprintf(out(b), "--------------------------------------------------------------------\n")
+	fmt.Fprintf(out(b), "geomeans relative to %s:\n", baseline)
+	fmt.Fprintf(out(b), "  %-12s", "driver")
+	for _, c := range categories {
+		fmt.Fprintf(out(b), " %10s", c)
+	}
+	fmt.Fprintf(out(b), "\n")
+
+	for _, d := range drivers {
+		fmt.Fprintf(out(b), "  %-12s", d)
+		for _, c := range categories {
+			if base[c] <= 0 {
+				fmt.Fprintf(out(b), " %10s", "n/a")
+
+				continue
+			}
+			fmt.Fprintf(out(b), " %10.2f", all[d][c]/base[c])
+		}
+		fmt.Fprintf(out(b), "\n")
+	}
+}
+
 func executeOneTest(b *benchmark.Benchmark, testDesc *TestDesc) {
-	testDesc.launcherFunc(b, testDesc)
+	if dialect := b.TestOpts.(*TestOpts).DBOpts.Dialect; dialect == benchmark.COCKROACH && !testDesc.dbIsSupported(dialect) {
+		// the tests not verified on CockroachDB yet, see withCockroach(), are skipped when run as a part of a group
+		fmt.Fprintf(out(b), "test: %s; SKIPPED: not verified on CockroachDB\n", testDesc.name)
+
+		return
+	}
+
+	if dbOpts := b.TestOpts.(*TestOpts).DBOpts; !testDesc.isReadonly && !dbOpts.SupportsIsolation() {
+		fmt.Fprintf(out(b), "test: %s; SKIPPED: --isolation=%s is not supported by the '%s' database\n", testDesc.name, dbOpts.Isolation, dbOpts.DialectName())
+
+		return
+	}
+
+	launcher, emulation := testLauncher(b, testDesc)
+	if launcher == nil {
+		fmt.Fprintf(out(b), "test: %s; SKIPPED: %s\n", testDesc.name, emulation)
+
+		return
+	}
+	if emulation != "" {
+		fmt.Fprintf(out(b), "test: %s; EMULATED: %s\n", testDesc.name, emulation)
+	}
+
+	b.Vault.(*DBTestData).Emulation = emulation
+	defer func() {
+		b.Vault.(*DBTestData).Emulation = ""
+		b.Vault.(*DBTestData).counters = nil
+	}()
+
+	if sweep := b.TestOpts.(*TestOpts).BenchOpts.BatchSweep; sweep != "" {
+		executeBatchSweep(b, testDesc, launcher, sweep)
+
+		return
+	}
+
+	if b.TestOpts.(*TestOpts).DBOpts.ConnPerWorker {
+		executeConnPerWorker(b, testDesc, launcher)
+
+		return
+	}
+
+	repeat := b.TestOpts.(*TestOpts).BenchOpts.RepeatTest
+	if repeat <= 1 {
+		launcher(b, testDesc)
+
+		return
+	}
+
+	b.CollectLatencies = true
+
+	rates := make([]float64, 0, repeat)
+	p99s := make([]float64, 0, repeat)
+
+	for r := 0; r < repeat && !b.NeedToExit; r++ {
+		launcher(b, testDesc)
+		rates = append(rates, b.Score.Rate)
+		p99s = append(p99s, float64(b.Score.P99))
+	}
+
+	b.CollectLatencies = false
+
+	var rateSum, p99Sum float64
+	for i := range rates {
+		rateSum += rates[i]
+		p99Sum += p99s[i]
+	}
+
+	rateCV := benchmark.CoefficientOfVariation(rates)
+	p99CV := benchmark.CoefficientOfVariation(p99s)
+
+	stability := "stable"
+	if threshold := b.TestOpts.(*TestOpts).BenchOpts.UnstableCV; rateCV > threshold || p99CV > threshold {
+		stability = fmt.Sprintf("UNSTABLE (CV > %.1f%%)", threshold)
+	}
+
+	name := testDesc.name
+	if emulation != "" {
+		name += " (EMULATED)"
+	}
+
+	fmt.Fprintf(out(b), "test: %s; runs: %d; avg rate: %.1f %s; rate CV: %.1f%%; avg p99: %.3f ms; p99 CV: %.1f%%; %s\n",
+		name, len(rates), rateSum/float64(len(rates)), testDesc.metric, rateCV,
+		p99Sum/float64(len(p99s))/float64(time.Millisecond), p99CV, stability)
+}
+
+// executeConnPerWorker runs the test with the connections pool of every worker and then with single pinned connection
+// per worker, and reports the throughput difference, see --conn-per-worker
+func executeConnPerWorker(b *benchmark.Benchmark, testDesc *TestDesc, launcher func(b *benchmark.Benchmark, testDesc *TestDesc)) {
+	dbOpts := &b.TestOpts.(*TestOpts).DBOpts
+	defer func() { dbOpts.ConnPerWorker = true }()
+
+	var rates [2]float64
+	for i, pinned := range []bool{false, true} {
+		dbOpts.ConnPerWorker = pinned
+		launcher(b, testDesc)
+		if b.NeedToExit {
+			return
+		}
+		rates[i] = b.Score.Rate
+	}
+
+	diff := 0.0
+	if rates[0] > 0 {
+		diff = (rates[1] - rates[0]) * 100 / rates[0]
+	}
+
+	fmt.Fprintf(out(b), "test: %s; pooled rate: %.1f %s; pinned rate: %.1f %s; pinned vs pooled: %+.1f%%\n",
+		testDesc.name, rates[0], testDesc.metric, rates[1], testDesc.metric, diff)
+}
+
+// parseBatchSweep parses the --batch-sweep option and returns the batch sizes in ascending order
+func parseBatchSweep(b *benchmark.Benchmark, batchSweep string) []int {
+	var batches []int
+
+	for _, s := range strings.Split(batchSweep, ",") {
+		batch, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || batch <= 0 {
+			b.Exit("invalid batch size '%s' in --batch-sweep=%s, positive integers are expected", s, batchSweep)
+		}
+		batches = append(batches, batch)
+	}
+	sort.Ints(batches)
+
+	return batches
+}
+
+// executeBatchSweep runs the insert test once per --batch-sweep batch size and reports the rate of every batch size
+func executeBatchSweep(b *benchmark.Benchmark, testDesc *TestDesc, launcher func(b *benchmark.Benchmark, testDesc *TestDesc), batchSweep string) {
+	if testDesc.category != TestInsert {
+		fmt.Fprintf(out(b), "test: %s; SKIPPED: --batch-sweep is supported by the insert tests only\n", testDesc.name)
+
+		return
+	}
+
+	batches := parseBatchSweep(b, batchSweep)
+	modes := make([]string, len(batches))
+	for i, batch := range batches {
+		modes[i] = strconv.Itoa(batch)
+	}
+
+	// the explicit --batch makes the tests with their own default batch take the swept one
+	benchOpts := &b.TestOpts.(*TestOpts).BenchOpts
+	defer func(batch int) { benchOpts.Batch = batch }(benchOpts.Batch)
+
+	rates := testModes(b, "BATCH", modes, nil, func(i int) []string {
+		benchOpts.Batch = batches[i]
+		defer setBatch(b, batches[i])()
+
+		launcher(b, testDesc)
+
+		return nil
+	})
+	if len(rates) == 0 {
+		return
+	}
+
+	best := 0
+	for i := range rates {
+		if rates[i] > rates[best] {
+			best = i
+		}
+	}
+
+	fmt.Fprintf(out(b), "test: %s; best batch: %d; rate: %.1f %s\n", testDesc.name, batches[best], rates[best], testDesc.metric)
 }
 
 func executeAllTestsOnce(b *benchmark.Benchmark, testOpts *TestOpts, workers int) {
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -16,7 +17,13 @@ import (
 func initWorker(b *benchmark.Benchmark, workerID int, testDesc *TestDesc, rowsRequired uint64) {
 	if b.WorkerData[workerID] == nil {
 		var workerData DBWorkerData
-		workerData.conn = benchmark.NewDBConnector(&b.TestOpts.(*TestOpts).DBOpts, workerID, b.Logger, 10)
+		dbOpts := &b.TestOpts.(*TestOpts).DBOpts
+		workerData.replica = -1
+		if replicas := b.Vault.(*DBTestData).ReadReplicas; len(replicas) > 0 && testDesc.isReadonly {
+			workerData.replica = workerID % len(replicas)
+			dbOpts = replicas[workerData.replica]
+		}
+		workerData.conn = benchmark.NewDBConnector(dbOpts, workerID, b.Logger, 10)
 		b.WorkerData[workerID] = &workerData
 		if testDesc.isDBRTest {
 			workerData.conn.DBRConnect()
@@ -25,6 +32,12 @@ func initWorker(b *benchmark.Benchmark, workerID int, testDesc *TestDesc, rowsRe
 		}
 	}
 
+	if testDesc.table.isTemporary(b) && !testDesc.isReadonly {
+		// temporary table is visible only within the session, so every worker needs its own one
+		t := TestTables[testDesc.table.TableName]
+		t.Create(b.WorkerData[workerID].(*DBWorkerData).conn, b)
+	}
+
 	if workerID == 0 {
 		conn := b.WorkerData[0].(*DBWorkerData).conn
 		testData := b.Vault.(*DBTestData)
@@ -34,7 +47,7 @@ func initWorker(b *benchmark.Benchmark, workerID int, testDesc *TestDesc, rowsRe
 
 		t := TestTables[tableName]
 
-		if tableName == "" {
+		if tableName == "" || (testDesc.table.isTemporary(b) && !testDesc.isReadonly) {
 			testDesc.table.RowsCount = 0
 		} else {
 			b.Log(benchmark.LogTrace, workerID, fmt.Sprintf("initializing table '%s'", tableName))
@@ -63,13 +76,27 @@ func initWorker(b *benchmark.Benchmark, workerID int, testDesc *TestDesc, rowsRe
 	}
 	b.Log(benchmark.LogTrace, workerID, "worker is initialized")
 	b.WorkerData[workerID].(*DBWorkerData).conn.SetLogLevel(benchmark.LogInfo)
+	b.WorkerData[workerID].(*DBWorkerData).conn.TakeAcquireWaits() // don't count the initialization queries
 }
 
 func initCommon(b *benchmark.Benchmark, testDesc *TestDesc, rowsRequired uint64) {
+	b.TestName = testDesc.name
+	benchmark.CaptureExplain(b.TestOpts.(*TestOpts).BenchOpts.ExplainOutput, testDesc.name)
+
 	b.InitPerWorker = func(workerId int) {
 		initWorker(b, workerId, testDesc, rowsRequired)
 	}
 
+	b.PostWarmup = func() {
+		if b.TestOpts.(*TestOpts).BenchOpts.ReportWAL {
+			// all the DDL of the initialization and the warmup are done, so the WAL is generated by the measured loops only
+			markWALStart(b)
+		}
+		for _, counters := range b.Vault.(*DBTestData).counters {
+			counters.reset()
+		}
+	}
+
 	b.Metric = func() (metric string) {
 		return testDesc.metric
 	}
@@ -81,6 +108,45 @@ func initCommon(b *benchmark.Benchmark, testDesc *TestDesc, rowsRequired uint64)
 	}
 }
 
+// insertKeyOrderIDTypes maps --insert-key-order values to the fake types of the 'id' the client generates instead of the
+// database for the tables with autoincremented primary key, the sequential order keeps the 'id' generated by the database
+var insertKeyOrderIDTypes = map[string]string{
+	"random": "bigint", // random int, causes index page splits and fragmentation like UUID v4
+	"uuid":   "now_ns", // nanoseconds int, mostly appended to the end of the index like UUID v7
+}
+
+// withInsertKeyOrder adds the 'id' generated in the --insert-key-order to the insert columns of the table with
+// autoincremented primary key
+func withInsertKeyOrder(b *benchmark.Benchmark, testDesc *TestDesc, colConfs *[]benchmark.DBFakeColumnConf) *[]benchmark.DBFakeColumnConf {
+	keyOrder := b.TestOpts.(*TestOpts).TestcaseOpts.InsertKeyOrder
+	idType, ok := insertKeyOrderIDTypes[keyOrder]
+	if !ok || testDesc.isDBRTest {
+		return colConfs
+	}
+
+	autoIncID := false
+	for _, c := range testDesc.table.ColumnsConf {
+		autoIncID = autoIncID || (c.ColumnName == "id" && c.ColumnType == "autoinc")
+	}
+	if !autoIncID {
+		return colConfs
+	}
+
+	switch getDBDriver(b) {
+	case benchmark.POSTGRES, benchmark.MYSQL, benchmark.SQLITE:
+	case benchmark.MSSQL:
+		// IDENTITY columns reject explicit values unless IDENTITY_INSERT is on, and it can be on for one table per session only
+		b.Exit("--insert-key-order=%s is not supported by mssql for the tables with autoincremented primary key", keyOrder)
+	default:
+		// the 'id' is generated by the client already
+		return colConfs
+	}
+
+	withID := append([]benchmark.DBFakeColumnConf{{ColumnName: "id", ColumnType: idType}}, *colConfs...)
+
+	return &withID
+}
+
 /*
  * SELECT workers
  */
@@ -167,7 +233,7 @@ func getDBDriver(b *benchmark.Benchmark) string {
 }
 
 func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
-	colConfs := testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(getDBDriver(b)))
+	colConfs := withInsertKeyOrder(b, testDesc, testDesc.table.GetColumnsForInsert(benchmark.WithAutoInc(getDBDriver(b))))
 
 	if len(*colConfs) == 0 {
 		b.Exit(fmt.Sprintf("internal error: no columns eligible for INSERT found in '%s' configuration", testDesc.table.TableName))
@@ -183,6 +249,23 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 		b.Exit("db type conversion error")
 	}
 
+	// clickhouse doesn't support autoincremented ID and DBR doesn't insert it either
+	withAutoInc := benchmark.WithAutoInc(getDBDriver(b)) && getDBDriver(b) != benchmark.CLICKHOUSE && !testDesc.isDBRTest
+
+	nextRow := func(workerId int) ([]string, []interface{}) {
+		return b.GenFakeData(workerId, colConfs, withAutoInc)
+	}
+
+	if testOpts.TestcaseOpts.DecoupleGen {
+		if testOpts.BenchOpts.Events {
+			b.Exit("--decouple-gen can't be combined with --events")
+		}
+
+		var stopGen func()
+		nextRow, stopGen = startFakeDataProducers(b, colConfs, withAutoInc, 2*batch*b.CommonOpts.Workers)
+		defer stopGen()
+	}
+
 	if b.TestOpts.(*TestOpts).DBOpts.Driver == benchmark.CLICKHOUSE {
 		sql := fmt.Sprintf("INSERT INTO %s", table.TableName)
 		b.Worker = func(workerId int) (loops int) {
@@ -198,7 +281,7 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 
 			for i := 0; i < batch; i++ {
 				// clickhouse doesn't support autoincremented ID, so need to maintain it here
-				_, values := b.GenFakeData(workerId, colConfs, false)
+				_, values := nextRow(workerId)
 				atomic.AddUint64(&rows, 1)
 				args := append([]interface{}{rows}, values...)
 
@@ -243,7 +326,7 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 			defer tx.RollbackUnlessCommitted() // Rollback in case of error
 
 			for i := 0; i < batch; i++ {
-				columns, values := b.GenFakeData(workerId, colConfs, false)
+				columns, values := nextRow(workerId)
 				_, err := tx.InsertInto(table.TableName).Columns(columns...).Values(values...).Exec()
 				if err != nil {
 					b.Exit("aborting")
@@ -272,24 +355,31 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 			var sql string
 
 			c := workerData.conn
-			c.Begin()
 
-			for i := 0; i < batch; i++ {
-				columns, values := b.GenFakeData(workerId, colConfs, benchmark.WithAutoInc(getDBDriver(b)))
+			err := c.Transact(func() error {
+				for i := 0; i < batch; i++ {
+					columns, values := nextRow(workerId)
 
-				if i == 0 {
-					sqlTemplate := fmt.Sprintf(insertSQL, table.TableName, strings.Join(columns, ","), parametersPlaceholder)
-					sql = formatSQL(sqlTemplate, testOpts.DBOpts.Driver)
-				}
+					if i == 0 {
+						sqlTemplate := fmt.Sprintf(insertSQL, table.TableName, strings.Join(columns, ","), parametersPlaceholder)
+						sql = formatSQL(sqlTemplate, testOpts.DBOpts.Driver)
+					}
 
-				c.ExecOrExit(sql, values...)
+					if _, err := c.Exec(sql, values...); err != nil {
+						return err
+					}
 
-				if b.TestOpts.(*TestOpts).BenchOpts.Events {
-					rw := b.Randomizer.GetWorker(workerId)
-					b.Vault.(*DBTestData).EventBus.InsertEvent(rw, c, rw.UUID())
+					if b.TestOpts.(*TestOpts).BenchOpts.Events {
+						rw := b.Randomizer.GetWorker(workerId)
+						b.Vault.(*DBTestData).EventBus.InsertEvent(rw, c, rw.UUID())
+					}
 				}
+
+				return nil
+			})
+			if err != nil {
+				c.Exit(err.Error())
 			}
-			c.Commit()
 
 			return batch
 		}
@@ -300,6 +390,60 @@ func testInsertGeneric(b *benchmark.Benchmark, testDesc *TestDesc) {
 	b.Vault.(*DBTestData).scores[testDesc.category] = append(b.Vault.(*DBTestData).scores[testDesc.category], b.Score)
 }
 
+// fakeRow is a row generated by the --decouple-gen producers
+type fakeRow struct {
+	columns []string
+	values  []interface{}
+}
+
+// startFakeDataProducers runs a GenFakeData producer per worker feeding a buffered channel of given size, so the data
+// generation is taken off the INSERT workers critical path, see --decouple-gen. Producers block while the channel is
+// full. The producers start on the first row request, when the benchmark randomizer and tenants cache are initialized,
+// and use the randomizers of the workers, so the workers must not generate anything themselves.
+// Returned next takes a generated row, stop terminates the producers and waits for them
+func startFakeDataProducers(b *benchmark.Benchmark, colConfs *[]benchmark.DBFakeColumnConf, withAutoInc bool, buffer int) (
+	next func(workerId int) ([]string, []interface{}), stop func()) {
+	rows := make(chan fakeRow, buffer)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var startOnce, stopOnce sync.Once
+
+	start := func() {
+		wg.Add(b.CommonOpts.Workers)
+		for p := 0; p < b.CommonOpts.Workers; p++ {
+			go func(producerID int) {
+				defer wg.Done()
+
+				for {
+					columns, values := b.GenFakeData(producerID, colConfs, withAutoInc)
+					select {
+					case rows <- fakeRow{columns: columns, values: values}:
+					case <-done:
+						return
+					}
+				}
+			}(p)
+		}
+	}
+
+	next = func(workerId int) ([]string, []interface{}) {
+		startOnce.Do(start)
+		row := <-rows
+
+		return row.columns, row.values
+	}
+
+	stop = func() {
+		stopOnce.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+
+	return next, stop
+}
+
 /*
  * UPDATE worker
  */
@@ -386,25 +530,31 @@ func testUpdateGeneric(b *benchmark.Benchmark, testDesc *TestDesc, updateRows ui
 		b.Worker = func(workerId int) (loops int) {
 			c := b.WorkerData[workerId].(*DBWorkerData).conn
 
-			c.Begin()
-
-			for i := 0; i < batch; i++ {
-				id := int64(b.Randomizer.GetWorker(workerId).Uintn64(table.RowsCount-updateRows) + updateRows)
-				_, values := b.GenFakeData(workerId, colConfs, false)
+			err := c.Transact(func() error {
+				for i := 0; i < batch; i++ {
+					id := int64(b.Randomizer.GetWorker(workerId).Uintn64(table.RowsCount-updateRows) + updateRows)
+					_, values := b.GenFakeData(workerId, colConfs, false)
 
-				values = append(values, id)
-				if updateRows > 1 {
-					values = append(values, id-int64(updateRows))
-				}
+					values = append(values, id)
+					if updateRows > 1 {
+						values = append(values, id-int64(updateRows))
+					}
 
-				c.QueryAndReturnString(updateSQL, values...)
+					if _, err := c.Exec(updateSQL, values...); err != nil {
+						return err
+					}
 
-				if b.TestOpts.(*TestOpts).BenchOpts.Events {
-					rw := b.Randomizer.GetWorker(workerId)
-					b.Vault.(*DBTestData).EventBus.InsertEvent(rw, c, rw.UUID())
+					if b.TestOpts.(*TestOpts).BenchOpts.Events {
+						rw := b.Randomizer.GetWorker(workerId)
+						b.Vault.(*DBTestData).EventBus.InsertEvent(rw, c, rw.UUID())
+					}
 				}
+
+				return nil
+			})
+			if err != nil {
+				c.Exit(err.Error())
 			}
-			c.Commit()
 
 			return batch * int(updateRows)
 		}
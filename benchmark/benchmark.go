@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/ClickHouse/clickhouse-go/v2" // clickhouse driver
@@ -32,6 +33,9 @@ type Score struct {
 	Loops   uint64
 	Rate    float64
 	Metric  string
+	P99     time.Duration // p99 of the Worker calls duration, set only if Benchmark.CollectLatencies is enabled
+
+	WorkerLoops []int // loops done by every worker
 }
 
 // FormatRate formats rate to 4 significant figures
@@ -55,9 +59,18 @@ func (s *Score) FormatRate(n int) string { //nolint:revive
 	return fmt.Sprintf(format, s.Rate)
 }
 
+// Warmup is the unmeasured work every worker does before the measured window of Benchmark.Run(): given amount of Worker
+// calls or calls for given time, whichever limit is set
+type Warmup struct {
+	Loops    int
+	Duration time.Duration
+}
+
 // Benchmark is used for running tests
 // Init is called once before InitPerWorker and should initialize program constants, global variables, etc.
 // InitPerWorker is called Benchmark.CommonOpts.Workers times and should initialize data structs required for running Worker method
+// PostWarmup is called once after InitPerWorker and the Warmup, right before the measured window, and should reset
+// everything the warmup has changed but must not be counted (e.g. the test own counters)
 // Worker runs user logic and should use opts.WorkerData[id] and opts.Vault
 // FinishPerWorker is called Benchmark.CommonOpts.Workers times and should deinit all WorkerData structs
 // Finish is called once after FinishPerWorker and should call some logic(e.g. analyze data) and deinit used data structs
@@ -66,6 +79,7 @@ type Benchmark struct {
 	Init            func()
 	InitPerWorker   func(id int)
 	PreWorker       func(id int)
+	PostWarmup      func()
 	Worker          func(id int) (loops int)
 	FinishPerWorker func(id int)
 	Finish          func()
@@ -82,8 +96,15 @@ type Benchmark struct {
 	TenantsCache    *TenantsCache
 	Randomizer      *Randomizer
 
-	NeedToExit bool
-	Score      Score
+	NeedToExit       bool
+	Score            Score
+	CollectLatencies bool
+	Warmup           Warmup // Warmup is done once per Run(), it's counted neither in the Score nor in the metrics
+	TestName         string // name of the running test, see --raw-latencies
+	ReconnectOnLoss  bool   // a worker loop failed on a lost DB connection is retried once after reconnect, see DatabaseOpts.ReconnectOnLoss
+
+	rawLatencies *rawLatencyWriter
+	metrics      *metricsServer
 
 	CliArgs    []string
 	WorkerData []WorkerData
@@ -104,6 +125,8 @@ func New() *Benchmark {
 		},
 		PreWorker: func(id int) {
 		},
+		PostWarmup: func() {
+		},
 		Worker: func(id int) (loops int) {
 			return 0
 		},
@@ -156,6 +179,31 @@ func (b *Benchmark) InitOpts() {
 		b.Logger = NewLogger(len(b.CommonOpts.Verbose) + 1)
 	}
 	b.adjustFilenoUlimit()
+
+	if b.CommonOpts.RawLatencies != "" {
+		var err error
+		if b.rawLatencies, err = newRawLatencyWriter(b.CommonOpts.RawLatencies); err != nil {
+			b.Exit("can't create --raw-latencies file: %v", err)
+		}
+	}
+
+	if b.CommonOpts.MetricsAddr != "" {
+		var err error
+		if b.metrics, err = newMetricsServer(b, b.CommonOpts.MetricsAddr); err != nil {
+			b.Exit("can't start --metrics-addr endpoint: %v", err)
+		}
+	}
+}
+
+// closeRawLatencies flushes the buffered raw latencies and closes the --raw-latencies file
+func (b *Benchmark) closeRawLatencies() {
+	if b.rawLatencies == nil {
+		return
+	}
+	if err := b.rawLatencies.close(); err != nil {
+		fmt.Printf("can't write --raw-latencies file: %v\n", err)
+	}
+	b.rawLatencies = nil
 }
 
 // SetUsage sets usage information
@@ -182,10 +230,16 @@ func (b *Benchmark) RunOnce(printScore bool) {
 	wg.Add(b.CommonOpts.Workers)
 
 	loops := make([]int, b.CommonOpts.Workers)
+	latencies := make([][]time.Duration, b.CommonOpts.Workers)
+
+	var metrics *testMetrics
+	if b.metrics != nil {
+		metrics = b.metrics.start(b.TestName, b.Metric())
+	}
 
 	startTime := time.Now().UnixNano()
 	for i := 0; i < b.CommonOpts.Workers; i++ {
-		go runner(i, b, &loops[i], requiredLoops[i], &wg)
+		go runner(i, b, &loops[i], &latencies[i], requiredLoops[i], metrics, &wg)
 	}
 	wg.Wait()
 
@@ -196,6 +250,20 @@ func (b *Benchmark) RunOnce(printScore bool) {
 		totalLoops += uint64(loop)
 	}
 
+	if metrics != nil {
+		rate := 0.0
+		if totalLoops > 0 {
+			rate = b.GetRate(totalLoops, float64(endTime-startTime)/float64(time.Second))
+		}
+		b.metrics.stop(metrics, rate)
+	}
+
+	var allLatencies []time.Duration
+	for _, l := range latencies {
+		allLatencies = append(allLatencies, l...)
+	}
+	b.Score.P99 = Percentile(allLatencies, 99)
+
 	if totalLoops == 0 {
 		return
 	}
@@ -205,6 +273,7 @@ func (b *Benchmark) RunOnce(printScore bool) {
 	b.Score.Metric = b.Metric()
 	b.Score.Workers = b.CommonOpts.Workers
 	b.Score.Loops = totalLoops
+	b.Score.WorkerLoops = loops
 
 	if printScore {
 		b.PrintScore(b.Score)
@@ -241,6 +310,11 @@ func (b *Benchmark) Run() {
 		}
 	}
 
+	if b.Warmup.Loops > 0 || b.Warmup.Duration > 0 {
+		b.warmup()
+	}
+	b.PostWarmup()
+
 	var minRate, maxRate, sumRate float64
 	minRate = -1
 	maxRate = -1
@@ -268,6 +342,12 @@ func (b *Benchmark) Run() {
 
 	b.Finish()
 
+	if b.rawLatencies != nil {
+		if err := b.rawLatencies.flush(); err != nil {
+			b.Exit("can't write --raw-latencies file: %v", err)
+		}
+	}
+
 	b.PrintScore(b.Score)
 
 	if b.CommonOpts.Repeat > 1 {
@@ -275,14 +355,83 @@ func (b *Benchmark) Run() {
 	}
 }
 
+// warmup runs the Worker of every worker until the Warmup limit is reached w/o measuring anything
+func (b *Benchmark) warmup() {
+	b.Log(LogDebug, 0, "warmup")
+
+	var wg sync.WaitGroup
+	wg.Add(b.CommonOpts.Workers)
+
+	for i := 0; i < b.CommonOpts.Workers; i++ {
+		go func(id int) {
+			defer wg.Done()
+
+			start := time.Now()
+			for n := 0; !b.NeedToExit; n++ {
+				if b.Warmup.Loops > 0 && n >= b.Warmup.Loops {
+					break
+				}
+				if b.Warmup.Duration > 0 && time.Since(start) >= b.Warmup.Duration {
+					break
+				}
+
+				b.PreWorker(id)
+				if b.runWorker(id) == 0 {
+					break
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
 // runner is a helper function for running tests in parallel
-func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGroup) {
+func runner(id int, b *Benchmark, loops *int, latencies *[]time.Duration, requiredLoops int, metrics *testMetrics, wg *sync.WaitGroup) {
 	var l int
 	doneLoops := 0
+
+	// the raw latencies are kept locally and passed to the shared writer in chunks not to perturb the hot path
+	var raw []rawLatency
+	flushRaw := func() {
+		if len(raw) == 0 {
+			return
+		}
+		if err := b.rawLatencies.write(b.TestName, id, raw); err != nil {
+			b.Log(LogError, id, "can't write --raw-latencies file: %v", err)
+		}
+		raw = raw[:0]
+	}
+
+	work := func() int {
+		if !b.CollectLatencies && b.rawLatencies == nil && metrics == nil {
+			return b.runWorker(id)
+		}
+		start := time.Now()
+		l := b.runWorker(id)
+		latency := time.Since(start)
+
+		if metrics != nil {
+			atomic.AddUint64(&metrics.loops, uint64(l))
+		}
+
+		if b.CollectLatencies {
+			*latencies = append(*latencies, latency)
+		}
+		if b.rawLatencies != nil {
+			raw = append(raw, rawLatency{start: start, latency: latency})
+			if len(raw) >= rawLatencyChunk {
+				flushRaw()
+			}
+		}
+
+		return l
+	}
+
 	if b.CommonOpts.Loops != 0 {
 		for doneLoops < requiredLoops {
 			b.PreWorker(id)
-			l = b.Worker(id)
+			l = work()
 			if l == 0 {
 				break
 			}
@@ -300,7 +449,7 @@ func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGr
 		startTime := time.Now().UnixNano()
 		for time.Now().UnixNano()-startTime < int64(b.CommonOpts.Duration*1000000000) {
 			b.PreWorker(id)
-			l = b.Worker(id)
+			l = work()
 			if l == 0 {
 				break
 			}
@@ -316,6 +465,7 @@ func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGr
 		}
 	}
 
+	flushRaw()
 	*loops = doneLoops
 
 	wg.Done()
@@ -323,6 +473,9 @@ func runner(id int, b *Benchmark, loops *int, requiredLoops int, wg *sync.WaitGr
 
 // Exit calls os.Exit() and sets 127 exit code if there is a message (+ args) passed, otherwise just exit with 0 (successfull exit)
 func (b *Benchmark) Exit(fmtAndArgs ...interface{}) {
+	b.closeRawLatencies()
+	b.closeMetrics()
+
 	if len(fmtAndArgs) == 0 {
 		b.PreExit()
 		os.Exit(0)
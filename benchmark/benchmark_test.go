@@ -2,6 +2,9 @@ package benchmark
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -26,6 +29,64 @@ func TestRunOnce(t *testing.T) {
 	}
 }
 
+func TestWarmup(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 100
+	b.Warmup = Warmup{Loops: 5}
+
+	var calls int64
+	b.Worker = func(id int) (loops int) {
+		atomic.AddInt64(&calls, 1)
+
+		return 1
+	}
+
+	b.warmup()
+	if calls != 10 {
+		t.Errorf("warmup() error, calls = %v, want %v", calls, 10)
+	}
+
+	b.RunOnce(false)
+	if b.Score.Loops != 100 {
+		t.Errorf("RunOnce() after warmup() error, loops = %v, want %v", b.Score.Loops, 100)
+	}
+
+	b.Warmup = Warmup{Duration: 10 * time.Millisecond}
+	start := time.Now()
+	b.warmup()
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("warmup() error, took %v, want at least %v", elapsed, 10*time.Millisecond)
+	}
+}
+
+func TestPostWarmup(t *testing.T) {
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 10
+	b.CommonOpts.Repeat = 1
+	b.OptsInitialized = true
+	b.Warmup = Warmup{Loops: 5}
+
+	var calls, warmupCalls int64
+	b.Worker = func(id int) (loops int) {
+		atomic.AddInt64(&calls, 1)
+
+		return 1
+	}
+	b.PostWarmup = func() {
+		warmupCalls = atomic.SwapInt64(&calls, 0)
+	}
+
+	b.Run()
+	if warmupCalls != 10 {
+		t.Errorf("PostWarmup() error, warmup calls = %v, want %v", warmupCalls, 10)
+	}
+	if b.Score.Loops != 10 {
+		t.Errorf("Run() after PostWarmup() error, loops = %v, want %v", b.Score.Loops, 10)
+	}
+}
+
 func TestFormatRateWithZeroRate(t *testing.T) {
 	score := Score{Rate: 0.0}
 	result := score.FormatRate(4)
@@ -110,3 +171,40 @@ func TestRun(t *testing.T) {
 		t.Errorf("Run() error, seconds = %v, want less than or equal to %v", b.Score.Seconds, 1)
 	}
 }
+
+func TestRunWithRawLatencies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raw.csv")
+
+	b := New()
+	b.CommonOpts.Workers = 2
+	b.CommonOpts.Loops = 10
+	b.TestName = "test-raw"
+	b.Worker = func(id int) (loops int) {
+		return 1
+	}
+
+	var err error
+	if b.rawLatencies, err = newRawLatencyWriter(path); err != nil {
+		t.Fatalf("newRawLatencyWriter() error = %v", err)
+	}
+	b.RunOnce(false)
+	b.closeRawLatencies()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("can't read raw latencies: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("raw latencies got %d lines, want header + 10 records", len(lines))
+	}
+	if lines[0] != "test,worker,start_ns,latency_ns" {
+		t.Errorf("raw latencies header got = %s", lines[0])
+	}
+	for _, line := range lines[1:] {
+		if fields := strings.Split(line, ","); len(fields) != 4 || fields[0] != "test-raw" {
+			t.Errorf("raw latencies record got = %s", line)
+		}
+	}
+}
@@ -18,19 +18,40 @@ type CommonOpts struct {
 	Repeat   int    `short:"r" long:"repeat" description:"repeat the test given amount of times" required:"false" default:"1"`
 	Quiet    bool   `short:"Q" long:"quiet" description:"be quiet and print as less information as possible"`
 	RandSeed int64  `short:"s" long:"randseed" description:"Seed used for random number generation" required:"false" default:"1"`
+
+	RawLatencies string `long:"raw-latencies" description:"stream every loop latency (test, worker, start and latency in nanoseconds) into given CSV file for offline analysis" required:"false"`
+	MetricsAddr  string `long:"metrics-addr" description:"expose the loops, rate and errors of the running tests as Prometheus metrics on given address (e.g. :9090) at /metrics" required:"false"`
 }
 
 // DatabaseOpts represents common flags for every test
 type DatabaseOpts struct {
-	Driver           string `long:"driver" description:"db driver (postgres|mysql|sqlite3)" default:"postgres" required:"false"`
-	Dsn              string `long:"dsn" description:"dsn connection string" default:"host=127.0.0.1 sslmode=disable user=test_user" required:"false"`
-	DontCleanup      bool   `long:"dont-cleanup" description:"do not cleanup DB content before/after the test in '-t all' mode" required:"false"`
-	UseTruncate      bool   `long:"use-truncate" description:"use TRUNCATE instead of DROP TABLE in cleanup procedure" required:"false"`
-	MaxOpenConns     int    `long:"maxopencons" description:"Set sql/db MaxOpenConns per worker, default value is set to 2 because the benchmark uses it's own workers pool" default:"2" required:"false"`
-	MySQLEngine      string `long:"mysql-engine" description:"mysql engine (innodb|myisam|xpand|...)" default:"innodb" required:"false"`
-	Reconnect        bool   `long:"reconnect" description:"reconnect to DB before every test iteration" required:"false"`
-	DryRun           bool   `long:"dry-run" description:"do not execute any INSERT/UPDATE/DELETE queries on DB-side" required:"false"`
-	EmbeddedPostgres bool   `long:"embedded-postgres" description:"use embedded postgres and apply --driver postgres" required:"false"`
+	Driver             string   `long:"driver" description:"db driver (postgres|mysql|sqlite3)" default:"postgres" required:"false"`
+	Dsn                string   `long:"dsn" description:"dsn connection string" default:"host=127.0.0.1 sslmode=disable user=test_user" required:"false"`
+	DontCleanup        bool     `long:"dont-cleanup" description:"do not cleanup DB content before/after the test in '-t all' mode" required:"false"`
+	UseTruncate        bool     `long:"use-truncate" description:"use TRUNCATE instead of DROP TABLE in cleanup procedure" required:"false"`
+	MaxOpenConns       int      `long:"maxopencons" description:"Set sql/db MaxOpenConns per worker, default value is set to 2 because the benchmark uses it's own workers pool" default:"2" required:"false"`
+	MySQLEngine        string   `long:"mysql-engine" description:"mysql engine (innodb|myisam|xpand|...)" default:"innodb" required:"false"`
+	Reconnect          bool     `long:"reconnect" description:"reconnect to DB before every test iteration" required:"false"`
+	DryRun             bool     `long:"dry-run" description:"do not execute any INSERT/UPDATE/DELETE queries on DB-side" required:"false"`
+	EmbeddedPostgres   bool     `long:"embedded-postgres" description:"use embedded postgres and apply --driver postgres" required:"false"`
+	PgProtocol         string   `long:"pg-protocol" description:"postgres query protocol for parametrized queries (simple|extended|prepared)" default:"simple" required:"false"`
+	PgBouncerMode      string   `long:"pgbouncer-mode" description:"the postgres dsn points to PgBouncer with given pool_mode (session|transaction|statement|auto), server-side prepared statements are disabled in transaction and statement modes" required:"false"`
+	ConnPerWorker      bool     `long:"conn-per-worker" description:"run every test twice: with sql/db pool of --maxopencons connections per worker and with single pinned DB connection per worker, and report the throughput difference" required:"false"`
+	PoolAcquireTimeout int      `long:"pool-acquire-timeout" description:"max time (msec) a statement may wait for a free connection in the worker's pool, the test fails if it's exceeded, also enables pool wait reporting (0 - disabled)" default:"0" required:"false"`
+	SessionTimezone    string   `long:"session-timezone" description:"session time zone to set on every DB connection, e.g. 'UTC' or 'Europe/Berlin' (postgres|mysql|sqlite)" required:"false"`
+	ReadReplicas       string   `long:"read-replicas" description:"comma-separated DSNs of read replicas, workers of read-only tests are spread across them in round-robin, writes still go to --dsn" required:"false"`
+	MaxRowsInMemory    int      `long:"max-rows-in-memory" description:"max amount of rows a single query result may buffer in memory, the test fails fast if exceeded (0 - unlimited)" default:"0" required:"false"`
+	TxMaxRetries       int      `long:"tx-max-retries" description:"max amount of times a transaction is retried after a transient error (serialization failure, deadlock, lock timeout)" default:"3" required:"false"`
+	MaxRetries         *int     `long:"max-retries" description:"alias of --tx-max-retries, overrides it if set" required:"false"`
+	TxBackoff          int      `long:"tx-backoff" description:"initial delay (msec) before retrying a transaction, doubled on every next attempt" default:"10" required:"false"`
+	Isolation          string   `long:"isolation" description:"transaction isolation level (read-committed|repeatable-read|serializable), the write tests are skipped if the database doesn't support it (default: the database default)" required:"false"`
+	InjectLatency      int      `long:"inject-latency" description:"artificial delay (msec) added to every DB statement to emulate a slow network" default:"0" required:"false"`
+	InjectErrorRate    float64  `long:"inject-error-rate" description:"percentage of retryable (transactional) statements failed with an artificial transient error" default:"0" required:"false"`
+	InjectDropRate     float64  `long:"inject-drop-rate" description:"percentage of Exec()/Query() statements preceded by killing a pooled DB session (on the server for postgres and mysql), forcing a reconnect" default:"0" required:"false"`
+	ReconnectOnLoss    int      `long:"reconnect-on-loss" description:"max time (sec) to wait for the database to come back after a lost connection (e.g. a server restart), the failed worker loop is retried once (0 - disabled, fail fast)" default:"0" required:"false"`
+	InitSQL            []string `long:"init-sql" description:"SQL statement executed once on every new pooled DB connection, e.g. SET statement_timeout = 5000 (can be repeated)" required:"false"`
+
+	Dialect string `no-flag:"true"` // Dialect is the SQL dialect served by the driver, see ResolveDialect()
 }
 
 // CLI is a wrapper for go-flags library
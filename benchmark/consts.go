@@ -19,13 +19,39 @@ const (
 	MSSQL      = "mssql"      // MSSQL is the Microsoft SQL Server driver name
 	CLICKHOUSE = "clickhouse" // CLICKHOUSE is the ClickHouse driver name
 	CASSANDRA  = "cassandra"  // CASSANDRA is the Cassandra driver name
+	COCKROACH  = "cockroach"  // COCKROACH is the CockroachDB dialect name, it is served by the postgres driver, see GetDialectName()
 
 	SequenceName = "acronis_db_bench_sequence" // SequenceName is the name of the sequence used for generating IDs
 )
 
+const (
+	PgProtocolSimple   = "simple"   // PgProtocolSimple sends literal SQL w/o bind parameters
+	PgProtocolExtended = "extended" // PgProtocolExtended sends SQL with bind parameters on every call
+	PgProtocolPrepared = "prepared" // PgProtocolPrepared prepares SQL once per connection and then reuses it
+)
+
+const (
+	PgBouncerSession     = "session"     // PgBouncerSession pins a server connection to the client session, i.e. it's like a direct connection
+	PgBouncerTransaction = "transaction" // PgBouncerTransaction pins a server connection for the duration of a transaction only
+	PgBouncerStatement   = "statement"   // PgBouncerStatement returns the server connection after every statement, no multi-statement transactions
+	PgBouncerAuto        = "auto"        // PgBouncerAuto detects the pool mode, see DBConnector.DetectPgBouncerMode()
+)
+
+const (
+	IsolationReadCommitted  = "read-committed"  // IsolationReadCommitted is the READ COMMITTED transaction isolation level
+	IsolationRepeatableRead = "repeatable-read" // IsolationRepeatableRead is the REPEATABLE READ transaction isolation level
+	IsolationSerializable   = "serializable"    // IsolationSerializable is the SERIALIZABLE transaction isolation level
+)
+
 var (
 	// SupportedDrivers is a string containing all supported drivers
 	SupportedDrivers = strings.Join([]string{SQLITE, POSTGRES, MYSQL, MSSQL}, "|")
 	// CassandraKeySpace is the name of the DB keyspace used for Cassandra
 	CassandraKeySpace = "acronis_db_bench"
+	// SupportedPgProtocols is a string containing all supported postgres query protocols
+	SupportedPgProtocols = strings.Join([]string{PgProtocolSimple, PgProtocolExtended, PgProtocolPrepared}, "|")
+	// SupportedIsolationLevels is a string containing all supported transaction isolation levels
+	SupportedIsolationLevels = strings.Join([]string{IsolationReadCommitted, IsolationRepeatableRead, IsolationSerializable}, "|")
+	// SupportedPgBouncerModes is a string containing all supported --pgbouncer-mode values
+	SupportedPgBouncerModes = strings.Join([]string{PgBouncerSession, PgBouncerTransaction, PgBouncerStatement, PgBouncerAuto}, "|")
 )
@@ -5,14 +5,18 @@ package benchmark
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"math/rand"
+	"net/url"
 	"os"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/MichaelS11/go-cql-driver"
@@ -25,14 +29,15 @@ type dbConnectorsPool struct {
 	pool map[string]*DBConnector
 }
 
-// key returns a unique key for the connection pool
-func (p *dbConnectorsPool) key(driver string, dsn string, workerID int) string {
-	return fmt.Sprintf("%s-%s-%d", driver, dsn, workerID)
+// key returns a unique key for the connection pool, the pinned and the pooled connectors are kept apart, because the
+// session pool of the connector is configured once on connect, see --conn-per-worker
+func (p *dbConnectorsPool) key(dbOpts *DatabaseOpts, workerID int) string {
+	return fmt.Sprintf("%s-%s-%d-%t", dbOpts.Driver, dbOpts.Dsn, workerID, dbOpts.ConnPerWorker)
 }
 
 // take returns a connection from the pool or nil if the pool is empty
 func (p *dbConnectorsPool) take(dbOpts *DatabaseOpts, workerID int) *DBConnector {
-	k := p.key(dbOpts.Driver, dbOpts.Dsn, workerID)
+	k := p.key(dbOpts, workerID)
 
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -50,7 +55,7 @@ func (p *dbConnectorsPool) take(dbOpts *DatabaseOpts, workerID int) *DBConnector
 
 // put puts a connection to the pool
 func (p *dbConnectorsPool) put(conn *DBConnector) {
-	k := p.key(conn.DbOpts.Driver, conn.DbOpts.Dsn, conn.WorkerID)
+	k := p.key(conn.DbOpts, conn.WorkerID)
 
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -92,8 +97,22 @@ type DBConnector struct {
 	dbrSess   *dbr.Session
 	tx        *sql.Tx
 	txStart   time.Time
+	stmts     map[string]*sql.Stmt // prepared statements cache, see --pg-protocol=prepared
+
+	acquireWaits []time.Duration // time spent waiting for a free pool connection, see --pool-acquire-timeout
+	waitTotal    time.Duration   // pool WaitDuration seen by the previous statement, see recordAcquireWait()
+	rePrepares   int             // amount of prepared statements re-prepared after a schema change
+	txRetries    int             // amount of transactions retried after a transient error, see --tx-max-retries
+	lastErr      error           // error of the last failed statement, see --reconnect-on-loss
+
+	transacting    bool // true while Transact() runs, so the statement may be retried
+	injectedErrors int  // amount of artificial errors, see --inject-error-rate
+	injectedDrops  int  // amount of artificial connection drops, see --inject-drop-rate
 }
 
+// ErrInjectedFault is the artificial transient error returned by Exec() and Query() inside Transact() with --inject-error-rate
+var ErrInjectedFault = errors.New("injected fault")
+
 // connectionsChecker checks for potential connections leak
 func connectionsChecker(conn *DBConnector) {
 	for {
@@ -123,7 +142,20 @@ func NewDBConnector(dbOpts *DatabaseOpts, workerID int, logger *Logger, retryAtt
 		return c
 	}
 
-	c = &DBConnector{
+	return newDBConnector(dbOpts, workerID, logger, retryAttempts)
+}
+
+// NewPinnedDBConnector creates a new DBConnector bypassing the pool, so it never returns an already connected one and
+// its DB session is always pinned to a single connection, the caller must Close() it instead of Release()
+func NewPinnedDBConnector(dbOpts *DatabaseOpts, workerID int, logger *Logger, retryAttempts int) *DBConnector {
+	opts := *dbOpts
+	opts.ConnPerWorker = true
+
+	return newDBConnector(&opts, workerID, logger, retryAttempts)
+}
+
+func newDBConnector(dbOpts *DatabaseOpts, workerID int, logger *Logger, retryAttempts int) *DBConnector {
+	c := &DBConnector{
 		Logger:        logger,
 		DbOpts:        dbOpts,
 		RetryAttempts: retryAttempts,
@@ -157,14 +189,22 @@ func (c *DBConnector) Logn(LogLevel int, format string, args ...interface{}) {
 	c.Logger.Logn(LogLevel, c.WorkerID, format, args...)
 }
 
-// Exit exits with an error message
+// Exit exits with an error message, or lets the worker loop recover the lost connection, see --reconnect-on-loss
 func (c *DBConnector) Exit(fmts string, args ...interface{}) {
+	if err := c.lostConnection(); err != nil {
+		panic(&connectionLost{c: c, err: err, msg: fmt.Sprintf(fmts, args...)})
+	}
+
+	c.exit(fmt.Sprintf(fmts, args...))
+}
+
+// exit exits with given error message
+func (c *DBConnector) exit(msg string) {
 	if c.Logger.LogLevel >= LogDebug {
 		fmt.Println()
 		printStack()
 	}
-	fmt.Printf(fmts, args...)
-	fmt.Println()
+	fmt.Println(msg)
 	os.Exit(127)
 }
 
@@ -181,9 +221,41 @@ func (c *DBConnector) db() *sql.DB {
 		c.Connect()
 	}
 
+	if c.DbOpts.PoolAcquireTimeout > 0 {
+		c.recordAcquireWait()
+	}
+
 	return c.dbSess
 }
 
+// recordAcquireWait records the time the previous statement waited for a free connection in the pool; it's the growth of
+// the pool WaitDuration since the previous call, so measuring it doesn't take an extra connection from the pool
+func (c *DBConnector) recordAcquireWait() {
+	// WaitDuration counts only the time spent blocked on MaxOpenConns limit, i.e. it doesn't include the connect time
+	waitTotal := c.dbSess.Stats().WaitDuration
+
+	c.lock.Lock()
+	wait := waitTotal - c.waitTotal
+	c.waitTotal = waitTotal
+	c.acquireWaits = append(c.acquireWaits, wait)
+	c.lock.Unlock()
+
+	if wait > time.Duration(c.DbOpts.PoolAcquireTimeout)*time.Millisecond {
+		c.Exit("waited %v for a free DB connection in the pool, more than %d msec (--maxopencons=%d)", wait, c.DbOpts.PoolAcquireTimeout, c.DbOpts.MaxOpenConns)
+	}
+}
+
+// TakeAcquireWaits returns pool acquisition wait times collected so far and resets them
+func (c *DBConnector) TakeAcquireWaits() []time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	waits := c.acquireWaits
+	c.acquireWaits = nil
+
+	return waits
+}
+
 // Ping pings the DB
 func (c *DBConnector) Ping() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -199,6 +271,122 @@ func (c *DBConnector) Ping() error {
 	return err
 }
 
+// withSessionTimezone returns the dsn extended with the driver specific parameter setting the session time zone
+func withSessionTimezone(driver string, dsn string, tz string) (string, error) {
+	if tz == "" {
+		return dsn, nil
+	}
+
+	if _, err := time.LoadLocation(tz); err != nil {
+		return "", fmt.Errorf("invalid session time zone '%s': %w", tz, err)
+	}
+
+	appendParam := func(dsn string, param string) string {
+		if strings.Contains(dsn, "?") {
+			return dsn + "&" + param
+		}
+
+		return dsn + "?" + param
+	}
+
+	switch driver {
+	case POSTGRES:
+		if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+			return appendParam(dsn, "timezone="+url.QueryEscape(tz)), nil
+		}
+
+		return fmt.Sprintf("%s timezone='%s'", dsn, strings.ReplaceAll(tz, "'", "\\'")), nil
+	case MYSQL:
+		return appendParam(dsn, "time_zone="+url.QueryEscape("'"+tz+"'")), nil
+	case SQLITE, SQLITE3:
+		return appendParam(dsn, "_loc="+url.QueryEscape(tz)), nil
+	default:
+		return "", fmt.Errorf("session time zone is not supported for driver '%s'", driver)
+	}
+}
+
+// initSQLConnector runs the --init-sql statements on every new connection created by the sql/db pool
+type initSQLConnector struct {
+	driver.Connector
+	initSQL []string
+}
+
+// Connect creates a new connection and runs the init statements on it
+func (ic *initSQLConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := ic.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, query := range ic.initSQL {
+		if err = execOnConn(ctx, conn, query); err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("init SQL '%s' failed: %w", query, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// execOnConn executes a statement on a raw driver connection
+func execOnConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	stmt, err := conn.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(nil) //nolint:staticcheck
+
+	return err
+}
+
+// dsnConnector is a connector for the drivers not implementing driver.DriverContext
+type dsnConnector struct {
+	dsn string
+	drv driver.Driver
+}
+
+// Connect opens a new connection using the dsn
+func (dc dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return dc.drv.Open(dc.dsn)
+}
+
+// Driver returns the underlying driver
+func (dc dsnConnector) Driver() driver.Driver {
+	return dc.drv
+}
+
+// openDB opens a sql/db pool, if initSQL is given every new pooled connection runs it once before the first use
+func openDB(driverName string, dsn string, initSQL []string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil || len(initSQL) == 0 {
+		return db, err
+	}
+
+	drv := db.Driver()
+	db.Close()
+
+	var connector driver.Connector
+	if driverCtx, ok := drv.(driver.DriverContext); ok {
+		if connector, err = driverCtx.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	} else {
+		connector = dsnConnector{dsn: dsn, drv: drv}
+	}
+
+	return sql.OpenDB(&initSQLConnector{Connector: connector, initSQL: initSQL}), nil
+}
+
 // Connect connects to the DB
 func (c *DBConnector) Connect() {
 	if c.dbSess != nil {
@@ -214,6 +402,22 @@ func (c *DBConnector) Connect() {
 		c.Exit("unsupported driver: '%v', supported drivers are: %s", c.DbOpts.Driver, SupportedDrivers)
 	}
 
+	dsn, err := withSessionTimezone(c.DbOpts.Driver, dsn, c.DbOpts.SessionTimezone)
+	if err != nil {
+		c.Exit(err.Error())
+	}
+
+	switch c.DbOpts.PgProtocol {
+	case "", PgProtocolSimple, PgProtocolExtended, PgProtocolPrepared:
+		break
+	default:
+		c.Exit("unsupported postgres protocol: '%v', supported protocols are: %s", c.DbOpts.PgProtocol, SupportedPgProtocols)
+	}
+
+	if _, ok := isolationLevels[c.DbOpts.Isolation]; !ok && c.DbOpts.Isolation != "" {
+		c.Exit("unsupported isolation level: '%v', supported levels are: %s", c.DbOpts.Isolation, SupportedIsolationLevels)
+	}
+
 	connect := func() {
 		c.Log(LogTrace, "connecting to DB (native) ... ")
 
@@ -243,10 +447,11 @@ func (c *DBConnector) Connect() {
 		}
 
 		for r := 0; !connected && r < c.RetryAttempts; r++ {
-			sess, err = sql.Open(driver, dsn)
+			sess, err = openDB(driver, dsn, c.DbOpts.InitSQL)
 
 			c.lock.Lock()
 			c.dbSess = sess
+			c.waitTotal = 0
 			c.lock.Unlock()
 
 			if err == nil {
@@ -267,8 +472,16 @@ func (c *DBConnector) Connect() {
 
 		c.Log(LogTrace, "connected to DB")
 
-		c.dbSess.SetMaxOpenConns(c.DbOpts.MaxOpenConns)
-		c.dbSess.SetMaxIdleConns(c.DbOpts.MaxOpenConns)
+		if c.DbOpts.ConnPerWorker {
+			// the only connection is never closed as idle or expired, so the worker always uses the same DB session
+			c.dbSess.SetMaxOpenConns(1)
+			c.dbSess.SetMaxIdleConns(1)
+			c.dbSess.SetConnMaxLifetime(0)
+			c.dbSess.SetConnMaxIdleTime(0)
+		} else {
+			c.dbSess.SetMaxOpenConns(c.DbOpts.MaxOpenConns)
+			c.dbSess.SetMaxIdleConns(c.DbOpts.MaxOpenConns)
+		}
 	}
 
 	connect()
@@ -328,6 +541,72 @@ func (c *DBConnector) GetVersion() (string, string) {
 	return c.DbOpts.Driver, version
 }
 
+// DBFeature is an optional database capability some tests rely on, see SupportsFeature
+type DBFeature string
+
+const (
+	// FeatureSkipLocked is locking rows while skipping the ones locked by others, e.g. SELECT ... FOR UPDATE SKIP LOCKED
+	FeatureSkipLocked DBFeature = "skip-locked"
+	// FeatureAdvisoryLocks is application defined named locks, e.g. pg_try_advisory_xact_lock(), GET_LOCK() or sp_getapplock
+	FeatureAdvisoryLocks DBFeature = "advisory-locks"
+)
+
+// versionAtLeast returns true if the first major.minor number found in given version string is not less than major.minor
+func versionAtLeast(version string, major int, minor int) bool {
+	start := strings.IndexAny(version, "0123456789")
+	if start < 0 {
+		return false
+	}
+
+	var gotMajor, gotMinor int
+	if n, _ := fmt.Sscanf(version[start:], "%d.%d", &gotMajor, &gotMinor); n == 0 {
+		return false
+	}
+
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
+
+// driverSupportsFeature returns true if given DB driver of given server version (see GetVersion) supports the feature
+func driverSupportsFeature(driver string, version string, feature DBFeature) bool {
+	switch feature {
+	case FeatureSkipLocked:
+		switch driver {
+		case POSTGRES:
+			if strings.Contains(version, "CockroachDB") {
+				return versionAtLeast(version, 23, 1)
+			}
+
+			return versionAtLeast(version, 9, 5)
+		case MYSQL:
+			if strings.Contains(version, "MariaDB") {
+				return versionAtLeast(version, 10, 6)
+			}
+
+			return versionAtLeast(version, 8, 0)
+		case MSSQL:
+			// READPAST table hint
+			return true
+		}
+	case FeatureAdvisoryLocks:
+		switch driver {
+		case POSTGRES:
+			// CockroachDB has the pg_advisory_*() functions for compatibility only, they don't lock anything
+			return !strings.Contains(version, "CockroachDB")
+		case MYSQL, MSSQL:
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsFeature returns true if the connected database supports given feature
+func (c *DBConnector) SupportsFeature(feature DBFeature) bool {
+	_, version := c.GetVersion()
+
+	return driverSupportsFeature(c.DbOpts.Driver, version, feature)
+}
+
 // GetInfo returns DB info
 func (c *DBConnector) GetInfo(version string) (ret []string, dbInfo *DBInfo) {
 	dbInfo = NewDBInfo(c, version)
@@ -515,6 +794,10 @@ func (c *DBConnector) Close() {
 				c.Exit(err.Error())
 			}
 		}
+		for query, stmt := range c.stmts {
+			stmt.Close()
+			delete(c.stmts, query)
+		}
 		c.dbSess.Close()
 		c.Log(LogTrace, "closing 'regular' DB connection")
 
@@ -533,6 +816,71 @@ func (c *DBConnector) Close() {
 	}
 }
 
+// UsePreparedStatements returns true if parametrized queries must be prepared once and then reused (see --pg-protocol)
+func (c *DBConnector) UsePreparedStatements() bool {
+	return c.DbOpts.Driver == POSTGRES && c.DbOpts.PgProtocol == PgProtocolPrepared
+}
+
+// prepare returns a cached prepared statement for given query or prepares a new one
+func (c *DBConnector) prepare(query string) (*sql.Stmt, error) {
+	if stmt, exists := c.stmts[query]; exists {
+		return stmt, nil
+	}
+
+	startTime := c.StatementEnter(query, nil)
+	stmt, err := c.db().Prepare(query)
+	c.StatementExit("Prepare()", startTime, err, false, nil, query, nil, nil, nil)
+
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+
+	if c.stmts == nil {
+		c.stmts = make(map[string]*sql.Stmt)
+	}
+	c.stmts[query] = stmt
+
+	return stmt, nil
+}
+
+// queryPrepared executes a query using cached prepared statement, the statement is re-prepared once if it became stale after a schema change
+func (c *DBConnector) queryPrepared(query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := c.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.Query(args...)
+	if !IsCachedPlanChanged(err) {
+		return rows, err
+	}
+
+	c.Log(LogDebug, "re-preparing stale statement: %s", query)
+	stmt.Close()
+	delete(c.stmts, query)
+
+	c.lock.Lock()
+	c.rePrepares++
+	c.lock.Unlock()
+
+	if stmt, err = c.prepare(query); err != nil {
+		return nil, err
+	}
+
+	return stmt.Query(args...)
+}
+
+// TakeRePrepares returns the amount of prepared statements re-prepared after a schema change since the last call and resets it
+func (c *DBConnector) TakeRePrepares() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	n := c.rePrepares
+	c.rePrepares = 0
+
+	return n
+}
+
 // StatementEnter is called before executing a statement
 func (c *DBConnector) StatementEnter(query string, args ...interface{}) time.Time { //nolint:revive
 	var startTime time.Time
@@ -590,6 +938,12 @@ func (c *DBConnector) StatementExit(statement string, startTime time.Time, err e
 		}
 		c.Log(c.logLevel, msg)
 	} else {
+		atomic.AddUint64(&statementErrors, 1)
+
+		c.lock.Lock()
+		c.lastErr = err
+		c.lock.Unlock()
+
 		c.Log(LogError, fmt.Sprintf("%s: '%s' error:\n%s", statement, msg, err.Error()))
 	}
 }
@@ -614,42 +968,232 @@ func (c *DBConnector) Begin() *sql.Tx {
 	}
 
 	var err error
-	c.tx, err = c.db().Begin()
+	if level, ok := isolationLevels[c.DbOpts.Isolation]; ok {
+		c.tx, err = c.db().BeginTx(context.Background(), &sql.TxOptions{Isolation: level})
+	} else {
+		c.tx, err = c.db().Begin()
+	}
 	c.Log(LogDebug, "BEGIN")
 	if err != nil {
+		c.lock.Lock()
+		c.lastErr = err
+		c.lock.Unlock()
+
 		c.Exit(err.Error())
 	}
 
 	return c.tx
 }
 
+// isolationLevels maps the --isolation values to the database/sql isolation levels
+var isolationLevels = map[string]sql.IsolationLevel{
+	IsolationReadCommitted:  sql.LevelReadCommitted,
+	IsolationRepeatableRead: sql.LevelRepeatableRead,
+	IsolationSerializable:   sql.LevelSerializable,
+}
+
+// SupportsIsolation returns true if the database runs the transactions at the --isolation level, the ones which don't
+// would either fail BEGIN or silently upgrade the level
+func (o *DatabaseOpts) SupportsIsolation() bool {
+	if o.Isolation == "" {
+		return true
+	}
+	if _, ok := isolationLevels[o.Isolation]; !ok {
+		return false
+	}
+
+	switch o.DialectName() {
+	case POSTGRES, MYSQL, MSSQL:
+		return true
+	case SQLITE, SQLITE3, COCKROACH:
+		// SQLite transactions are always serializable, CockroachDB upgrades the weaker levels unless enabled by the cluster settings
+		return o.Isolation == IsolationSerializable
+	default:
+		return false
+	}
+}
+
 // Commit commits a transaction
 // Note: CASSANDRA doesn't support transactions
 func (c *DBConnector) Commit() {
+	if err := c.commit(); err != nil {
+		c.Exit("DB commit failed\nError: %s", err.Error())
+	}
+}
+
+// commit commits a transaction and returns an error instead of exiting
+func (c *DBConnector) commit() error {
 	if c.DbOpts.Driver == CASSANDRA {
-		return
+		return nil
 	}
 	if c.DbOpts.DryRun {
 		c.Log(LogTrace, "skipping COMMIT request because of 'dry run' mode")
 
-		return
+		return nil
 	}
 	if c.tx == nil {
 		c.Exit("internal error: trying to call Commit() w/o Begin()")
 	}
 
 	err := c.tx.Commit()
+	c.tx = nil
 
-	if err == nil {
-		if c.Logger.LogLevel >= LogDebug {
-			c.Log(LogDebug, fmt.Sprintf("COMMIT # dur: %.6f", getElapsedTime(c.txStart)))
-		}
-	} else {
-		c.Exit("DB commit failed\nError: %s", err.Error())
+	if err != nil {
+		return err
+	}
+
+	if c.Logger.LogLevel >= LogDebug {
+		c.Log(LogDebug, fmt.Sprintf("COMMIT # dur: %.6f", getElapsedTime(c.txStart)))
+	}
+
+	return nil
+}
+
+// Rollback rolls back a transaction
+// Note: CASSANDRA doesn't support transactions
+func (c *DBConnector) Rollback() {
+	if c.DbOpts.Driver == CASSANDRA {
+		return
+	}
+	if c.DbOpts.DryRun {
+		c.Log(LogTrace, "skipping ROLLBACK request because of 'dry run' mode")
+
+		return
+	}
+	if c.tx == nil {
+		c.Exit("internal error: trying to call Rollback() w/o Begin()")
+	}
+
+	err := c.tx.Rollback()
+	c.Log(LogDebug, "ROLLBACK")
+	if err != nil {
+		c.Exit("DB rollback failed\nError: %s", err.Error())
 	}
 	c.tx = nil
 }
 
+// TxRetriesLimit returns the max amount of times a transaction is retried, --max-retries overrides --tx-max-retries
+func (o *DatabaseOpts) TxRetriesLimit() int {
+	if o.MaxRetries != nil {
+		return *o.MaxRetries
+	}
+
+	return o.TxMaxRetries
+}
+
+// Transact runs given function in a transaction and commits it, the whole transaction is retried up to --tx-max-retries times
+// with exponential backoff starting from --tx-backoff msec if the function or COMMIT fail with a transient error
+func (c *DBConnector) Transact(fn func() error) error {
+	backoff := time.Duration(c.DbOpts.TxBackoff) * time.Millisecond
+
+	c.transacting = true
+	defer func() { c.transacting = false }()
+
+	for attempt := 0; ; attempt++ {
+		c.Begin()
+
+		err := fn()
+		if err == nil {
+			err = c.commit()
+		} else if c.tx != nil {
+			c.Rollback()
+		}
+
+		if err == nil || !IsRetryableTxError(err) || attempt >= c.DbOpts.TxRetriesLimit() {
+			return err
+		}
+
+		c.Log(LogDebug, "retrying transaction after transient error: %v", err)
+
+		c.lock.Lock()
+		c.txRetries++
+		c.lock.Unlock()
+
+		time.Sleep(backoff << attempt)
+	}
+}
+
+// TakeTxRetries returns the amount of transactions retried after a transient error since the last call and resets it
+func (c *DBConnector) TakeTxRetries() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	n := c.txRetries
+	c.txRetries = 0
+
+	return n
+}
+
+// injectLatency delays the statement by --inject-latency msec
+func (c *DBConnector) injectLatency() {
+	if c.DbOpts.InjectLatency > 0 {
+		time.Sleep(time.Duration(c.DbOpts.InjectLatency) * time.Millisecond)
+	}
+}
+
+// dropSession kills a DB session of the pool on the server side (postgres and mysql) as if the network has dropped it,
+// and closes its connection on the client side, so the next statement has to connect again
+func (c *DBConnector) dropSession() bool {
+	// the pool may be busy with not yet closed rows, then the drop is skipped rather than blocking the statement
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conn, err := c.dbSess.Conn(ctx)
+	if err != nil {
+		return false
+	}
+	defer conn.Close() //nolint:errcheck
+
+	switch c.DbOpts.Driver {
+	case POSTGRES:
+		_, _ = conn.ExecContext(ctx, "SELECT pg_terminate_backend(pg_backend_pid())")
+	case MYSQL:
+		var id int64
+		if err = conn.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&id); err == nil {
+			_, _ = conn.ExecContext(ctx, fmt.Sprintf("KILL CONNECTION %d", id))
+		}
+	}
+
+	// driver.ErrBadConn makes database/sql close the connection instead of returning it to the pool
+	_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+
+	return true
+}
+
+// injectFault emulates an unreliable network in front of the DB session: it adds --inject-latency, kills a pooled
+// session with --inject-drop-rate probability and fails the statement with --inject-error-rate probability; errors are
+// injected only inside Transact() where the caller can retry, so that setup queries and non-retryable paths are unaffected
+func (c *DBConnector) injectFault() error {
+	c.injectLatency()
+
+	if c.DbOpts.InjectDropRate > 0 && c.tx == nil && c.dbSess != nil && rand.Float64()*100 < c.DbOpts.InjectDropRate && c.dropSession() { //nolint:gosec
+		c.lock.Lock()
+		c.injectedDrops++
+		c.lock.Unlock()
+	}
+
+	if c.DbOpts.InjectErrorRate > 0 && c.transacting && rand.Float64()*100 < c.DbOpts.InjectErrorRate { //nolint:gosec
+		c.lock.Lock()
+		c.injectedErrors++
+		c.lock.Unlock()
+
+		return ErrInjectedFault
+	}
+
+	return nil
+}
+
+// TakeInjectedFaults returns the amount of injected errors and connection drops since the last call and resets them
+func (c *DBConnector) TakeInjectedFaults() (errs int, drops int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	errs, drops = c.injectedErrors, c.injectedDrops
+	c.injectedErrors, c.injectedDrops = 0, 0
+
+	return errs, drops
+}
+
 // getElapsedTime returns elapsed time since startTime
 func getElapsedTime(prevTime time.Time) float64 {
 	return time.Since(prevTime).Seconds()
@@ -669,10 +1213,12 @@ func (c *DBConnector) Exec(format string, args ...interface{}) (sql.Result, erro
 		return result, nil
 	}
 
-	if c.tx == nil {
-		result, err = c.db().Exec(format, args...)
-	} else {
-		result, err = c.tx.Exec(format, args...)
+	if err = c.injectFault(); err == nil {
+		if c.tx == nil {
+			result, err = c.db().Exec(format, args...)
+		} else {
+			result, err = c.tx.Exec(format, args...)
+		}
 	}
 
 	if err != nil {
@@ -692,10 +1238,12 @@ func (c *DBConnector) Query(query string, args ...interface{}) (*sql.Rows, error
 	query = c.updatePlaceholders(query)
 	startTime := c.StatementEnter(query, args)
 
-	if c.tx == nil {
-		rows, err = c.db().Query(query, args...)
-	} else {
-		rows, err = c.tx.Query(query, args...)
+	if err = c.injectFault(); err == nil {
+		if c.tx == nil {
+			rows, err = c.db().Query(query, args...)
+		} else {
+			rows, err = c.tx.Query(query, args...)
+		}
 	}
 
 	if err != nil {
@@ -777,6 +1325,7 @@ func (c *DBConnector) queryRowAndScan(query string, allowEmpty bool, dest ...int
 
 	query = c.updatePlaceholders(query)
 	startTime := c.StatementEnter(query, nil)
+	c.injectLatency()
 
 	if c.tx == nil {
 		err = c.db().QueryRow(query).Scan(dest...)
@@ -918,6 +1467,11 @@ func (c *DBConnector) fetchRows(rows *sql.Rows, query string, args ...interface{
 		}
 
 		rawRows = append(rawRows, rawData)
+
+		if c.DbOpts.MaxRowsInMemory > 0 && len(rawRows) > c.DbOpts.MaxRowsInMemory {
+			c.Exit("DB query result exceeds %d rows limit: %s\nIncrease --max-rows-in-memory if the host has enough memory for larger results",
+				c.DbOpts.MaxRowsInMemory, query)
+		}
 	}
 
 	ret := DBRows{data: rawRows}
@@ -931,18 +1485,39 @@ func (c *DBConnector) SelectRaw(explain bool, query string, args ...interface{})
 	var err error
 	startTime := c.StatementEnter(query, args)
 
+	// the first plan of the test goes to the --explain-output file, the rest are logged
+	var planFile string
 	if explain {
-		query = c.addExplainPrefix(query)
+		if planFile = c.explainFile(); planFile != "" {
+			query = c.explainJSONPrefix() + query
+		} else {
+			query = c.addExplainPrefix(query)
+		}
 	}
 
-	if c.tx == nil {
-		rows, err = c.db().Query(query, args...)
-	} else {
+	c.injectLatency()
+
+	if c.tx != nil {
 		rows, err = c.tx.Query(query, args...)
+	} else if len(args) > 0 && c.UsePreparedStatements() {
+		rows, err = c.queryPrepared(query, args...)
+	} else {
+		rows, err = c.db().Query(query, args...)
+	}
+
+	if err != nil {
+		c.StatementExit("Query()", startTime, err, false, nil, query, args, nil, nil)
+		c.Exit("DB query failed: %s\nError: %s", query, err.Error())
 	}
 
 	defer rows.Close()
 
+	if planFile != "" {
+		c.saveExplain(rows, planFile, query)
+
+		return nil
+	}
+
 	if explain {
 		c.explain(rows, query, args...)
 
@@ -951,11 +1526,7 @@ func (c *DBConnector) SelectRaw(explain bool, query string, args ...interface{})
 
 	ret := c.fetchRows(rows, query, args...)
 
-	if err != nil {
-		c.Exit("DB query failed: %s\nError: %s", query, err.Error())
-	}
-
-	c.StatementExit("Query()", startTime, err, false, nil, query, args, ret, nil)
+	c.StatementExit("Query()", startTime, nil, false, nil, query, args, ret, nil)
 
 	return ret
 }
@@ -999,6 +1570,28 @@ func (c *DBConnector) Select(from string, what string, where string, orderBy str
 	return c.SelectRaw(explain, query, args...)
 }
 
+// fetchFirstClause returns standard SQL 'OFFSET n ROWS FETCH NEXT m ROWS ONLY' pagination clause, or LIMIT on the drivers not supporting it
+func fetchFirstClause(driver string, offset int, limit int) string {
+	switch driver {
+	case POSTGRES, MSSQL:
+		return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+	default:
+		return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+	}
+}
+
+// SelectFetchFirst executes a select query paginated by standard SQL OFFSET ... FETCH NEXT ... ROWS ONLY (LIMIT ... OFFSET on MySQL and SQLite),
+// orderBy is mandatory as MSSQL doesn't support OFFSET w/o ORDER BY
+func (c *DBConnector) SelectFetchFirst(from string, what string, where string, orderBy string, offset int, limit int, explain bool, args ...interface{}) *DBRows {
+	query := fmt.Sprintf("SELECT %s FROM %s", what, from)
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s", orderBy, fetchFirstClause(c.DbOpts.Driver, offset, limit))
+
+	return c.SelectRaw(explain, c.updatePlaceholders(query), args...)
+}
+
 // ExecOrExit executes a statement or exits
 func (c *DBConnector) ExecOrExit(format string, args ...interface{}) {
 	_, err := c.Exec(format, args...)
@@ -1007,6 +1600,44 @@ func (c *DBConnector) ExecOrExit(format string, args ...interface{}) {
 	}
 }
 
+// ExecSecretOrExit executes a statement carrying given secrets (e.g. a password in DDL, which can't have bind parameters)
+// or exits, the statement is logged and reported with the secrets masked
+func (c *DBConnector) ExecSecretOrExit(query string, secrets ...string) {
+	masked := query
+	for _, s := range secrets {
+		if s != "" {
+			masked = strings.ReplaceAll(masked, s, "******")
+		}
+	}
+
+	startTime := c.StatementEnter(masked)
+
+	if c.DbOpts.DryRun {
+		c.Log(LogTrace, "skipping the '"+masked+"' request because of 'dry run' mode")
+
+		return
+	}
+
+	var err error
+	if c.tx == nil {
+		_, err = c.db().Exec(query)
+	} else {
+		_, err = c.tx.Exec(query)
+	}
+
+	if err != nil {
+		msg := err.Error()
+		for _, s := range secrets {
+			if s != "" {
+				msg = strings.ReplaceAll(msg, s, "******")
+			}
+		}
+		c.Exit("DB exec failed: %s\nError: %s", masked, msg)
+	}
+
+	c.StatementExit("Exec()", startTime, nil, false, nil, masked, nil, nil, nil)
+}
+
 // QueryOrExit executes a query or exits
 func (c *DBConnector) QueryOrExit(format string, args ...interface{}) {
 	rows := c.QueryOrExitWithResult(format, args...)
@@ -1121,6 +1752,27 @@ func (c *DBConnector) DropIndex(indexName string) {
 	}
 }
 
+// DropTableIndex drops an index created by CreateIndex() for a given table and columns if it exists
+func (c *DBConnector) DropTableIndex(tableName string, columns string, id int) {
+	indexName := makeIndexName(tableName, columns, id)
+
+	switch c.DbOpts.Driver {
+	case CLICKHOUSE, CASSANDRA:
+		//
+	case MYSQL:
+		// MySQL has no DROP INDEX IF EXISTS
+		indexExists := false
+		c.QueryRowAndScan("SELECT EXISTS (SELECT 1 FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_NAME = '"+tableName+"' AND INDEX_NAME = '"+indexName+"')", &indexExists)
+		if indexExists {
+			c.ExecOrExit(fmt.Sprintf("DROP INDEX %s ON %s", indexName, tableName))
+		}
+	case MSSQL:
+		c.ExecOrExit(fmt.Sprintf("DROP INDEX IF EXISTS %s ON %s", indexName, tableName))
+	default:
+		c.ExecOrExit(fmt.Sprintf("DROP INDEX IF EXISTS %s", indexName))
+	}
+}
+
 // CreateSequence creates a sequence if it doesn't exist
 func (c *DBConnector) CreateSequence(sequenceName string) {
 	switch c.DbOpts.Driver {
@@ -1128,8 +1780,8 @@ func (c *DBConnector) CreateSequence(sequenceName string) {
 		c.ExecOrExit("CREATE SEQUENCE IF NOT EXISTS " + sequenceName)
 	case SQLITE:
 		if !c.TableExists(sequenceName) {
-			c.CreateTable(sequenceName, fmt.Sprintf("CREATE TABLE %s (value BIGINT NOT NULL, sequence_id INT NOT NULL); ALTER TABLE %s ADD INDEX %s_value (value);",
-				sequenceName, sequenceName, sequenceName))
+			c.CreateTable(sequenceName, fmt.Sprintf("CREATE TABLE %[1]s (value BIGINT NOT NULL, sequence_id INT NOT NULL); CREATE INDEX %[1]s_value ON %[1]s (value);",
+				sequenceName))
 			c.ExecOrExit(fmt.Sprintf("INSERT INTO %s (value, sequence_id) VALUES (1, 1)", sequenceName))
 		}
 	case MSSQL:
@@ -1186,6 +1838,32 @@ func (c *DBConnector) GetTableSizeMB(tableName string) (sizeMB int64) {
 	return sizeMB
 }
 
+// GetBlobStorage returns the raw size of the values of given binary column and the size the server stores them in,
+// the stored size is per column on postgres (compressed TOAST values) and per table elsewhere, -1 means the value is not observable
+func (c *DBConnector) GetBlobStorage(tableName string, column string) (rawBytes int64, storedBytes int64) {
+	switch c.DbOpts.Driver {
+	case POSTGRES:
+		rawBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT SUM(octet_length(%s))::bigint FROM %s", column, tableName))
+		storedBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT SUM(pg_column_size(%s))::bigint FROM %s", column, tableName))
+	case MYSQL:
+		rawBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT CAST(SUM(LENGTH(%s)) AS SIGNED) FROM %s", column, tableName))
+		// ALLOCATED_SIZE accounts the holes punched by InnoDB page compression, unlike FILE_SIZE
+		storedBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT ALLOCATED_SIZE FROM information_schema.INNODB_TABLESPACES WHERE NAME = CONCAT(DATABASE(), '/%s')", tableName))
+	case MSSQL:
+		rawBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT SUM(CAST(DATALENGTH(%s) AS bigint)) FROM %s", column, tableName))
+		storedBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT SUM(CAST(a.used_pages AS bigint)) * 8192 FROM sys.partitions p "+
+			"JOIN sys.allocation_units a ON a.container_id = p.partition_id WHERE p.object_id = OBJECT_ID('%s')", tableName))
+	case SQLITE:
+		rawBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT SUM(LENGTH(%s)) FROM %s", column, tableName))
+		// the dbstat virtual table is optional, see SQLITE_ENABLE_DBSTAT_VTAB
+		storedBytes = c.queryInt64OrNA(fmt.Sprintf("SELECT SUM(pgsize) FROM dbstat WHERE name = '%s'", tableName))
+	default:
+		return -1, -1
+	}
+
+	return rawBytes, storedBytes
+}
+
 // GetIndexesSizeMB returns the size of indexes of a table in MB
 func (c *DBConnector) GetIndexesSizeMB(tableName string) (sizeMB int64) {
 	switch c.DbOpts.Driver {
@@ -1203,6 +1881,141 @@ func (c *DBConnector) GetIndexesSizeMB(tableName string) (sizeMB int64) {
 	return sizeMB
 }
 
+// GetTableAndIndexSizes returns the size of a table data and the size of every its index in bytes, -1 data size means unsupported driver
+func (c *DBConnector) GetTableAndIndexSizes(tableName string) (dataBytes int64, indexBytes map[string]int64) {
+	var indexesQuery string
+
+	switch c.DbOpts.Driver {
+	case POSTGRES:
+		c.QueryRowAndScanAllowEmpty(fmt.Sprintf("SELECT pg_relation_size('%s')", tableName), &dataBytes)
+		indexesQuery = fmt.Sprintf("SELECT indexrelname, pg_relation_size(indexrelid) FROM pg_stat_user_indexes WHERE relname = '%s'", tableName)
+	case MYSQL:
+		c.QueryRowAndScanAllowEmpty(fmt.Sprintf("SELECT Data_length FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = '%s'",
+			tableName), &dataBytes)
+		indexesQuery = fmt.Sprintf("SELECT index_name, stat_value * @@innodb_page_size FROM mysql.innodb_index_stats "+
+			"WHERE database_name = DATABASE() AND table_name = '%s' AND stat_name = 'size' AND index_name != 'PRIMARY'", tableName)
+	case MSSQL:
+		// heap (0) and clustered index (1) pages are the table data
+		c.QueryRowAndScanAllowEmpty(fmt.Sprintf("SELECT COALESCE(SUM(used_page_count), 0) * 8192 FROM sys.dm_db_partition_stats "+
+			"WHERE object_id = OBJECT_ID('%s') AND index_id IN (0, 1)", tableName), &dataBytes)
+		indexesQuery = fmt.Sprintf("SELECT i.name, SUM(s.used_page_count) * 8192 FROM sys.dm_db_partition_stats s "+
+			"JOIN sys.indexes i ON s.object_id = i.object_id AND s.index_id = i.index_id "+
+			"WHERE s.object_id = OBJECT_ID('%s') AND s.index_id > 1 GROUP BY i.name", tableName)
+	case CLICKHOUSE:
+		c.QueryRowAndScanAllowEmpty(fmt.Sprintf("SELECT sum(bytes_on_disk) FROM system.parts WHERE active AND table = '%s'", tableName), &dataBytes)
+		indexesQuery = fmt.Sprintf("SELECT 'primary', sum(primary_key_bytes_in_memory) FROM system.parts WHERE active AND table = '%s'", tableName)
+	default:
+		return -1, nil
+	}
+
+	rows, err := c.Query(indexesQuery)
+	if err != nil {
+		c.Exit("can't get indexes size of table %s: %s", tableName, err.Error())
+	}
+	defer rows.Close()
+
+	indexBytes = make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var size int64
+		if err = rows.Scan(&name, &size); err != nil {
+			c.Exit("can't get indexes size of table %s: %s", tableName, err.Error())
+		}
+		indexBytes[name] = size
+	}
+
+	return dataBytes, indexBytes
+}
+
+// queryInt64OrNA returns the single int64 value of given query or -1 if the value is not available
+func (c *DBConnector) queryInt64OrNA(query string) int64 {
+	rows, err := c.Query(query)
+	if err != nil {
+		return -1
+	}
+	defer rows.Close()
+
+	var v sql.NullInt64
+	if !rows.Next() || rows.Scan(&v) != nil || !v.Valid {
+		return -1
+	}
+
+	return v.Int64
+}
+
+// GetPreparedStatementsFootprint returns the amount of SQL-level prepared statements of the current session and the session
+// memory as the server sees it, -1 means the value is not observable (unsupported driver, old server version or no privileges)
+func (c *DBConnector) GetPreparedStatementsFootprint() (count int64, memoryBytes int64) {
+	switch c.DbOpts.Driver {
+	case POSTGRES:
+		count = c.queryInt64OrNA("SELECT COUNT(*) FROM pg_prepared_statements")
+
+		// postgres 14+, superuser or pg_read_all_stats role
+		var visible bool
+		c.QueryRowAndScan("SELECT CASE WHEN to_regclass('pg_catalog.pg_backend_memory_contexts') IS NULL THEN false "+
+			"ELSE has_table_privilege('pg_catalog.pg_backend_memory_contexts', 'SELECT') END", &visible)
+		if !visible {
+			return count, -1
+		}
+
+		return count, c.queryInt64OrNA("SELECT SUM(used_bytes) FROM pg_backend_memory_contexts")
+	case MYSQL:
+		// mysql 8.0.16+ with performance_schema enabled
+		count = c.queryInt64OrNA("SELECT COUNT(*) FROM performance_schema.prepared_statements_instances WHERE OWNER_THREAD_ID = PS_CURRENT_THREAD_ID()")
+		memoryBytes = c.queryInt64OrNA("SELECT SUM(CURRENT_NUMBER_OF_BYTES_USED) FROM performance_schema.memory_summary_by_thread_by_event_name " +
+			"WHERE THREAD_ID = PS_CURRENT_THREAD_ID()")
+
+		return count, memoryBytes
+	default:
+		return -1, -1
+	}
+}
+
+// GetWALBytes returns the amount of bytes written to the write-ahead log (redo log on MySQL, transaction log on MSSQL)
+// since the server start, -1 means the value is not observable (unsupported driver, replica or no privileges)
+func (c *DBConnector) GetWALBytes() int64 {
+	switch c.DbOpts.Driver {
+	case POSTGRES:
+		return c.queryInt64OrNA("SELECT (pg_current_wal_lsn() - '0/0'::pg_lsn)::bigint")
+	case MYSQL:
+		written := c.queryInt64OrNA("SELECT CAST(VARIABLE_VALUE AS SIGNED) FROM performance_schema.global_status WHERE VARIABLE_NAME = 'Innodb_os_log_written'")
+		if written < 0 {
+			// mariadb and mysql w/o performance_schema
+			written = c.queryInt64OrNA("SELECT CAST(VARIABLE_VALUE AS SIGNED) FROM information_schema.GLOBAL_STATUS WHERE VARIABLE_NAME = 'INNODB_OS_LOG_WRITTEN'")
+		}
+
+		return written
+	case MSSQL:
+		// the per-second counters are cumulative raw values in the DMV
+		return c.queryInt64OrNA("SELECT cntr_value FROM sys.dm_os_performance_counters WHERE counter_name LIKE 'Log Bytes Flushed/sec%' AND instance_name = DB_NAME()")
+	default:
+		return -1
+	}
+}
+
+// GetConnectionsLimit returns the max amount of client connections the server allows and the amount of the connections
+// currently in use, -1 means the value is not observable (unsupported driver or no privileges)
+func (c *DBConnector) GetConnectionsLimit() (maxConns int64, usedConns int64) {
+	switch c.DbOpts.Driver {
+	case POSTGRES:
+		// the reserved connections are not available for regular users
+		maxConns = c.queryInt64OrNA("SELECT current_setting('max_connections')::bigint - current_setting('superuser_reserved_connections')::bigint")
+		usedConns = c.queryInt64OrNA("SELECT COUNT(*) FROM pg_stat_activity WHERE backend_type = 'client backend'")
+	case MYSQL:
+		maxConns = c.queryInt64OrNA("SELECT @@max_connections")
+		usedConns = c.queryInt64OrNA("SELECT CAST(VARIABLE_VALUE AS SIGNED) FROM performance_schema.global_status WHERE VARIABLE_NAME = 'Threads_connected'")
+	case MSSQL:
+		// 0 'user connections' means the server maximum
+		maxConns = c.queryInt64OrNA("SELECT CASE WHEN CAST(value_in_use AS bigint) = 0 THEN @@MAX_CONNECTIONS ELSE CAST(value_in_use AS bigint) END " +
+			"FROM sys.configurations WHERE name = 'user connections'")
+		usedConns = c.queryInt64OrNA("SELECT COUNT(*) FROM sys.dm_exec_sessions WHERE is_user_process = 1")
+	default:
+		return -1, -1
+	}
+
+	return maxConns, usedConns
+}
+
 // GetUUIDs returns UUIDs from a table
 func (c *DBConnector) GetUUIDs(tableName, where string) (uuids []string) {
 	rows := c.dbQueryIfExist("uuid", tableName, where)
@@ -1245,7 +2058,11 @@ func (c *DBConnector) GetNextVal(sequenceName string) (nextVal uint64) {
 	switch c.DbOpts.Driver {
 	case POSTGRES, MSSQL, MYSQL:
 		var query string
-		if c.DbOpts.Driver == POSTGRES {
+		if c.DbOpts.Dialect == COCKROACH {
+			// a sequence is a single hot range in CockroachDB, unique_rowid() is its native unique id generator,
+			// the values are unique and roughly ordered, but not contiguous
+			query = "SELECT unique_rowid()"
+		} else if c.DbOpts.Driver == POSTGRES {
 			query = "SELECT NEXTVAL('" + sequenceName + "')"
 		} else if c.DbOpts.Driver == MYSQL {
 			query = "SELECT NEXTVAL(" + sequenceName + ")"
@@ -1268,11 +2085,8 @@ func (c *DBConnector) GetNextVal(sequenceName string) (nextVal uint64) {
 			c.Log(LogDebug, fmt.Sprintf("%s = %d", query, nextVal))
 		}
 	case SQLITE:
-		var value int64
-		c.Begin()
-		c.QueryRowAndScan("SELECT value FROM "+sequenceName+" WHERE sequence_id = 1 FOR UPDATE", &value)
-		c.ExecOrExit(fmt.Sprintf("UPDATE %s SET value = %d WHERE sequence_id = 1", sequenceName, value+1))
-		c.Commit()
+		// a single statement is atomic, so the concurrent callers never get the same value
+		c.QueryRowAndScan(fmt.Sprintf("UPDATE %s SET value = value + 1 WHERE sequence_id = 1 RETURNING value - 1", sequenceName), &nextVal)
 	default:
 		c.Exit("unknown driver: '%v', supported drivers are: postgres|sqlite|mysql|mssql", c.DbOpts.Driver)
 	}
@@ -1670,6 +2484,8 @@ func GetDatabases() []DBType {
 	ret = append(ret, DBType{Driver: CLICKHOUSE, Symbol: "C", Name: "ClickHouse"})
 	// "A" is used as the latest symbol of the "Cassandra" due to duplicate with ClickHouse "C"
 	ret = append(ret, DBType{Driver: CASSANDRA, Symbol: "A", Name: "Cassandra"})
+	// "R" is used as the second symbol of the "CockroachDB" due to duplicate with ClickHouse and Cassandra
+	ret = append(ret, DBType{Driver: COCKROACH, Symbol: "R", Name: "CockroachDB"})
 
 	return ret
 }
@@ -0,0 +1,139 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CancelStats is the outcome of a single query cancellation
+type CancelStats struct {
+	Returned time.Duration // time from the cancellation till the query call returned to the client
+	Stopped  time.Duration // time from the cancellation till the server stopped the query, -1 if the server state is not observable
+	Leaked   bool          // the server was still running the query when the timeout expired
+}
+
+// sessionIDQuery returns the query returning the server session id of the current connection
+func sessionIDQuery(driver string) string {
+	switch driver {
+	case POSTGRES:
+		return "SELECT pg_backend_pid()"
+	case MYSQL:
+		return "SELECT CONNECTION_ID()"
+	case MSSQL:
+		return "SELECT @@SPID"
+	default:
+		return ""
+	}
+}
+
+// sessionActiveQuery returns the query returning 1 while the given server session executes a statement
+func sessionActiveQuery(driver string, sessionID int64) string {
+	switch driver {
+	case POSTGRES:
+		return fmt.Sprintf("SELECT COUNT(*) FROM pg_stat_activity WHERE pid = %d AND state = 'active'", sessionID)
+	case MYSQL:
+		return fmt.Sprintf("SELECT COUNT(*) FROM information_schema.PROCESSLIST WHERE ID = %d AND COMMAND = 'Query'", sessionID)
+	case MSSQL:
+		return fmt.Sprintf("SELECT COUNT(*) FROM sys.dm_exec_requests WHERE session_id = %d", sessionID)
+	default:
+		return ""
+	}
+}
+
+// CancelQuery runs the query on a dedicated session, cancels its context after given delay and then polls the server
+// on another session until the query is gone or the timeout expires, so it tells whether the client side cancellation
+// really reaches the server. The connector must allow at least 2 open connections (see --maxopencons)
+func (c *DBConnector) CancelQuery(query string, delay time.Duration, timeout time.Duration) (CancelStats, error) {
+	stats := CancelStats{Stopped: -1}
+
+	db := c.db()
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return stats, fmt.Errorf("can't open the query session: %w", err)
+	}
+	defer conn.Close()
+
+	var watch *sql.Conn
+	var sessionID int64
+	if q := sessionIDQuery(c.DbOpts.Driver); q != "" {
+		if err = conn.QueryRowContext(context.Background(), q).Scan(&sessionID); err != nil {
+			return stats, fmt.Errorf("can't get the session id: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		watch, err = db.Conn(ctx)
+		cancel()
+		if err != nil {
+			return stats, fmt.Errorf("can't open the watching session: %w", err)
+		}
+		defer watch.Close()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		rows, err := conn.QueryContext(ctx, c.updatePlaceholders(query))
+		if err == nil {
+			for rows.Next() { //nolint:revive
+			}
+			err = rows.Err()
+			rows.Close()
+		}
+		done <- err
+	}()
+
+	select {
+	case err = <-done:
+		if err == nil {
+			err = errors.New("the query completed before the cancellation, increase its cost or reduce the delay")
+		}
+
+		return stats, err
+	case <-time.After(delay):
+	}
+
+	cancelled := time.Now()
+	cancel()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		stats.Returned = time.Since(cancelled)
+	case <-timer.C:
+		stats.Returned = timeout
+	}
+
+	if watch == nil {
+		return stats, nil
+	}
+
+	activeQuery := sessionActiveQuery(c.DbOpts.Driver, sessionID)
+	for {
+		var active int64
+		if err = watch.QueryRowContext(context.Background(), activeQuery).Scan(&active); err != nil {
+			return stats, fmt.Errorf("can't get the session state: %w", err)
+		}
+
+		if active == 0 {
+			stats.Stopped = time.Since(cancelled)
+
+			return stats, nil
+		}
+
+		if time.Since(cancelled) > timeout {
+			stats.Leaked = true
+
+			return stats, nil
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
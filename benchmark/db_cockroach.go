@@ -0,0 +1,59 @@
+package benchmark
+
+import (
+	"net/url"
+	"strings"
+)
+
+// cockroachPort is the default CockroachDB SQL port
+const cockroachPort = "26257"
+
+// GetDialectName returns the SQL dialect of the database given driver and dsn point to. CockroachDB speaks the postgres
+// wire protocol, so it's either requested explicitly by the COCKROACH driver name or detected from a postgres dsn by the
+// 'cockroachdb://' scheme or the default CockroachDB port
+func GetDialectName(driver string, dsn string) string {
+	switch driver {
+	case COCKROACH:
+		return COCKROACH
+	case POSTGRES:
+		if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+			if u.Scheme == "cockroachdb" || u.Port() == cockroachPort {
+				return COCKROACH
+			}
+
+			return POSTGRES
+		}
+
+		// key=value dsn, e.g. 'host=127.0.0.1 port=26257 user=root'
+		for _, kv := range strings.Fields(dsn) {
+			if kv == "port="+cockroachPort {
+				return COCKROACH
+			}
+		}
+	}
+
+	return driver
+}
+
+// ResolveDialect detects the dialect of the database, see GetDialectName(), the CockroachDB one is served by the postgres
+// driver, so the tests and the connector take the postgres code paths unless they check the dialect explicitly
+func (o *DatabaseOpts) ResolveDialect() {
+	o.Dialect = GetDialectName(o.Driver, o.Dsn)
+	if o.Dialect != COCKROACH {
+		return
+	}
+
+	o.Driver = POSTGRES
+	if strings.HasPrefix(o.Dsn, "cockroachdb://") {
+		o.Dsn = "postgresql://" + strings.TrimPrefix(o.Dsn, "cockroachdb://")
+	}
+}
+
+// DialectName returns the SQL dialect of the database, it's the driver name unless ResolveDialect() detected another one
+func (o *DatabaseOpts) DialectName() string {
+	if o.Dialect != "" {
+		return o.Dialect
+	}
+
+	return o.Driver
+}
@@ -123,8 +123,17 @@ func (c *DBConnector) DBRConnect() {
 		driver = "sqlite3"
 	}
 
+	dsn, err := withSessionTimezone(c.DbOpts.Driver, c.DbOpts.Dsn, c.DbOpts.SessionTimezone)
+	if err != nil {
+		c.Exit(err.Error())
+	}
+
 	for r := 0; !connected && r < c.RetryAttempts; r++ {
-		conn, err = dbr.Open(driver, c.DbOpts.Dsn, &DBREventReceiver{connector: c, exitOnError: true, queries: []DBRQuery{}})
+		conn, err = dbr.Open(driver, dsn, &DBREventReceiver{connector: c, exitOnError: true, queries: []DBRQuery{}})
+		if err == nil && len(c.DbOpts.InitSQL) > 0 {
+			conn.DB.Close()
+			conn.DB, err = openDB(driver, dsn, c.DbOpts.InitSQL)
+		}
 
 		if err == nil {
 			err = c.Ping()
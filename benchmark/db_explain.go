@@ -0,0 +1,78 @@
+package benchmark
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// explainCapture is the state of the --explain-output plans capture, see CaptureExplain()
+var explainCapture struct {
+	lock  sync.Mutex
+	dir   string
+	test  string
+	saved map[string]bool // tests whose plan has already been saved
+}
+
+// CaptureExplain makes the first plan explained while given test runs saved to the dir/<test>.json file instead of
+// being logged, an empty dir turns the capture off
+func CaptureExplain(dir string, test string) {
+	explainCapture.lock.Lock()
+	defer explainCapture.lock.Unlock()
+
+	explainCapture.dir = dir
+	explainCapture.test = test
+	if explainCapture.saved == nil {
+		explainCapture.saved = make(map[string]bool)
+	}
+}
+
+// explainJSONPrefix returns the 'explain' prefix making the query return its plan as a JSON document, empty string if
+// the database can't do it
+func (c *DBConnector) explainJSONPrefix() string {
+	switch c.DbOpts.DialectName() {
+	case POSTGRES:
+		return "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "
+	case MYSQL:
+		return "EXPLAIN FORMAT=JSON "
+	default:
+		return ""
+	}
+}
+
+// explainFile returns the file the plan of the query being explained goes to, the plan of every test is saved once,
+// so the file is returned to the first caller only
+func (c *DBConnector) explainFile() string {
+	if c.explainJSONPrefix() == "" {
+		return ""
+	}
+
+	explainCapture.lock.Lock()
+	defer explainCapture.lock.Unlock()
+
+	if explainCapture.dir == "" || explainCapture.test == "" || explainCapture.saved[explainCapture.test] {
+		return ""
+	}
+	explainCapture.saved[explainCapture.test] = true
+
+	return filepath.Join(explainCapture.dir, explainCapture.test+".json")
+}
+
+// saveExplain writes the JSON plan returned by the explained query to given file
+func (c *DBConnector) saveExplain(rows *sql.Rows, path string, query string) {
+	var plan string
+	if rows.Next() {
+		if err := rows.Scan(&plan); err != nil {
+			c.Exit("DB query result scan failed: %s\nError: %s", query, err.Error())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		c.Exit("DB query failed: %s\nError: %s", query, err.Error())
+	}
+
+	if err := os.WriteFile(path, []byte(plan+"\n"), 0o644); err != nil { //nolint:gosec
+		c.Exit("can't write --explain-output file: %v", err)
+	}
+	c.Log(LogInfo, "query plan saved to %s", path)
+}
@@ -0,0 +1,141 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// pgBouncerDetectRounds is the amount of backend pid samples taken by DetectPgBouncerMode()
+const pgBouncerDetectRounds = 10
+
+// PgPoolerFeatures are the postgres client features which behave differently behind PgBouncer in transaction or statement
+// pool mode (see the 'Feature matrix for pooling modes' section of the PgBouncer docs), see ProbePgPoolerFeature()
+var PgPoolerFeatures = []string{"simple", "extended", "transaction", "sql-prepare", "session-set"}
+
+// backendPid returns the postgres backend pid serving the current statement of given client connection
+func backendPid(ctx context.Context, conn *sql.Conn) (int64, error) {
+	var pid int64
+	err := conn.QueryRowContext(ctx, "SELECT pg_backend_pid()").Scan(&pid)
+
+	return pid, err
+}
+
+// DetectPgBouncerMode detects the pool mode of the PgBouncer the connector's dsn points to. In transaction and statement
+// modes the server connection is not pinned to the client one, so two concurrent client sessions may share a backend and
+// a single client session may hop between backends; statement mode also rejects multi-statement transactions.
+// A direct connection is reported as PgBouncerSession, since it behaves the same way
+func (c *DBConnector) DetectPgBouncerMode() (string, error) {
+	if c.DbOpts.Driver != POSTGRES {
+		return "", fmt.Errorf("PgBouncer mode detection is supported for postgres only")
+	}
+
+	ctx := context.Background()
+	db := c.db()
+
+	a, err := db.Conn(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer a.Close()
+
+	b, err := db.Conn(ctx)
+	if err != nil {
+		return "", fmt.Errorf("can't open the second client session (--maxopencons must be at least 2): %w", err)
+	}
+	defer b.Close()
+
+	pooled := false
+	for i := 0; i < pgBouncerDetectRounds && !pooled; i++ {
+		var pidA1, pidB, pidA2 int64
+		if pidA1, err = backendPid(ctx, a); err == nil {
+			if pidB, err = backendPid(ctx, b); err == nil {
+				pidA2, err = backendPid(ctx, a)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+		pooled = pidA1 == pidB || pidA1 != pidA2
+	}
+
+	if !pooled {
+		return PgBouncerSession, nil
+	}
+
+	tx, err := a.BeginTx(ctx, nil)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, "SELECT 1")
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			tx.Rollback() //nolint:errcheck
+		}
+	}
+	if err != nil {
+		return PgBouncerStatement, nil //nolint:nilerr
+	}
+
+	return PgBouncerTransaction, nil
+}
+
+// ProbePgPoolerFeature runs a single round of given PgPoolerFeatures feature on a client connection of the pool and returns
+// an error if the feature doesn't work, seq makes the round names unique across the concurrent callers
+func (c *DBConnector) ProbePgPoolerFeature(feature string, seq int) error {
+	ctx := context.Background()
+
+	conn, err := c.db().Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch feature {
+	case "simple":
+		_, err = conn.ExecContext(ctx, "SELECT 1")
+	case "extended":
+		var v int
+		err = conn.QueryRowContext(ctx, "SELECT $1::int", seq).Scan(&v)
+	case "transaction":
+		var tx *sql.Tx
+		if tx, err = conn.BeginTx(ctx, nil); err != nil {
+			break
+		}
+		if _, err = tx.ExecContext(ctx, "SELECT 1"); err == nil {
+			_, err = tx.ExecContext(ctx, "SELECT 2")
+		}
+		if err != nil {
+			tx.Rollback() //nolint:errcheck
+
+			break
+		}
+		err = tx.Commit()
+	case "sql-prepare":
+		name := fmt.Sprintf("perfkit_probe_%d", seq)
+		if _, err = conn.ExecContext(ctx, fmt.Sprintf("PREPARE %s AS SELECT 1", name)); err != nil {
+			break
+		}
+		if _, err = conn.ExecContext(ctx, "EXECUTE "+name); err == nil {
+			_, err = conn.ExecContext(ctx, "DEALLOCATE "+name)
+		}
+	case "session-set":
+		want := fmt.Sprintf("perfkit-%d", seq)
+		if _, err = conn.ExecContext(ctx, fmt.Sprintf("SET application_name = '%s'", want)); err != nil {
+			break
+		}
+		var got string
+		if err = conn.QueryRowContext(ctx, "SELECT current_setting('application_name')").Scan(&got); err == nil && got != want {
+			err = errors.New("session state is lost between statements")
+		}
+		conn.ExecContext(ctx, "RESET application_name") //nolint:errcheck
+	default:
+		err = fmt.Errorf("unknown pooler feature: '%s'", feature)
+	}
+
+	if err != nil {
+		c.Log(LogDebug, "pooler feature '%s' failed: %v", feature, err)
+	}
+
+	return err
+}
@@ -0,0 +1,156 @@
+package benchmark
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PreparedPool is a small pool of DB connections shared by many workers, like the pool of an application server.
+// Prepared statements are bound to the connection they were prepared on, so every pooled connection keeps its own
+// statements cache and a statement is prepared again whenever it runs on a connection which hasn't seen it yet
+type PreparedPool struct {
+	c     *DBConnector
+	conns chan *pooledConn
+
+	lock  sync.Mutex
+	stats PreparedPoolStats
+}
+
+// PreparedPoolStats is the PreparedPool activity since the last TakeStats() call
+type PreparedPoolStats struct {
+	Prepares int           // amount of statements prepared, i.e. the statements cache misses
+	Executes int           // amount of statements executed
+	Wait     time.Duration // time spent waiting for a free pooled connection
+}
+
+// pooledConn is a pinned DB session with its own prepared statements cache
+type pooledConn struct {
+	conn  *sql.Conn
+	stmts map[string]*sql.Stmt
+}
+
+// NewPreparedPool opens a separate pool of given amount of connections to the connector's database
+func (c *DBConnector) NewPreparedPool(size int) (*PreparedPool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid pool size: %d", size)
+	}
+
+	opts := *c.DbOpts
+	opts.MaxOpenConns = size
+	opts.ConnPerWorker = false
+	opts.PoolAcquireTimeout = 0
+
+	// the connector doesn't go to the connections pool, it's closed by PreparedPool.Close()
+	p := &PreparedPool{
+		c: &DBConnector{
+			Logger:        c.Logger,
+			DbOpts:        &opts,
+			RetryAttempts: c.RetryAttempts,
+			WorkerID:      c.WorkerID,
+			logLevel:      c.logLevel,
+		},
+		conns: make(chan *pooledConn, size),
+	}
+	p.c.Connect()
+
+	for i := 0; i < size; i++ {
+		conn, err := p.c.dbSess.Conn(context.Background())
+		if err != nil {
+			p.Close()
+
+			return nil, fmt.Errorf("can't open pooled connection #%d: %w", i+1, err)
+		}
+		p.conns <- &pooledConn{conn: conn, stmts: make(map[string]*sql.Stmt)}
+	}
+
+	return p, nil
+}
+
+// acquire waits for a free pooled connection
+func (p *PreparedPool) acquire() *pooledConn {
+	select {
+	case pc := <-p.conns:
+		return pc
+	default:
+	}
+
+	start := time.Now()
+	pc := <-p.conns
+
+	p.lock.Lock()
+	p.stats.Wait += time.Since(start)
+	p.lock.Unlock()
+
+	return pc
+}
+
+// Query executes the query on a free pooled connection preparing it first if the connection hasn't seen it yet,
+// it returns the amount of fetched rows
+func (p *PreparedPool) Query(query string, args ...interface{}) (int, error) {
+	pc := p.acquire()
+	defer func() { p.conns <- pc }()
+
+	query = p.c.updatePlaceholders(query)
+
+	prepared := false
+	stmt, exists := pc.stmts[query]
+	if !exists {
+		var err error
+		if stmt, err = pc.conn.PrepareContext(context.Background(), query); err != nil {
+			return 0, fmt.Errorf("prepare failed: %w", err)
+		}
+		pc.stmts[query] = stmt
+		prepared = true
+	}
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+
+	p.lock.Lock()
+	p.stats.Executes++
+	if prepared {
+		p.stats.Prepares++
+	}
+	p.lock.Unlock()
+
+	return n, rows.Err()
+}
+
+// TakeStats returns the pool activity since the last call and resets it
+func (p *PreparedPool) TakeStats() PreparedPoolStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	stats := p.stats
+	p.stats = PreparedPoolStats{}
+
+	return stats
+}
+
+// Close closes all the prepared statements and the pooled connections
+func (p *PreparedPool) Close() {
+	for {
+		select {
+		case pc := <-p.conns:
+			for _, stmt := range pc.stmts {
+				stmt.Close()
+			}
+			pc.conn.Close()
+		default:
+			p.c.Close()
+
+			return
+		}
+	}
+}
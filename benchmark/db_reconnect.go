@@ -0,0 +1,112 @@
+package benchmark
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// reconnects is the amount of lost DB connections re-established since the process start, see TakeReconnects()
+var reconnects uint64
+
+// loopsRunning is the amount of worker loops which recover a lost connection at the moment, see Benchmark.runWorker()
+var loopsRunning int32
+
+// TakeReconnects returns the amount of lost DB connections re-established since the last call and resets it
+func TakeReconnects() uint64 {
+	return atomic.SwapUint64(&reconnects, 0)
+}
+
+// connectionLost is the panic value of DBConnector.Exit() failing on a lost connection inside a worker loop with
+// --reconnect-on-loss, it is recovered by Benchmark.runWorker()
+type connectionLost struct {
+	c   *DBConnector
+	err error
+	msg string // the Exit() message, it's printed if the connection can't be re-established
+}
+
+// lostConnection returns the error the connector has lost its connection with if the worker loop can recover it
+func (c *DBConnector) lostConnection() error {
+	if c.DbOpts == nil || c.DbOpts.ReconnectOnLoss <= 0 || atomic.LoadInt32(&loopsRunning) == 0 {
+		return nil
+	}
+
+	c.lock.Lock()
+	err := c.lastErr
+	c.lastErr = nil
+	c.lock.Unlock()
+
+	if !IsConnectionLost(err) {
+		return nil
+	}
+
+	return err
+}
+
+// reconnect waits up to --reconnect-on-loss seconds for the database to come back, the broken transaction is rolled back,
+// the pooled connections are re-established by database/sql on demand
+func (l *connectionLost) reconnect() {
+	c := l.c
+	c.Log(LogWarn, "DB connection lost: %v, reconnecting", l.err)
+
+	// the rollback fails on the lost connection anyway, but it returns the connection to the database/sql pool,
+	// which discards it as broken
+	if c.tx != nil {
+		_ = c.tx.Rollback()
+		c.tx = nil
+	}
+
+	deadline := time.Now().Add(time.Duration(c.DbOpts.ReconnectOnLoss) * time.Second)
+	for {
+		if err := c.Ping(); err == nil {
+			atomic.AddUint64(&reconnects, 1)
+			c.Log(LogWarn, "DB connection re-established")
+
+			return
+		}
+
+		if time.Now().After(deadline) {
+			c.exit(fmt.Sprintf("%s\n(the connection can't be re-established in %d sec)", l.msg, c.DbOpts.ReconnectOnLoss))
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// tryWorker runs a single worker loop and returns the lost connection if the loop has failed on it
+func (b *Benchmark) tryWorker(id int) (loops int, lost *connectionLost) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if lost, ok = r.(*connectionLost); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	return b.Worker(id), nil
+}
+
+// runWorker runs a single worker loop, with --reconnect-on-loss a loop failed on a lost connection waits for the
+// connection to be re-established and then it is retried once
+func (b *Benchmark) runWorker(id int) int {
+	if !b.ReconnectOnLoss {
+		return b.Worker(id)
+	}
+
+	atomic.AddInt32(&loopsRunning, 1)
+	defer atomic.AddInt32(&loopsRunning, -1)
+
+	loops, lost := b.tryWorker(id)
+	if lost == nil {
+		return loops
+	}
+
+	lost.reconnect()
+
+	if loops, lost = b.tryWorker(id); lost != nil {
+		lost.c.exit(lost.msg)
+	}
+
+	return loops
+}
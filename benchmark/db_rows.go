@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type dbRow []interface{}
@@ -119,6 +120,46 @@ func (r *DBRows) Next() bool {
 	return false
 }
 
+// Len returns number of fetched rows (nil DBRows is treated as empty)
+func (r *DBRows) Len() int {
+	if r == nil {
+		return 0
+	}
+
+	return len(r.data)
+}
+
+// Bytes estimates the amount of bytes the fetched rows took on the wire: the length of strings and binary values and
+// the native size of numbers and timestamps, protocol overhead is not counted (nil DBRows is treated as empty)
+func (r *DBRows) Bytes() int64 {
+	if r == nil {
+		return 0
+	}
+
+	var ret int64
+	for _, row := range r.data {
+		for _, v := range row {
+			switch val := v.(type) {
+			case nil:
+			case []byte:
+				ret += int64(len(val))
+			case string:
+				ret += int64(len(val))
+			case bool:
+				ret++
+			case int32, uint32, float32:
+				ret += 4
+			case int64, uint64, float64, int, uint, time.Time:
+				ret += 8
+			default:
+				ret += int64(len(fmt.Sprint(val)))
+			}
+		}
+	}
+
+	return ret
+}
+
 // Close implements sql.Rows interface for DBRows struct (used in tests)
 func (r *DBRows) Close() error {
 	return nil
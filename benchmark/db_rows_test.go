@@ -2,6 +2,7 @@ package benchmark
 
 import (
 	"testing"
+	"time"
 )
 
 // TestScanWithValidData tests Scan() function
@@ -52,6 +53,24 @@ func TestNextWithRemainingData(t *testing.T) {
 	}
 }
 
+// TestLen tests Len() function
+func TestLen(t *testing.T) {
+	rows := &DBRows{
+		data: []dbRow{
+			{"test", 1, true},
+			{"test2", 2, false},
+		},
+	}
+	if rows.Len() != 2 {
+		t.Errorf("Len() error, expected 2 but got %d", rows.Len())
+	}
+
+	var empty *DBRows
+	if empty.Len() != 0 {
+		t.Errorf("Len() error, expected 0 for nil rows but got %d", empty.Len())
+	}
+}
+
 // TestNextWithoutRemainingData tests Next() function
 func TestNextWithoutRemainingData(t *testing.T) {
 	rows := &DBRows{
@@ -100,3 +119,22 @@ func TestDumpWithSingleRow(t *testing.T) {
 		t.Errorf("Dump() error, dump is empty")
 	}
 }
+
+// TestBytes tests Bytes() function
+func TestBytes(t *testing.T) {
+	rows := &DBRows{
+		data: []dbRow{
+			{"test", []byte{1, 2, 3}, int64(1), true, nil},
+			{"", []byte{}, 1.5, false, time.Now()},
+		},
+	}
+
+	if got, want := rows.Bytes(), int64(4+3+8+1+0+0+0+8+1+8); got != want {
+		t.Errorf("Bytes() got = %d, want %d", got, want)
+	}
+
+	var empty *DBRows
+	if got := empty.Bytes(); got != 0 {
+		t.Errorf("Bytes() of nil rows got = %d, want 0", got)
+	}
+}
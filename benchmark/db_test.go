@@ -0,0 +1,402 @@
+package benchmark
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestOpenDBWithInitSQL(t *testing.T) {
+	db, err := openDB("sqlite3", "file::memory:", []string{"CREATE TEMP TABLE init_marker (x INT)", "INSERT INTO init_marker VALUES (42)"})
+	if err != nil {
+		t.Fatalf("openDB() error = %v", err)
+	}
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	var x int
+	if err = db.QueryRow("SELECT x FROM init_marker").Scan(&x); err != nil {
+		t.Fatalf("init SQL has not been executed on the new connection: %v", err)
+	}
+	if x != 42 {
+		t.Errorf("init_marker got = %d, want 42", x)
+	}
+
+	broken, err := openDB("sqlite3", "file::memory:", []string{"SELECT * FROM no_such_table"})
+	if err != nil {
+		t.Fatalf("openDB() error = %v", err)
+	}
+	defer broken.Close()
+
+	if err = broken.Ping(); err == nil || !strings.Contains(err.Error(), "init SQL") {
+		t.Errorf("Ping() expected init SQL error, got %v", err)
+	}
+}
+
+func TestFetchFirstClause(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   string
+	}{
+		{POSTGRES, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{MSSQL, "OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{MYSQL, "LIMIT 10 OFFSET 20"},
+		{SQLITE, "LIMIT 10 OFFSET 20"},
+	}
+
+	for _, tt := range tests {
+		if got := fetchFirstClause(tt.driver, 20, 10); got != tt.want {
+			t.Errorf("fetchFirstClause(%s) got = %s, want %s", tt.driver, got, tt.want)
+		}
+	}
+}
+
+func TestDriverSupportsFeature(t *testing.T) {
+	tests := []struct {
+		driver  string
+		version string
+		feature DBFeature
+		want    bool
+	}{
+		{POSTGRES, "PostgreSQL 15.4 on x86_64-pc-linux-gnu", FeatureSkipLocked, true},
+		{POSTGRES, "PostgreSQL 9.4.26 on x86_64-pc-linux-gnu", FeatureSkipLocked, false},
+		{MYSQL, "8.0.33 (MySQL Community Server - GPL)", FeatureSkipLocked, true},
+		{MYSQL, "5.7.44-log (MySQL Community Server (GPL))", FeatureSkipLocked, false},
+		{MYSQL, "10.5.22-MariaDB (mariadb.org binary distribution)", FeatureSkipLocked, false},
+		{MYSQL, "10.11.5-MariaDB (mariadb.org binary distribution)", FeatureSkipLocked, true},
+		{MYSQL, "5.7.44-log (MySQL Community Server (GPL))", FeatureAdvisoryLocks, true},
+		{MSSQL, "Microsoft SQL Server 2019 (RTM-CU22) - 15.0.4322.2 (X64)", FeatureSkipLocked, true},
+		{POSTGRES, "CockroachDB CCL v22.2.19 (x86_64-pc-linux-gnu, built 2024/01/10 18:53:56, go1.19.13)", FeatureSkipLocked, false},
+		{POSTGRES, "CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, built 2023/09/27 01:53:43, go1.19.13)", FeatureSkipLocked, true},
+		{POSTGRES, "CockroachDB CCL v23.1.11 (x86_64-pc-linux-gnu, built 2023/09/27 01:53:43, go1.19.13)", FeatureAdvisoryLocks, false},
+		{POSTGRES, "PostgreSQL 15.4 on x86_64-pc-linux-gnu", FeatureAdvisoryLocks, true},
+		{SQLITE, "3.44.0", FeatureSkipLocked, false},
+		{SQLITE, "3.44.0", FeatureAdvisoryLocks, false},
+	}
+
+	for _, tt := range tests {
+		if got := driverSupportsFeature(tt.driver, tt.version, tt.feature); got != tt.want {
+			t.Errorf("driverSupportsFeature(%s, %s, %s) got = %v, want %v", tt.driver, tt.version, tt.feature, got, tt.want)
+		}
+	}
+}
+
+func TestGetDialectName(t *testing.T) {
+	tests := []struct {
+		driver string
+		dsn    string
+		want   string
+	}{
+		{POSTGRES, "host=127.0.0.1 sslmode=disable user=test_user", POSTGRES},
+		{POSTGRES, "host=127.0.0.1 port=26257 user=root sslmode=disable", COCKROACH},
+		{POSTGRES, "postgresql://root@127.0.0.1:5432/defaultdb", POSTGRES},
+		{POSTGRES, "postgresql://root@127.0.0.1:26257/defaultdb?sslmode=disable", COCKROACH},
+		{POSTGRES, "cockroachdb://root@crdb.local/defaultdb", COCKROACH},
+		{COCKROACH, "host=crdb.local user=root", COCKROACH},
+		{MYSQL, "root@tcp(127.0.0.1:26257)/test", MYSQL},
+	}
+
+	for _, tt := range tests {
+		if got := GetDialectName(tt.driver, tt.dsn); got != tt.want {
+			t.Errorf("GetDialectName(%s, %s) got = %s, want %s", tt.driver, tt.dsn, got, tt.want)
+		}
+	}
+
+	opts := DatabaseOpts{Driver: COCKROACH, Dsn: "cockroachdb://root@crdb.local/defaultdb"}
+	opts.ResolveDialect()
+	if opts.Driver != POSTGRES || opts.DialectName() != COCKROACH || opts.Dsn != "postgresql://root@crdb.local/defaultdb" {
+		t.Errorf("ResolveDialect() got driver = %s, dialect = %s, dsn = %s", opts.Driver, opts.DialectName(), opts.Dsn)
+	}
+}
+
+func TestSupportsIsolation(t *testing.T) {
+	tests := []struct {
+		opts DatabaseOpts
+		want bool
+	}{
+		{DatabaseOpts{Driver: POSTGRES}, true},
+		{DatabaseOpts{Driver: POSTGRES, Isolation: IsolationRepeatableRead}, true},
+		{DatabaseOpts{Driver: POSTGRES, Isolation: "snapshot"}, false},
+		{DatabaseOpts{Driver: POSTGRES, Dialect: COCKROACH, Isolation: IsolationReadCommitted}, false},
+		{DatabaseOpts{Driver: POSTGRES, Dialect: COCKROACH, Isolation: IsolationSerializable}, true},
+		{DatabaseOpts{Driver: MSSQL, Isolation: IsolationReadCommitted}, true},
+		{DatabaseOpts{Driver: SQLITE, Isolation: IsolationReadCommitted}, false},
+		{DatabaseOpts{Driver: SQLITE, Isolation: IsolationSerializable}, true},
+		{DatabaseOpts{Driver: CASSANDRA, Isolation: IsolationSerializable}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.opts.SupportsIsolation(); got != tt.want {
+			t.Errorf("SupportsIsolation(%s, %s) got = %v, want %v", tt.opts.DialectName(), tt.opts.Isolation, got, tt.want)
+		}
+	}
+}
+
+func TestTxRetriesLimit(t *testing.T) {
+	zero := 0
+
+	tests := []struct {
+		opts DatabaseOpts
+		want int
+	}{
+		{DatabaseOpts{TxMaxRetries: 3}, 3},
+		{DatabaseOpts{TxMaxRetries: 3, MaxRetries: &zero}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.opts.TxRetriesLimit(); got != tt.want {
+			t.Errorf("TxRetriesLimit() got = %d, want %d", got, tt.want)
+		}
+	}
+}
+
+// TestTransactIsolation needs a postgres server, e.g. PERFKIT_TEST_POSTGRES_DSN='host=127.0.0.1 sslmode=disable user=postgres'
+func TestTransactIsolation(t *testing.T) {
+	dsn := os.Getenv("PERFKIT_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("PERFKIT_TEST_POSTGRES_DSN is not set")
+	}
+
+	for level, want := range map[string]string{
+		IsolationReadCommitted:  "read committed",
+		IsolationRepeatableRead: "repeatable read",
+		IsolationSerializable:   "serializable",
+	} {
+		c := &DBConnector{
+			Logger:        NewLogger(LogError),
+			DbOpts:        &DatabaseOpts{Driver: POSTGRES, Dsn: dsn, Isolation: level},
+			RetryAttempts: 1,
+		}
+
+		var got string
+		err := c.Transact(func() error {
+			c.QueryRowAndScan("SELECT current_setting('transaction_isolation')", &got)
+
+			return nil
+		})
+		c.Close()
+
+		if err != nil {
+			t.Fatalf("Transact() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("--isolation %s: transaction_isolation got = %s, want %s", level, got, want)
+		}
+	}
+}
+
+func TestIsConnectionLost(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{driver.ErrBadConn, true},
+		{fmt.Errorf("query failed: %w", io.ErrUnexpectedEOF), true},
+		{&pq.Error{Code: "57P01"}, true},
+		{&pq.Error{Code: "57014"}, false},
+		{&pq.Error{Code: "40001"}, false},
+		{errors.New("syntax error"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsConnectionLost(tt.err); got != tt.want {
+			t.Errorf("IsConnectionLost(%v) got = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestReconnectOnLoss(t *testing.T) {
+	c := &DBConnector{
+		Logger:        NewLogger(LogError),
+		DbOpts:        &DatabaseOpts{Driver: SQLITE, Dsn: t.TempDir() + "/reconnect.db", ReconnectOnLoss: 1},
+		RetryAttempts: 1,
+	}
+	c.ExecOrExit("CREATE TABLE reconnect_test (id INT)")
+	defer c.Close()
+
+	b := New()
+	b.ReconnectOnLoss = true
+
+	calls := 0
+	b.Worker = func(id int) (loops int) {
+		calls++
+		if calls == 1 {
+			c.StatementExit("Exec()", time.Now(), driver.ErrBadConn, false, nil, "INSERT INTO reconnect_test VALUES (1)", nil, nil, nil)
+			c.Exit("the connection is lost")
+		}
+
+		return 1
+	}
+
+	TakeReconnects()
+	if loops := b.runWorker(0); loops != 1 || calls != 2 {
+		t.Errorf("runWorker() got loops = %d, calls = %d, want 1 and 2", loops, calls)
+	}
+	if n := TakeReconnects(); n != 1 {
+		t.Errorf("TakeReconnects() got = %d, want 1", n)
+	}
+}
+
+func TestExecSecretOrExit(t *testing.T) {
+	c := &DBConnector{
+		Logger:        NewLogger(LogError),
+		DbOpts:        &DatabaseOpts{Driver: SQLITE, Dsn: t.TempDir() + "/secret.db"},
+		RetryAttempts: 1,
+	}
+	defer c.Close()
+
+	c.ExecSecretOrExit("CREATE TABLE secret_test (x TEXT DEFAULT 'topsecret')", "topsecret")
+	if strings.Contains(c.lastQuery, "topsecret") || !strings.Contains(c.lastQuery, "******") {
+		t.Errorf("ExecSecretOrExit() secret is not masked, last query = %s", c.lastQuery)
+	}
+	if !c.TableExists("secret_test") {
+		t.Errorf("ExecSecretOrExit() statement has not been executed")
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	c := &DBConnector{DbOpts: &DatabaseOpts{}}
+	if err := c.injectFault(); err != nil {
+		t.Errorf("injectFault() without --inject-* options error = %v", err)
+	}
+
+	c.DbOpts.InjectErrorRate = 100
+	if err := c.injectFault(); err != nil {
+		t.Errorf("injectFault() outside of Transact() error = %v", err)
+	}
+
+	c.transacting = true
+	for i := 0; i < 3; i++ {
+		if err := c.injectFault(); !errors.Is(err, ErrInjectedFault) {
+			t.Errorf("injectFault() error = %v, want %v", err, ErrInjectedFault)
+		}
+	}
+
+	if errs, drops := c.TakeInjectedFaults(); errs != 3 || drops != 0 {
+		t.Errorf("TakeInjectedFaults() got = %d, %d, want 3, 0", errs, drops)
+	}
+	if errs, _ := c.TakeInjectedFaults(); errs != 0 {
+		t.Errorf("TakeInjectedFaults() has not been reset, got = %d", errs)
+	}
+}
+
+func TestPreparedPool(t *testing.T) {
+	c := &DBConnector{
+		Logger:        NewLogger(LogError),
+		DbOpts:        &DatabaseOpts{Driver: SQLITE, Dsn: t.TempDir() + "/pool.db"},
+		RetryAttempts: 1,
+	}
+	c.ExecOrExit("CREATE TABLE pool_test (id INT)")
+	c.ExecOrExit("INSERT INTO pool_test VALUES (1), (2), (3)")
+	defer c.Close()
+
+	p, err := c.NewPreparedPool(2)
+	if err != nil {
+		t.Fatalf("NewPreparedPool() error = %v", err)
+	}
+	defer p.Close()
+
+	// 2 connections, every one prepares the statement once and then reuses it
+	for i := 0; i < 10; i++ {
+		n, err := p.Query("SELECT id FROM pool_test WHERE id <= $1", 2)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if n != 2 {
+			t.Errorf("Query() got %d rows, want 2", n)
+		}
+	}
+
+	stats := p.TakeStats()
+	if stats.Executes != 10 || stats.Prepares < 1 || stats.Prepares > 2 {
+		t.Errorf("TakeStats() got %+v, want 10 executes and 1..2 prepares", stats)
+	}
+	if stats = p.TakeStats(); stats.Executes != 0 || stats.Prepares != 0 {
+		t.Errorf("TakeStats() must reset the stats, got %+v", stats)
+	}
+
+	if _, err = c.NewPreparedPool(0); err == nil {
+		t.Errorf("NewPreparedPool(0) expected error")
+	}
+}
+
+func TestCancelQuery(t *testing.T) {
+	c := &DBConnector{
+		Logger:        NewLogger(LogError),
+		DbOpts:        &DatabaseOpts{Driver: SQLITE, Dsn: t.TempDir() + "/cancel.db", MaxOpenConns: 2},
+		RetryAttempts: 1,
+	}
+	defer c.Close()
+
+	endless := "WITH RECURSIVE s(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM s) SELECT SUM(n) FROM s"
+
+	stats, err := c.CancelQuery(endless, 10*time.Millisecond, 10*time.Second)
+	if err != nil {
+		t.Fatalf("CancelQuery() error = %v", err)
+	}
+	if stats.Returned >= 10*time.Second {
+		t.Errorf("CancelQuery() the query didn't return in time, got %v", stats.Returned)
+	}
+	if stats.Stopped != -1 || stats.Leaked {
+		t.Errorf("CancelQuery() sqlite server state must be not observable, got %+v", stats)
+	}
+
+	if _, err = c.CancelQuery("SELECT 1", time.Second, time.Second); err == nil {
+		t.Errorf("CancelQuery() of a fast query expected error")
+	}
+}
+
+func TestExplainCapture(t *testing.T) {
+	dir := t.TempDir()
+	defer CaptureExplain("", "")
+
+	pg := &DBConnector{DbOpts: &DatabaseOpts{Driver: POSTGRES}}
+	sqlite := &DBConnector{DbOpts: &DatabaseOpts{Driver: SQLITE}}
+
+	if f := pg.explainFile(); f != "" {
+		t.Errorf("explainFile() w/o --explain-output got = %s, want none", f)
+	}
+
+	CaptureExplain(dir, "select-1")
+	if f := sqlite.explainFile(); f != "" {
+		t.Errorf("explainFile() sqlite has no JSON plans, got = %s", f)
+	}
+	if f, want := pg.explainFile(), dir+"/select-1.json"; f != want {
+		t.Errorf("explainFile() got = %s, want %s", f, want)
+	}
+	if f := pg.explainFile(); f != "" {
+		t.Errorf("explainFile() only the first plan of the test is saved, got = %s", f)
+	}
+
+	CaptureExplain(dir, "select-2")
+	if f, want := pg.explainFile(), dir+"/select-2.json"; f != want {
+		t.Errorf("explainFile() got = %s, want %s", f, want)
+	}
+
+	c := &DBConnector{
+		Logger:        NewLogger(LogError),
+		DbOpts:        &DatabaseOpts{Driver: SQLITE, Dsn: t.TempDir() + "/explain.db"},
+		RetryAttempts: 1,
+	}
+	defer c.Close()
+
+	rows, err := c.db().Query(`SELECT '[{"Plan": {}}]'`)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	defer rows.Close()
+
+	c.saveExplain(rows, dir+"/plan.json", "SELECT 1")
+	if plan, err := os.ReadFile(dir + "/plan.json"); err != nil || string(plan) != "[{\"Plan\": {}}]\n" {
+		t.Errorf("saveExplain() got = %q, %v", plan, err)
+	}
+}
@@ -3,8 +3,10 @@ package benchmark
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,6 +17,56 @@ import (
 // letterBytes is used for random string generation
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
+// localeLetters are the character sets used for random string generation in given data locale, see SetDataLocale()
+var localeLetters = map[string]string{
+	"ascii": letterBytes,
+	"ja":    "あいうえおかきくけこさしすせそたちつてとなにぬねのはひふへほまみむめもやゆよらりるれろわをんアイウエオカキクケコサシスセソ日本語東京大阪時間会社名前",
+	"zh":    "的一是不了人我在有他这中大来上国个到说们为子和你地出道也时年得就那要下以生会自着去之过家学对可",
+	"ko":    "가나다라마바사아자차카타파하거너더러머버서어저처커터퍼허고노도로모보소오조초코토포호",
+	"emoji": "😀😃😄😁😆😅😂🤣😊😇🙂🙃😉😌😍🥰😘😗😙😚😋😛😝😜🤪🤨🧐🤓😎🤩🥳🚀🔥💾📦",
+	"mixed": letterBytes + "あいうえおかきくけこ日本語东京中文가나다라😀🚀🔥",
+}
+
+// dataLetters is a character set of the current data locale
+var dataLetters = []rune(letterBytes)
+
+// dataLocale is the current data locale
+var dataLocale = "ascii"
+
+// SetDataLocale sets the character set used for random strings generation (ascii|ja|zh|ko|emoji|mixed),
+// string sizes are in characters, so multibyte locales produce longer values in bytes
+func SetDataLocale(locale string) error {
+	letters, ok := localeLetters[locale]
+	if !ok {
+		names := make([]string, 0, len(localeLetters))
+		for name := range localeLetters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return fmt.Errorf("unsupported data locale: '%s', supported locales are: %s", locale, strings.Join(names, "|"))
+	}
+
+	cardinalityCache.lock.Lock()
+	cardinalityCache.entities = make(map[string][]string)
+	cardinalityCache.lock.Unlock()
+
+	dataLetters = []rune(letters)
+	dataLocale = locale
+
+	return nil
+}
+
+// DataLocale returns the current data locale
+func DataLocale() string {
+	return dataLocale
+}
+
+// DataLocaleLetter returns the first character of the current data locale, e.g. for LIKE patterns matching the generated strings
+func DataLocaleLetter() string {
+	return string(dataLetters[0])
+}
+
 // cardinalityCacheType is a struct for storing cardinality cache data (for random string generation)
 type cardinalityCacheType struct {
 	lock     sync.RWMutex
@@ -42,12 +94,12 @@ func (cc *cardinalityCacheType) randStringWithCardinality(randID int, pfx string
 		if !exists {
 			var entities []string
 			for n := 0; n < cardinality; n++ {
-				bytes := make([]byte, rr.Intn(maxsize-minsize-len(pfx))+minsize)
-				l := len(letterBytes)
-				for i := range bytes {
-					bytes[i] = letterBytes[rr.Intn(l)]
+				runes := make([]rune, rr.Intn(maxsize-minsize-len(pfx))+minsize)
+				l := len(dataLetters)
+				for i := range runes {
+					runes[i] = dataLetters[rr.Intn(l)]
 				}
-				entities = append(entities, pfx+string(bytes))
+				entities = append(entities, pfx+string(runes))
 			}
 			cc.entities[index] = entities
 		}
@@ -73,22 +125,22 @@ func (b *Benchmark) RandStringBytes(workerID int, pfx string, cardinality int, m
 		return cardinalityCache.randStringWithCardinality(rw.Intn(cardinality), pfx, cardinality, maxsize, minsize)
 	}
 
-	var bytes []byte
-	l := len(letterBytes)
+	var runes []rune
+	l := len(dataLetters)
 
 	if seeded {
-		bytes = make([]byte, rw.Seeded().Intn(maxsize-minsize)+minsize)
-		for i := range bytes {
-			bytes[i] = letterBytes[rw.Seeded().Intn(l)]
+		runes = make([]rune, rw.Seeded().Intn(maxsize-minsize)+minsize)
+		for i := range runes {
+			runes[i] = dataLetters[rw.Seeded().Intn(l)]
 		}
 	} else {
-		bytes = make([]byte, rw.Unique().Intn(maxsize-minsize)+minsize)
-		for i := range bytes {
-			bytes[i] = letterBytes[rw.Unique().Intn(l)]
+		runes = make([]rune, rw.Unique().Intn(maxsize-minsize)+minsize)
+		for i := range runes {
+			runes[i] = dataLetters[rw.Unique().Intn(l)]
 		}
 	}
 
-	return string(bytes)
+	return string(runes)
 }
 
 /*
@@ -148,6 +200,77 @@ func (rw *RandomizerWorker) UUID() string {
 	)
 }
 
+// UUIDv7 returns random time-ordered UUID v7 value (RFC 9562), i.e. unix milliseconds followed by random bits
+func (rw *RandomizerWorker) UUIDv7() string {
+	r := rw.Unique()
+	ms := time.Now().UnixMilli()
+
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%04x%04x%04x",
+		ms>>16&0xffffffff, ms&0xffff,
+		r.Int31n(0xffff)&0x0fff|0x7000,
+		r.Int31n(0xffff)&0x3fff|0x8000,
+		r.Int31n(0xffff), r.Int31n(0xffff), r.Int31n(0xffff),
+	)
+}
+
+// UUIDv7Bound returns the smallest UUID v7 value of given unix milliseconds, so the keys generated within a time window
+// can be range-scanned as [UUIDv7Bound(from), UUIDv7Bound(to))
+func UUIDv7Bound(ms int64) string {
+	return fmt.Sprintf("%08x-%04x-7000-0000-000000000000", ms>>16&0xffffffff, ms&0xffff)
+}
+
+// UUIDv7Millis returns unix milliseconds the given UUID v7 value was generated at
+func UUIDv7Millis(uuid string) (int64, error) {
+	var high, low int64
+	if _, err := fmt.Sscanf(uuid, "%08x-%04x-", &high, &low); err != nil {
+		return 0, fmt.Errorf("not a UUID v7 value: '%s'", uuid)
+	}
+
+	return high<<16 | low, nil
+}
+
+// Decimal returns random non-negative decimal value as a string having up to precision digits in total and exactly scale fraction digits
+func (rw *RandomizerWorker) Decimal(precision int, scale int) string {
+	var sb strings.Builder
+
+	for i := 0; i < precision-scale; i++ {
+		d := rw.Intn(10)
+		if sb.Len() == 0 && d == 0 {
+			continue // skip leading zeros
+		}
+		sb.WriteByte(byte('0' + d))
+	}
+	if sb.Len() == 0 {
+		sb.WriteByte('0')
+	}
+
+	if scale > 0 {
+		sb.WriteByte('.')
+		for i := 0; i < scale; i++ {
+			sb.WriteByte(byte('0' + rw.Intn(10)))
+		}
+	}
+
+	return sb.String()
+}
+
+// seqKeyLast is the last value returned by SeqKey()
+var seqKeyLast int64
+
+// SeqKey returns strictly monotonic zero-padded key, it is based on current time in nanoseconds, so it keeps growing across the runs
+func SeqKey() string {
+	for {
+		last := atomic.LoadInt64(&seqKeyLast)
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&seqKeyLast, last, next) {
+			return fmt.Sprintf("%020d", next)
+		}
+	}
+}
+
 // UUIDn returns random UUID v4 value (RFC 4122) with given limit
 func (rw *RandomizerWorker) UUIDn(limit int) string {
 	r := rw.Unique()
@@ -155,9 +278,9 @@ func (rw *RandomizerWorker) UUIDn(limit int) string {
 	return fmt.Sprintf("01234567-89ab-cdef-0123-0000%08x", r.Intn(limit))
 }
 
-// RandTime returns random time within the given limit
+// RandTime returns random time (in UTC) within the given limit
 func (rw *RandomizerWorker) RandTime(daysAgoLimit int) time.Time {
-	now := time.Now()
+	now := time.Now().UTC()
 
 	days := time.Duration(daysAgoLimit) * 24 * time.Hour
 	from := now.Add(-days)
@@ -239,6 +362,9 @@ func (rz *Randomizer) GetWorker(workerID int) *RandomizerWorker {
  * Database fake value generators
  */
 
+// compressibleBlobPattern is the repeated text of the compressible part of the 'compressible_blob' values
+const compressibleBlobPattern = "The quick brown fox jumps over the lazy dog. "
+
 // DBFakeColumnConf is a struct for storing DB fake column configuration
 type DBFakeColumnConf struct {
 	ColumnName  string
@@ -265,11 +391,17 @@ func (b *Benchmark) GenFakeValue(workerID int, columnType string, columnName str
 	case "now_ns":
 		return time.Now().UnixNano()
 	case "now":
-		return time.Now()
+		// UTC keeps the stored value independent from the client and the session time zones
+		return time.Now().UTC()
 	case "int":
 		return rw.Intn(cardinality)
 	case "bigint":
 		return rand.Int63()
+	case "float":
+		return rw.Seeded().Float64() * float64(cardinality)
+	case "decimal":
+		// maxsize is a precision (total digits), minsize is a scale (fraction digits)
+		return rw.Decimal(maxsize, minsize)
 	case "tenant_uuid":
 		return tenantUUID
 	case "tenant_uuid_bound_id":
@@ -285,6 +417,10 @@ func (b *Benchmark) GenFakeValue(workerID int, columnType string, columnName str
 		return b.RandStringBytes(workerID, columnName+"_", cardinality, maxsize, minsize, true)
 	case "rstring":
 		return b.RandStringBytes(workerID, columnName+"_", cardinality, maxsize, minsize, false)
+	case "seq_key":
+		return SeqKey()
+	case "uuid_v7":
+		return rw.UUIDv7()
 	case "uuid":
 		if cardinality == 0 {
 			return rw.UUID()
@@ -293,7 +429,7 @@ func (b *Benchmark) GenFakeValue(workerID int, columnType string, columnName str
 		}
 	case "time":
 		if cardinality == 0 {
-			return time.Now().String()
+			return time.Now().UTC().String()
 		} else {
 			return rw.RandTime(cardinality).String()
 		}
@@ -316,6 +452,14 @@ func (b *Benchmark) GenFakeValue(workerID int, columnType string, columnName str
 		return []byte(b.RandStringBytes(workerID, "", cardinality, maxsize, minsize, false))
 	case "json":
 		return b.GenRandomJson(rw, 1024)
+	case "json_tags":
+		// cardinality is a tags vocabulary size, maxsize is a max amount of tags in the document
+		tags := make([]string, rw.Intn(maxsize)+1)
+		for i := range tags {
+			tags[i] = fmt.Sprintf("\"tag_%d\"", rw.Intn(cardinality))
+		}
+
+		return `{"tags": [` + strings.Join(tags, ", ") + `]}`
 	case "bool":
 		return rw.Intn(2) == 1
 	case "blob":
@@ -326,6 +470,20 @@ func (b *Benchmark) GenFakeValue(workerID int, columnType string, columnName str
 			b.Exit(err.Error())
 		}
 
+		return blob
+	case "compressible_blob":
+		// cardinality is the compressible share (%) of the blob filled by a repeated text, the rest is random
+		size := rw.Intn(maxsize-minsize) + minsize
+		blob := make([]byte, size)
+		compressible := size * cardinality / 100
+		for i := 0; i < compressible; i++ {
+			blob[i] = compressibleBlobPattern[i%len(compressibleBlobPattern)]
+		}
+		err := rw.Read(blob[compressible:])
+		if err != nil {
+			b.Exit(err.Error())
+		}
+
 		return blob
 	default:
 		b.Exit("generateParameter: unsupported parameter '%s'", columnType)
@@ -1,7 +1,12 @@
 package benchmark
 
 import (
+	"database/sql"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestRandStringBytesWithCardinality(t *testing.T) {
@@ -22,6 +27,30 @@ func TestRandStringBytesWithoutCardinality(t *testing.T) {
 	}
 }
 
+func TestRandStringBytesWithDataLocale(t *testing.T) {
+	if err := SetDataLocale("ja"); err != nil {
+		t.Fatalf("SetDataLocale() error = %v", err)
+	}
+	defer SetDataLocale("ascii") //nolint:errcheck
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+	str := b.RandStringBytes(1, "", 0, 20, 5, true)
+	if n := utf8.RuneCountInString(str); n < 5 || n > 20 {
+		t.Errorf("RandStringBytes() error, string length %d out of bounds", n)
+	}
+	if len(str) == utf8.RuneCountInString(str) {
+		t.Errorf("RandStringBytes() error, no multibyte characters generated in 'ja' locale: %s", str)
+	}
+	if !strings.Contains(localeLetters["ja"], DataLocaleLetter()) {
+		t.Errorf("DataLocaleLetter() got = %s, not a 'ja' locale character", DataLocaleLetter())
+	}
+
+	if err := SetDataLocale("xx"); err == nil {
+		t.Errorf("SetDataLocale() expected error for unsupported locale")
+	}
+}
+
 func TestGenFakeValueAutoInc(t *testing.T) {
 	b := New()
 	b.Randomizer = NewRandomizer(1, 1)
@@ -71,3 +100,170 @@ func TestGenDBParameterPlaceholdersCassandra(t *testing.T) {
 		t.Errorf("GenDBParameterPlaceholdersCassandra() error, placeholders mismatch")
 	}
 }
+
+func TestUUIDv7(t *testing.T) {
+	rz := NewRandomizer(1, 1)
+	rw := rz.GetWorker(0)
+
+	first := rw.UUIDv7()
+	time.Sleep(2 * time.Millisecond)
+	second := rw.UUIDv7()
+
+	if len(first) != 36 || first[14] != '7' {
+		t.Errorf("UUIDv7() error, unexpected format: %s", first)
+	}
+	if first >= second {
+		t.Errorf("UUIDv7() error, values are not time-ordered: %s >= %s", first, second)
+	}
+}
+
+func TestUUIDv7Bound(t *testing.T) {
+	rz := NewRandomizer(1, 1)
+	rw := rz.GetWorker(0)
+
+	from := time.Now().UnixMilli()
+	uuid := rw.UUIDv7()
+	to := time.Now().UnixMilli() + 1
+
+	if lo, hi := UUIDv7Bound(from), UUIDv7Bound(to); uuid < lo || uuid >= hi {
+		t.Errorf("UUIDv7Bound() error, %s is out of [%s, %s)", uuid, lo, hi)
+	}
+
+	ms, err := UUIDv7Millis(uuid)
+	if err != nil || ms < from || ms >= to {
+		t.Errorf("UUIDv7Millis(%s) got = %d, %v, want value in [%d, %d)", uuid, ms, err, from, to)
+	}
+}
+
+func TestSeqKey(t *testing.T) {
+	prev := SeqKey()
+	for i := 0; i < 1000; i++ {
+		next := SeqKey()
+		if next <= prev {
+			t.Errorf("SeqKey() error, values are not monotonic: %s <= %s", next, prev)
+		}
+		prev = next
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	rz := NewRandomizer(1, 1)
+	rw := rz.GetWorker(0)
+
+	for i := 0; i < 100; i++ {
+		val := rw.Decimal(6, 2)
+		parts := strings.Split(val, ".")
+		if len(parts) != 2 || len(parts[0]) < 1 || len(parts[0]) > 4 || len(parts[1]) != 2 {
+			t.Errorf("Decimal() error, unexpected value for decimal(6,2): %s", val)
+		}
+	}
+
+	if val := rw.Decimal(3, 0); strings.Contains(val, ".") || len(val) > 3 {
+		t.Errorf("Decimal() error, unexpected value for decimal(3,0): %s", val)
+	}
+}
+
+func TestGenFakeValueTimeRoundTripInSessionTimezone(t *testing.T) {
+	origLocal := time.Local
+	defer func() { time.Local = origLocal }()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+	time.Local = loc
+
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+
+	for _, tz := range []string{"UTC", "Asia/Kolkata", "America/Los_Angeles"} {
+		dsn, err := withSessionTimezone(SQLITE, "file::memory:", tz)
+		if err != nil {
+			t.Fatalf("withSessionTimezone() error = %v", err)
+		}
+
+		db, err := sql.Open(SQLITE3, dsn)
+		if err != nil {
+			t.Fatalf("sql.Open() error = %v", err)
+		}
+
+		if _, err = db.Exec("CREATE TABLE ts (v timestamp)"); err != nil {
+			t.Fatalf("create table error = %v", err)
+		}
+
+		written := []time.Time{
+			b.GenFakeValue(0, "now", "v", 0, 0, 0, "").(time.Time),
+			b.Randomizer.GetWorker(0).RandTime(30),
+		}
+
+		for _, w := range written {
+			if w.Location() != time.UTC {
+				t.Errorf("GenFakeValue() error, time %s is not in UTC", w)
+			}
+
+			if _, err = db.Exec("DELETE FROM ts"); err != nil {
+				t.Fatalf("delete error = %v", err)
+			}
+			if _, err = db.Exec("INSERT INTO ts (v) VALUES (?)", w); err != nil {
+				t.Fatalf("insert error = %v", err)
+			}
+
+			var read time.Time
+			if err = db.QueryRow("SELECT v FROM ts").Scan(&read); err != nil {
+				t.Fatalf("select error = %v", err)
+			}
+
+			if !read.Equal(w) {
+				t.Errorf("time round trip error in session time zone %s: written %s, read %s", tz, w, read)
+			}
+		}
+
+		db.Close()
+	}
+}
+
+func TestGenFakeValueJSONTags(t *testing.T) {
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+	val, ok := b.GenFakeValue(1, "json_tags", "doc", 10, 5, 0, "").(string)
+	if !ok {
+		t.Fatalf("GenFakeValue() error, value is not a string")
+	}
+
+	var doc struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(val), &doc); err != nil {
+		t.Fatalf("GenFakeValue() error, invalid json %s: %v", val, err)
+	}
+	if len(doc.Tags) < 1 || len(doc.Tags) > 5 {
+		t.Errorf("GenFakeValue() error, %d tags out of bounds", len(doc.Tags))
+	}
+}
+
+func TestGenFakeValueCompressibleBlob(t *testing.T) {
+	b := New()
+	b.Randomizer = NewRandomizer(1, 1)
+
+	for _, share := range []int{0, 50, 100} {
+		blob, ok := b.GenFakeValue(1, "compressible_blob", "data", share, 2001, 2000, "").([]byte)
+		if !ok {
+			t.Fatalf("GenFakeValue() error, value is not a []byte")
+		}
+		if len(blob) != 2000 {
+			t.Fatalf("GenFakeValue() error, got %d bytes, want 2000", len(blob))
+		}
+
+		compressible := len(blob) * share / 100
+		for i := 0; i < compressible; i++ {
+			if blob[i] != compressibleBlobPattern[i%len(compressibleBlobPattern)] {
+				t.Fatalf("GenFakeValue() error, %d%% compressible blob byte #%d is not the pattern", share, i)
+			}
+		}
+
+		random := blob[compressible:]
+		if len(random) > 0 && strings.Contains(compressibleBlobPattern, string(random[:8])) {
+			t.Errorf("GenFakeValue() error, the rest of %d%% compressible blob is not random", share)
+		}
+	}
+}
@@ -1,13 +1,26 @@
 package benchmark
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
+	"math"
+	"net"
 	"os"
 	"reflect"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
 )
 
 // FatalError prints error message and exits with code 127
@@ -129,6 +142,24 @@ func DumpRecursive(i interface{}, indent string) string {
 	}
 }
 
+// RenderBool returns the boolean literal of given SQL dialect, it matches the {$boolean} column type of DefaultCreateQueryPatchFunc
+func RenderBool(dialect string, v bool) string {
+	switch dialect {
+	case MYSQL, SQLITE, MSSQL, CLICKHOUSE:
+		// TINYINT(1), INTEGER affinity, BIT and UInt8 accordingly
+		if v {
+			return "1"
+		}
+
+		return "0"
+	case POSTGRES, CASSANDRA:
+		return strconv.FormatBool(v)
+	default:
+		// SQL standard
+		return strings.ToUpper(strconv.FormatBool(v))
+	}
+}
+
 // DefaultCreateQueryPatchFunc returns function that replaces placeholders in query with values from given table, sql_driver and sql_engine
 func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, sqlEngine string) (string, error) {
 	query = strings.ReplaceAll(query, "{table}", table)
@@ -152,6 +183,7 @@ func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, s
 		query = strings.ReplaceAll(query, "{$boolean_false}", "0")
 		query = strings.ReplaceAll(query, "{$boolean_true}", "1")
 		query = strings.ReplaceAll(query, "{$tinyint}", "TINYINT")
+		query = strings.ReplaceAll(query, "{$double}", "DOUBLE")
 		query = strings.ReplaceAll(query, "{$longtext}", "LONGTEXT")
 		query = strings.ReplaceAll(query, "{$unique}", "unique")
 		query = strings.ReplaceAll(query, "{$notnull}", "not null")
@@ -179,6 +211,7 @@ func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, s
 		query = strings.ReplaceAll(query, "{$boolean_false}", "0")
 		query = strings.ReplaceAll(query, "{$boolean_true}", "1")
 		query = strings.ReplaceAll(query, "{$tinyint}", "SMALLINT")
+		query = strings.ReplaceAll(query, "{$double}", "REAL")
 		query = strings.ReplaceAll(query, "{$longtext}", "TEXT")
 		query = strings.ReplaceAll(query, "{$unique}", "unique")
 		query = strings.ReplaceAll(query, "{$engine}", "")
@@ -203,6 +236,7 @@ func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, s
 		query = strings.ReplaceAll(query, "{$boolean_false}", "0")
 		query = strings.ReplaceAll(query, "{$boolean_true}", "1")
 		query = strings.ReplaceAll(query, "{$tinyint}", "TINYINT")
+		query = strings.ReplaceAll(query, "{$double}", "FLOAT")
 		query = strings.ReplaceAll(query, "{$longtext}", "NVARCHAR(MAX)")
 		query = strings.ReplaceAll(query, "{$unique}", "unique")
 		query = strings.ReplaceAll(query, "{$engine}", "")
@@ -227,6 +261,7 @@ func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, s
 		query = strings.ReplaceAll(query, "{$boolean_false}", "false")
 		query = strings.ReplaceAll(query, "{$boolean_true}", "true")
 		query = strings.ReplaceAll(query, "{$tinyint}", "SMALLINT")
+		query = strings.ReplaceAll(query, "{$double}", "DOUBLE PRECISION")
 		query = strings.ReplaceAll(query, "{$longtext}", "TEXT")
 		query = strings.ReplaceAll(query, "{$unique}", "unique")
 		query = strings.ReplaceAll(query, "{$engine}", "")
@@ -250,7 +285,8 @@ func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, s
 		query = strings.ReplaceAll(query, "{$boolean}", "UInt8")                // ClickHouse uses UInt8 for boolean values
 		query = strings.ReplaceAll(query, "{$boolean_false}", "0")
 		query = strings.ReplaceAll(query, "{$boolean_true}", "1")
-		query = strings.ReplaceAll(query, "{$tinyint}", "Int8")    // Int8 for small integers
+		query = strings.ReplaceAll(query, "{$tinyint}", "Int8") // Int8 for small integers
+		query = strings.ReplaceAll(query, "{$double}", "Float64")
 		query = strings.ReplaceAll(query, "{$longtext}", "String") // Use String for long text
 		query = strings.ReplaceAll(query, "{$unique}", "")         // Unique values are not supported
 		query = strings.ReplaceAll(query, "{$engine}", "ENGINE = MergeTree() ORDER BY id;")
@@ -275,6 +311,7 @@ func DefaultCreateQueryPatchFunc(table string, query string, sqlDriver string, s
 		query = strings.ReplaceAll(query, "{$boolean_false}", "false")
 		query = strings.ReplaceAll(query, "{$boolean_true}", "true")
 		query = strings.ReplaceAll(query, "{$tinyint}", "tinyint")
+		query = strings.ReplaceAll(query, "{$double}", "double")
 		query = strings.ReplaceAll(query, "{$longtext}", "text") // Use text for long text
 		query = strings.ReplaceAll(query, "{$unique}", "")       // Unique values are not supported
 		query = strings.ReplaceAll(query, "{$engine}", "")
@@ -345,3 +382,204 @@ func WithAutoInc(DBDriver string) bool {
 		return false
 	}
 }
+
+// Percentile returns p-th percentile (0 < p <= 100) of given durations using the nearest-rank method
+func Percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	} else if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+// CoefficientOfVariation returns the ratio of the standard deviation to the mean of given values, in percents
+func CoefficientOfVariation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	var sqDiff float64
+	for _, v := range values {
+		sqDiff += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(sqDiff/float64(len(values))) / math.Abs(mean) * 100
+}
+
+// IsUniqueViolation returns true if given error is a unique or primary key constraint violation reported by the DB driver
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505" // unique_violation
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062 // ER_DUP_ENTRY
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		return mssqlErr.Number == 2627 || mssqlErr.Number == 2601 // unique constraint or unique index violation
+	}
+
+	return false
+}
+
+// IsCheckViolation returns true if given error is a CHECK constraint violation reported by the DB driver
+func IsCheckViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23514" // check_violation
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 3819 // ER_CHECK_CONSTRAINT_VIOLATED
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintCheck
+	}
+
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		return mssqlErr.Number == 547 // constraint conflict
+	}
+
+	return false
+}
+
+// IsRetryableTxError returns true if given error is a transient one and the whole transaction can be retried
+// (serialization failure, deadlock or lock wait timeout)
+func IsRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrInjectedFault) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001", "40P01", "55P03": // serialization_failure (also used by CockroachDB), deadlock_detected, lock_not_available
+			return true
+		}
+
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1205, 1213, 9007: // ER_LOCK_WAIT_TIMEOUT, ER_LOCK_DEADLOCK, TiDB write conflict
+			return true
+		}
+
+		return false
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		return mssqlErr.Number == 1205 || mssqlErr.Number == 1222 // deadlock victim, lock request time out
+	}
+
+	return false
+}
+
+// IsLockNotAvailable returns true if given error reports a row lock couldn't be acquired immediately (see FOR UPDATE NOWAIT)
+func IsLockNotAvailable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "55P03" // lock_not_available
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 3572 // ER_LOCK_NOWAIT
+	}
+
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		return mssqlErr.Number == 1222 // lock request time out
+	}
+
+	return false
+}
+
+// IsConnectionLost returns true if given error reports the DB session is gone, e.g. the server has been restarted
+func IsConnectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, target := range []error{driver.ErrBadConn, mysql.ErrInvalidConn, io.EOF, io.ErrUnexpectedEOF, syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.EPIPE} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// operator intervention class: admin_shutdown, crash_shutdown, cannot_connect_now, but not query_canceled
+		return pqErr.Code.Class() == "57" && pqErr.Code != "57014"
+	}
+
+	return false
+}
+
+// IsCachedPlanChanged returns true if given error reports the prepared statement became stale after a schema change (postgres 0A000)
+func IsCachedPlanChanged(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "0A000" && strings.Contains(pqErr.Message, "cached plan must not change result type")
+	}
+
+	return false
+}
@@ -1,7 +1,15 @@
 package benchmark
 
 import (
+	"database/sql"
+	"fmt"
 	"testing"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
 )
 
 func TestDefaultCreateQueryPatchFuncWithMySQL(t *testing.T) {
@@ -116,3 +124,206 @@ func TestDefaultCreateQueryPatchFuncWithCassandra(t *testing.T) {
 		t.Errorf("DefaultCreateQueryPatchFunc() got = %v, want %v", result, expected)
 	}
 }
+
+func TestRenderBool(t *testing.T) {
+	tests := []struct {
+		dialect   string
+		wantTrue  string
+		wantFalse string
+	}{
+		{POSTGRES, "true", "false"},
+		{CASSANDRA, "true", "false"},
+		{MYSQL, "1", "0"},
+		{SQLITE, "1", "0"},
+		{MSSQL, "1", "0"},
+		{CLICKHOUSE, "1", "0"},
+		{"unknown", "TRUE", "FALSE"},
+	}
+
+	for _, tt := range tests {
+		if got := RenderBool(tt.dialect, true); got != tt.wantTrue {
+			t.Errorf("RenderBool(%s, true) got = %s, want %s", tt.dialect, got, tt.wantTrue)
+		}
+		if got := RenderBool(tt.dialect, false); got != tt.wantFalse {
+			t.Errorf("RenderBool(%s, false) got = %s, want %s", tt.dialect, got, tt.wantFalse)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+
+	if p := Percentile(samples, 50); p != 5 {
+		t.Errorf("Percentile(50) got = %v, want %v", p, 5)
+	}
+	if p := Percentile(samples, 99); p != 10 {
+		t.Errorf("Percentile(99) got = %v, want %v", p, 10)
+	}
+	if p := Percentile(nil, 99); p != 0 {
+		t.Errorf("Percentile() of empty samples got = %v, want 0", p)
+	}
+	if samples[0] != 5 {
+		t.Errorf("Percentile() must not modify given samples")
+	}
+}
+
+func TestWithSessionTimezone(t *testing.T) {
+	tests := []struct {
+		driver   string
+		dsn      string
+		tz       string
+		expected string
+	}{
+		{POSTGRES, "host=127.0.0.1 user=test", "Europe/Berlin", "host=127.0.0.1 user=test timezone='Europe/Berlin'"},
+		{POSTGRES, "postgres://test@127.0.0.1/db?sslmode=disable", "UTC", "postgres://test@127.0.0.1/db?sslmode=disable&timezone=UTC"},
+		{MYSQL, "test@tcp(127.0.0.1:3306)/db", "Europe/Berlin", "test@tcp(127.0.0.1:3306)/db?time_zone=%27Europe%2FBerlin%27"},
+		{SQLITE, "/tmp/test.db", "UTC", "/tmp/test.db?_loc=UTC"},
+		{MSSQL, "sqlserver://test@127.0.0.1", "", "sqlserver://test@127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		result, err := withSessionTimezone(tt.driver, tt.dsn, tt.tz)
+		if err != nil {
+			t.Errorf("withSessionTimezone() error = %v", err)
+
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf("withSessionTimezone() got = %v, want %v", result, tt.expected)
+		}
+	}
+
+	if _, err := withSessionTimezone(MSSQL, "sqlserver://test@127.0.0.1", "UTC"); err == nil {
+		t.Errorf("withSessionTimezone() expected error for unsupported driver, got nil")
+	}
+	if _, err := withSessionTimezone(POSTGRES, "host=127.0.0.1", "Not/AZone"); err == nil {
+		t.Errorf("withSessionTimezone() expected error for invalid time zone, got nil")
+	}
+}
+
+func TestCoefficientOfVariation(t *testing.T) {
+	if cv := CoefficientOfVariation([]float64{100, 100, 100}); cv != 0 {
+		t.Errorf("CoefficientOfVariation() of equal values got = %v, want 0", cv)
+	}
+	if cv := CoefficientOfVariation([]float64{90, 110}); cv < 9.999 || cv > 10.001 {
+		t.Errorf("CoefficientOfVariation() got = %v, want 10", cv)
+	}
+	if cv := CoefficientOfVariation(nil); cv != 0 {
+		t.Errorf("CoefficientOfVariation() of empty values got = %v, want 0", cv)
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	db, err := sql.Open(SQLITE3, "file::memory:")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	if _, err = db.Exec("CREATE TABLE t (k TEXT UNIQUE, v INT NOT NULL)"); err != nil {
+		t.Fatalf("create table error = %v", err)
+	}
+	if _, err = db.Exec("INSERT INTO t (k, v) VALUES ('a', 1)"); err != nil {
+		t.Fatalf("insert error = %v", err)
+	}
+
+	_, err = db.Exec("INSERT INTO t (k, v) VALUES ('a', 2)")
+	if !IsUniqueViolation(fmt.Errorf("exec failed: %w", err)) {
+		t.Errorf("IsUniqueViolation() got = false for duplicate key error: %v", err)
+	}
+
+	_, err = db.Exec("INSERT INTO t (k, v) VALUES ('b', NULL)")
+	if err == nil || IsUniqueViolation(err) {
+		t.Errorf("IsUniqueViolation() got = true for not null constraint error: %v", err)
+	}
+	if IsUniqueViolation(nil) {
+		t.Errorf("IsUniqueViolation() got = true for nil error")
+	}
+}
+
+func TestIsCheckViolation(t *testing.T) {
+	db, err := sql.Open(SQLITE3, "file::memory:")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	if _, err = db.Exec("CREATE TABLE t (k TEXT UNIQUE, v INT CHECK (v BETWEEN 0 AND 100))"); err != nil {
+		t.Fatalf("create table error = %v", err)
+	}
+
+	_, err = db.Exec("INSERT INTO t (k, v) VALUES ('a', 101)")
+	if !IsCheckViolation(fmt.Errorf("exec failed: %w", err)) {
+		t.Errorf("IsCheckViolation() got = false for check constraint error: %v", err)
+	}
+
+	if _, err = db.Exec("INSERT INTO t (k, v) VALUES ('a', 1)"); err != nil {
+		t.Fatalf("insert error = %v", err)
+	}
+	_, err = db.Exec("INSERT INTO t (k, v) VALUES ('a', 2)")
+	if err == nil || IsCheckViolation(err) {
+		t.Errorf("IsCheckViolation() got = true for unique constraint error: %v", err)
+	}
+	if !IsCheckViolation(&pq.Error{Code: "23514"}) {
+		t.Errorf("IsCheckViolation() got = false for postgres check_violation")
+	}
+	if IsCheckViolation(nil) {
+		t.Errorf("IsCheckViolation() got = true for nil error")
+	}
+}
+
+func TestIsCachedPlanChanged(t *testing.T) {
+	stale := &pq.Error{Code: "0A000", Message: "cached plan must not change result type"}
+	if !IsCachedPlanChanged(fmt.Errorf("query failed: %w", stale)) {
+		t.Errorf("IsCachedPlanChanged() got = false for stale plan error")
+	}
+	if IsCachedPlanChanged(&pq.Error{Code: "0A000", Message: "cannot use window function"}) {
+		t.Errorf("IsCachedPlanChanged() got = true for other feature_not_supported error")
+	}
+	if IsCachedPlanChanged(nil) {
+		t.Errorf("IsCachedPlanChanged() got = true for nil error")
+	}
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	retryable := []error{
+		&pq.Error{Code: "40001"},
+		fmt.Errorf("exec failed: %w", &pq.Error{Code: "40P01"}),
+		&mysql.MySQLError{Number: 1213},
+		sqlite3.Error{Code: sqlite3.ErrBusy},
+		mssql.Error{Number: 1205},
+		fmt.Errorf("exec failed: %w", ErrInjectedFault),
+	}
+	for _, err := range retryable {
+		if !IsRetryableTxError(err) {
+			t.Errorf("IsRetryableTxError() got = false for %v", err)
+		}
+	}
+
+	permanent := []error{
+		nil,
+		&pq.Error{Code: "23505"},
+		&mysql.MySQLError{Number: 1062},
+		sqlite3.Error{Code: sqlite3.ErrConstraint},
+		mssql.Error{Number: 2627},
+		fmt.Errorf("connection refused"),
+	}
+	for _, err := range permanent {
+		if IsRetryableTxError(err) {
+			t.Errorf("IsRetryableTxError() got = true for %v", err)
+		}
+	}
+}
+
+func TestIsLockNotAvailable(t *testing.T) {
+	for _, err := range []error{&pq.Error{Code: "55P03"}, fmt.Errorf("query failed: %w", &mysql.MySQLError{Number: 3572}), mssql.Error{Number: 1222}} {
+		if !IsLockNotAvailable(err) {
+			t.Errorf("IsLockNotAvailable() got = false for %v", err)
+		}
+	}
+	for _, err := range []error{nil, &pq.Error{Code: "40P01"}, &mysql.MySQLError{Number: 1205}, mssql.Error{Number: 1205}} {
+		if IsLockNotAvailable(err) {
+			t.Errorf("IsLockNotAvailable() got = true for %v", err)
+		}
+	}
+}
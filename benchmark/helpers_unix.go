@@ -6,6 +6,7 @@ package benchmark
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -19,7 +20,7 @@ func (b *Benchmark) adjustFilenoUlimit() int {
 
 	err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rLimit)
 	if err != nil {
-		fmt.Println("Error Getting Rlimit ", err)
+		fmt.Fprintln(os.Stderr, "Error Getting Rlimit ", err)
 
 		return -1
 	}
@@ -29,14 +30,14 @@ func (b *Benchmark) adjustFilenoUlimit() int {
 
 	err = syscall.Setrlimit(syscall.RLIMIT_NOFILE, &rLimit)
 	if err != nil {
-		fmt.Println("Error Setting Rlimit ", err)
+		fmt.Fprintln(os.Stderr, "Error Setting Rlimit ", err)
 
 		return -1
 	}
 
 	err = syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rLimit)
 	if err != nil {
-		fmt.Println("Error Getting Rlimit ", err)
+		fmt.Fprintln(os.Stderr, "Error Getting Rlimit ", err)
 
 		return -1
 	}
@@ -0,0 +1,157 @@
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// statementErrors is the amount of failed DB statements since the process start, see StatementErrors()
+var statementErrors uint64
+
+// StatementErrors returns the amount of failed DB statements since the process start
+func StatementErrors() uint64 {
+	return atomic.LoadUint64(&statementErrors)
+}
+
+// testMetrics are the metrics of a single test, the loops counter is updated by the runners w/o locking
+type testMetrics struct {
+	loops  uint64 // atomic
+	errors uint64 // statement errors of the finished runs
+
+	metric      string
+	running     bool
+	runStart    time.Time
+	runLoops    uint64 // loops counter at the run start
+	errorsStart uint64 // StatementErrors() at the run start
+	rate        float64
+}
+
+// metricsServer exposes the running tests metrics in the Prometheus text format, see --metrics-addr
+type metricsServer struct {
+	server *http.Server
+	b      *Benchmark
+
+	lock  sync.Mutex
+	tests map[string]*testMetrics
+}
+
+// newMetricsServer starts serving the /metrics endpoint on given address
+func newMetricsServer(b *Benchmark, addr string) (*metricsServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &metricsServer{b: b, tests: make(map[string]*testMetrics)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", m.serve)
+	m.server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go m.server.Serve(listener) //nolint:errcheck
+
+	return m, nil
+}
+
+// start marks the beginning of a run of given test and returns its metrics
+func (m *metricsServer) start(test string, metric string) *testMetrics {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	t, exists := m.tests[test]
+	if !exists {
+		t = &testMetrics{}
+		m.tests[test] = t
+	}
+
+	t.metric = metric
+	t.running = true
+	t.runStart = time.Now()
+	t.runLoops = atomic.LoadUint64(&t.loops)
+	t.errorsStart = StatementErrors()
+
+	return t
+}
+
+// stop marks the end of the test run and keeps its final rate
+func (m *metricsServer) stop(t *testMetrics, rate float64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	t.running = false
+	t.rate = rate
+	t.errors += StatementErrors() - t.errorsStart
+}
+
+// serve writes the metrics of every test, the rate of a running test is the one of its current run so far
+func (m *metricsServer) serve(w http.ResponseWriter, _ *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	names := make([]string, 0, len(m.tests))
+	for name := range m.tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	fmt.Fprintf(w, "# HELP perfkit_loops_total Loops done by the test workers.\n# TYPE perfkit_loops_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "perfkit_loops_total{test=%q} %d\n", name, atomic.LoadUint64(&m.tests[name].loops))
+	}
+
+	fmt.Fprintf(w, "# HELP perfkit_rate Rate of the current or the last test run in the test metric units.\n# TYPE perfkit_rate gauge\n")
+	for _, name := range names {
+		t := m.tests[name]
+		rate := t.rate
+		if t.running {
+			if seconds := time.Since(t.runStart).Seconds(); seconds > 0 {
+				rate = m.b.GetRate(atomic.LoadUint64(&t.loops)-t.runLoops, seconds)
+			}
+		}
+		fmt.Fprintf(w, "perfkit_rate{test=%q,metric=%q} %g\n", name, t.metric, rate)
+	}
+
+	fmt.Fprintf(w, "# HELP perfkit_errors_total Failed DB statements of the test.\n# TYPE perfkit_errors_total counter\n")
+	for _, name := range names {
+		t := m.tests[name]
+		errors := t.errors
+		if t.running {
+			errors += StatementErrors() - t.errorsStart
+		}
+		fmt.Fprintf(w, "perfkit_errors_total{test=%q} %d\n", name, errors)
+	}
+
+	fmt.Fprintf(w, "# HELP perfkit_running Whether the test is running.\n# TYPE perfkit_running gauge\n")
+	for _, name := range names {
+		running := 0
+		if m.tests[name].running {
+			running = 1
+		}
+		fmt.Fprintf(w, "perfkit_running{test=%q} %d\n", name, running)
+	}
+}
+
+// close gracefully shuts the endpoint down
+func (m *metricsServer) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	m.server.Shutdown(ctx) //nolint:errcheck
+}
+
+// closeMetrics shuts the --metrics-addr endpoint down
+func (b *Benchmark) closeMetrics() {
+	if b.metrics == nil {
+		return
+	}
+	b.metrics.close()
+	b.metrics = nil
+}
@@ -0,0 +1,48 @@
+package benchmark
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMetricsServe(t *testing.T) {
+	b := New()
+	m := &metricsServer{b: b, tests: make(map[string]*testMetrics)}
+
+	tm := m.start("insert-light", "rows/sec")
+	atomic.AddUint64(&tm.loops, 10)
+	atomic.AddUint64(&statementErrors, 2)
+
+	rec := httptest.NewRecorder()
+	m.serve(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, want := range []string{
+		`perfkit_loops_total{test="insert-light"} 10`,
+		`perfkit_errors_total{test="insert-light"} 2`,
+		`perfkit_running{test="insert-light"} 1`,
+		"# TYPE perfkit_rate gauge",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("serve() output doesn't contain %q:\n%s", want, out)
+		}
+	}
+
+	m.stop(tm, 123)
+
+	rec = httptest.NewRecorder()
+	m.serve(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out = rec.Body.String()
+
+	for _, want := range []string{
+		`perfkit_rate{test="insert-light",metric="rows/sec"} 123`,
+		`perfkit_errors_total{test="insert-light"} 2`,
+		`perfkit_running{test="insert-light"} 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("serve() output after stop() doesn't contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package benchmark
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rawLatencyChunk is the amount of latencies a runner keeps locally before passing them to the shared writer
+const rawLatencyChunk = 4096
+
+// rawLatency is a single loop latency record, see --raw-latencies
+type rawLatency struct {
+	start   time.Time
+	latency time.Duration
+}
+
+// rawLatencyWriter streams every loop latency into a CSV file for offline analysis, see --raw-latencies
+type rawLatencyWriter struct {
+	lock sync.Mutex
+	f    *os.File
+	w    *bufio.Writer
+	line []byte
+}
+
+// newRawLatencyWriter creates given CSV file and writes the header
+func newRawLatencyWriter(path string) (*rawLatencyWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &rawLatencyWriter{f: f, w: bufio.NewWriterSize(f, 1<<20)}
+	if _, err = r.w.WriteString("test,worker,start_ns,latency_ns\n"); err != nil {
+		f.Close()
+
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// write appends the records of a single worker, it's called once per rawLatencyChunk loops, so the lock is not contended
+func (r *rawLatencyWriter) write(test string, workerID int, records []rawLatency) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, rec := range records {
+		r.line = append(r.line[:0], test...)
+		r.line = append(r.line, ',')
+		r.line = strconv.AppendInt(r.line, int64(workerID), 10)
+		r.line = append(r.line, ',')
+		r.line = strconv.AppendInt(r.line, rec.start.UnixNano(), 10)
+		r.line = append(r.line, ',')
+		r.line = strconv.AppendInt(r.line, int64(rec.latency), 10)
+		r.line = append(r.line, '\n')
+
+		if _, err := r.w.Write(r.line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flush writes the buffered records to the file
+func (r *rawLatencyWriter) flush() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.w.Flush()
+}
+
+// close flushes the buffered records and closes the file
+func (r *rawLatencyWriter) close() error {
+	if err := r.flush(); err != nil {
+		r.f.Close()
+
+		return err
+	}
+
+	return r.f.Close()
+}